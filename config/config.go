@@ -0,0 +1,525 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"maps"
+	"time"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// PathParameterValidator is a custom validation function for path parameters, registered by name and invoked
+// with the raw value for any path parameter that names it using the 'x-validator' extension. Return an error
+// to reject the value, the error message is used as the reason for the failure.
+type PathParameterValidator func(param *v3.Parameter, value string) error
+
+// ExampleFetcher fetches the external value of an example object (the URL of its 'externalValue'), so it can be
+// validated against its schema. The fetched value may be JSON or YAML.
+type ExampleFetcher interface {
+	FetchExample(externalValue string) ([]byte, error)
+}
+
+// ExampleFetcherFunc allows a function to be used as an ExampleFetcher.
+type ExampleFetcherFunc func(externalValue string) ([]byte, error)
+
+// FetchExample calls f(externalValue).
+func (f ExampleFetcherFunc) FetchExample(externalValue string) ([]byte, error) {
+	return f(externalValue)
+}
+
+// SchemaFailureVisitor is invoked with each schema validation failure, as it is discovered.
+type SchemaFailureVisitor func(failure *errors.SchemaValidationFailure)
+
+// ValidationStats describe the work done to validate a single request (or response), see WithMetricsHandler.
+type ValidationStats struct {
+	// Method and Path are the method and path of the request that was validated.
+	Method string
+	Path   string
+
+	// MatchedPath is the path from the specification that matched the request, empty if no path matched.
+	MatchedPath string
+
+	// PathCandidates is the number of paths that were compared against the request path.
+	PathCandidates int
+
+	// PathMatchDuration is how long it took to find the path of the request.
+	PathMatchDuration time.Duration
+
+	// ValidationDuration is how long it took to validate the parameters and bodies, after the path was found.
+	ValidationDuration time.Duration
+
+	// TotalDuration is how long the whole validation took.
+	TotalDuration time.Duration
+
+	// Valid is true if validation passed, and Errors is the number of validation errors that were returned.
+	Valid  bool
+	Errors int
+}
+
+// MetricsHandler is invoked with the stats of each validation, once the validation is complete.
+type MetricsHandler func(stats ValidationStats)
+
+// WarningHandler is invoked with each validation warning, such as a request for a deprecated operation.
+type WarningHandler func(warning *errors.ValidationWarning)
+
+// ParameterLocations is a set of parameter locations, which can be combined (e.g. PathLocation | QueryLocation).
+type ParameterLocations int
+
+const (
+	// PathLocation is the location of 'in: path' parameters.
+	PathLocation ParameterLocations = 1 << iota
+
+	// QueryLocation is the location of 'in: query' parameters.
+	QueryLocation
+
+	// HeaderLocation is the location of 'in: header' parameters.
+	HeaderLocation
+
+	// CookieLocation is the location of 'in: cookie' parameters.
+	CookieLocation
+
+	// AllLocations is every parameter location.
+	AllLocations = PathLocation | QueryLocation | HeaderLocation | CookieLocation
+)
+
+// ValidationOptions is a container for all the configuration that can be applied to the validators.
+type ValidationOptions struct {
+	// NormalizeDuplicateSlashes will collapse repeated slashes in a request path (e.g. /users//42 becomes /users/42)
+	// before the path is matched against the specification.
+	NormalizeDuplicateSlashes bool
+
+	// IgnoreTrailingSlash will match a request path with a trailing slash (e.g. /users/) against the path without
+	// one (e.g. /users), and a path declared with a trailing slash against a request path without one.
+	IgnoreTrailingSlash bool
+
+	// CaseInsensitivePaths will match the literal segments of request paths case-insensitively, so /Users/42
+	// matches /users/{id}. Parameter values are still validated exactly as they were sent.
+	CaseInsensitivePaths bool
+
+	// DisableServerStripping will match request paths exactly as they are sent, without stripping the base path of
+	// any server URL first. By default, server base paths are stripped.
+	DisableServerStripping bool
+
+	// RequestPathPrefix is prepended to the path of every request (e.g. /v1, so /users becomes /v1/users) before the
+	// path is matched against the specification. By default, there is no prefix.
+	RequestPathPrefix string
+
+	// AllowReservedPathParameters will match path parameters that allow reserved characters (with 'allowReserved'
+	// or an 'x-allow-reserved' extension) greedily, so their values can contain reserved characters, including '/'.
+	AllowReservedPathParameters bool
+
+	// RejectUntypedPathParameters will fail validation of path parameters whose schema does not define a type
+	// (or any allOf / oneOf / anyOf composition). By default, untyped path parameters match any value.
+	RejectUntypedPathParameters bool
+
+	// PathParameterValidators holds custom path parameter validators, keyed by the name used in the
+	// 'x-validator' extension of a parameter.
+	PathParameterValidators map[string]PathParameterValidator
+
+	// RejectUndeclaredRequestBody will fail validation of requests that send a body to an operation that does not
+	// define a requestBody. By default, such bodies are ignored, as some servers tolerate bodies on GET requests.
+	RejectUndeclaredRequestBody bool
+
+	// ValidateReadOnlyWriteOnly will fail request bodies that contain readOnly properties, and response bodies
+	// that contain writeOnly properties.
+	ValidateReadOnlyWriteOnly bool
+
+	// RelaxRequired will ignore 'required' keywords when validating request bodies, so partial objects are only
+	// validated against the schemas of the properties they contain.
+	RelaxRequired bool
+
+	// RelaxRequiredForPatch will ignore 'required' keywords when validating the request bodies of PATCH
+	// operations only, for APIs that use JSON Merge Patch semantics.
+	RelaxRequiredForPatch bool
+
+	// SchemaFailureVisitor is invoked with every schema validation failure as it is discovered, before the
+	// failures are collected into a ValidationError.
+	SchemaFailureVisitor SchemaFailureVisitor
+
+	// MaxRequestBodySize is the maximum number of bytes of a request body that will be read. Larger bodies fail
+	// validation, without being read in full. By default, this is DefaultMaxRequestBodySize, and zero (or less) means
+	// there is no limit.
+	MaxRequestBodySize int64
+
+	// BodyDiscriminator is a JSON pointer (such as /operationName) to a value in JSON request bodies, that selects
+	// the schema from the oneOf (or anyOf) of the body schema to validate the body against.
+	BodyDiscriminator string
+
+	// WarningHandler is invoked with every validation warning, warnings never fail validation.
+	WarningHandler WarningHandler
+
+	// MetricsHandler is invoked with the timings and counts of every validation, once it is complete.
+	MetricsHandler MetricsHandler
+
+	// IncludeAllErrors will report every error produced by the JSON schema validator, exactly as it was reported,
+	// rather than removing the errors that are noise, and splitting additionalProperties violations per property.
+	IncludeAllErrors bool
+
+	// MessageFormatters replace the default Message, Reason and HowToFix of validation errors. They are keyed by
+	// validation type, or by validation type and sub type (see WithMessageFormatter).
+	MessageFormatters map[string]errors.MessageFormatter
+
+	// ValidateDecimalFormat will validate numeric path parameters with a 'decimal' format as exact decimal strings,
+	// rather than converting them into a float64 (which loses precision).
+	ValidateDecimalFormat bool
+
+	// CaseInsensitiveCompoundLiterals will match the literal text of compound path segments (such as the '.json' of
+	// '{file}.json') case-insensitively. Parameter values are still validated exactly as they were sent.
+	CaseInsensitiveCompoundLiterals bool
+
+	// UseJSONNumber will decode the numbers of JSON payloads as json.Number, rather than float64, so integers
+	// that cannot be represented exactly by a float64 keep their precision when validated.
+	UseJSONNumber bool
+
+	// ExampleFetcher fetches the external values of examples, so they can be validated. By default, there is no
+	// fetcher, so external example values are never fetched.
+	ExampleFetcher ExampleFetcher
+
+	// PathSuggestions is the maximum number of similar specification paths to suggest, when a request path cannot
+	// be found. By default, no suggestions are made.
+	PathSuggestions int
+
+	// ValidateLocations are the locations of the parameters that are validated while a path is found (see
+	// parameters.FindPathAndValidate). By default, only path parameters are validated.
+	ValidateLocations ParameterLocations
+
+	// MethodExtension is the name of a path item extension (such as 'x-method') that maps method names to
+	// operations, which is consulted when a path item does not define an operation for the request method.
+	MethodExtension string
+
+	// StrictQueryNumbers will only accept numeric query parameter values that are written as JSON numbers, so a '+'
+	// sign or leading zeros (such as '+5' or '007') are rejected. By default, they are accepted as the number written.
+	StrictQueryNumbers bool
+
+	// Operations is an operation cache shared by every validator and path matcher created with the options, rather
+	// than each creating its own (see WithOperationCache).
+	Operations *helpers.OperationCache
+}
+
+// Option enables an 'options pattern' approach to configuring the validators.
+type Option func(*ValidationOptions)
+
+// DefaultMaxRequestBodySize is the maximum number of bytes of a request body that will be read, unless another limit
+// is set with WithMaxRequestBodySize.
+const DefaultMaxRequestBodySize int64 = 10 << 20
+
+// NewValidationOptions will create a new ValidationOptions instance, with all the supplied options applied.
+func NewValidationOptions(opts ...Option) *ValidationOptions {
+	o := &ValidationOptions{MaxRequestBodySize: DefaultMaxRequestBodySize}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// WithExistingOpts will copy all the values from an existing ValidationOptions instance. This is used to pass
+// options that have already been resolved down to other validators.
+func WithExistingOpts(options *ValidationOptions) Option {
+	return func(o *ValidationOptions) {
+		if options != nil {
+			*o = *options
+		}
+	}
+}
+
+// WithDuplicateSlashNormalization will collapse repeated slashes in request paths before they are matched, so
+// accidental double slashes (e.g. /users//42) don't cause a path to not be found.
+func WithDuplicateSlashNormalization() Option {
+	return func(o *ValidationOptions) {
+		o.NormalizeDuplicateSlashes = true
+	}
+}
+
+// WithTrailingSlash will ignore trailing slashes when request paths are matched, so /users/ matches a path of
+// /users (and /users matches a path of /users/). The root path '/' is never changed.
+func WithTrailingSlash() Option {
+	return func(o *ValidationOptions) {
+		o.IgnoreTrailingSlash = true
+	}
+}
+
+// WithCaseInsensitive will match the literal segments of request paths case-insensitively (including the literal
+// text of compound segments, see WithCaseInsensitiveCompoundLiterals). Server base paths are still matched exactly.
+func WithCaseInsensitive() Option {
+	return func(o *ValidationOptions) {
+		o.CaseInsensitivePaths = true
+		o.CaseInsensitiveCompoundLiterals = true
+	}
+}
+
+// WithServerStripping will enable or disable the stripping of server base paths from request paths before they are
+// matched. Stripping is enabled by default, so /api/users matches a path of /users when a server has a URL of /api.
+// Disable it when the paths of the specification already include the base path.
+func WithServerStripping(enabled bool) Option {
+	return func(o *ValidationOptions) {
+		o.DisableServerStripping = !enabled
+	}
+}
+
+// WithRequestPathPrefix will prepend a prefix to the path of every request before it is matched, which complements
+// server stripping. It suits specifications where every path shares a prefix (such as '/v1/users'), but requests
+// arrive without it, for example when a gateway routes on the prefix and strips it. The prefix is always added, so a
+// request that still has the prefix is matched with it twice.
+func WithRequestPathPrefix(prefix string) Option {
+	return func(o *ValidationOptions) {
+		o.RequestPathPrefix = prefix
+	}
+}
+
+// WithReservedPathParameters will allow the values of path parameters that set 'allowReserved' (or the
+// 'x-allow-reserved' extension) to contain reserved characters, including '/'. A path such as /files/{path} then
+// matches /files/docs/readme.md, with a path value of 'docs/readme.md'. Only parameters that fill a whole segment
+// of the path are matched this way.
+func WithReservedPathParameters() Option {
+	return func(o *ValidationOptions) {
+		o.AllowReservedPathParameters = true
+	}
+}
+
+// WithUntypedPathParameterRejection will report an error for path parameters that have a schema without a type,
+// rather than allowing any value to pass. Use this to catch under-specified contracts.
+func WithUntypedPathParameterRejection() Option {
+	return func(o *ValidationOptions) {
+		o.RejectUntypedPathParameters = true
+	}
+}
+
+// WithPathParameterValidator will register a custom validator for path parameters that define an 'x-validator'
+// extension with the supplied name. The validator runs in addition to the schema validation of the parameter.
+func WithPathParameterValidator(name string, validator PathParameterValidator) Option {
+	return func(o *ValidationOptions) {
+		// copy the registry, so options copied from an existing instance are not modified.
+		validators := maps.Clone(o.PathParameterValidators)
+		if validators == nil {
+			validators = make(map[string]PathParameterValidator)
+		}
+		validators[name] = validator
+		o.PathParameterValidators = validators
+	}
+}
+
+// WithUndeclaredRequestBodyRejection will report an error for requests that send a non-empty body to an operation
+// that does not define a requestBody (such as most GET and DELETE operations). This is strict, as some servers
+// tolerate bodies on GET requests, so it is not enabled by default.
+func WithUndeclaredRequestBodyRejection() Option {
+	return func(o *ValidationOptions) {
+		o.RejectUndeclaredRequestBody = true
+	}
+}
+
+// WithReadOnlyWriteOnlyValidation will flag readOnly properties sent in a request body, and writeOnly properties
+// returned in a response body.
+func WithReadOnlyWriteOnlyValidation() Option {
+	return func(o *ValidationOptions) {
+		o.ValidateReadOnlyWriteOnly = true
+	}
+}
+
+// WithRelaxedRequired will ignore 'required' keywords in request body schemas, for every request. Only the
+// properties that are present in a request body will be validated.
+func WithRelaxedRequired() Option {
+	return func(o *ValidationOptions) {
+		o.RelaxRequired = true
+	}
+}
+
+// WithPatchValidation will ignore 'required' keywords in request body schemas for PATCH requests, so partial
+// (merge patch) payloads are accepted, while every property that is sent is still validated.
+func WithPatchValidation() Option {
+	return func(o *ValidationOptions) {
+		o.RelaxRequiredForPatch = true
+	}
+}
+
+// WithPathSuggestions will attach up to count of the most similar specification paths (and their locations) to the
+// error returned when a request path cannot be found, to help identify the route that was intended.
+func WithPathSuggestions(count int) Option {
+	return func(o *ValidationOptions) {
+		o.PathSuggestions = count
+	}
+}
+
+// WithValidateLocations will set the locations of the parameters that are validated while a path is found (see
+// parameters.FindPathAndValidate), for example WithValidateLocations(PathLocation | QueryLocation | HeaderLocation).
+func WithValidateLocations(locations ParameterLocations) Option {
+	return func(o *ValidationOptions) {
+		o.ValidateLocations = locations
+	}
+}
+
+// WithMethodExtension will select the operation of a request from the named path item extension (such as
+// 'x-method'), when the path item does not define an operation for the request method. The extension maps method
+// names to operations, so specifications can define non-standard operations (see helpers.OperationCache).
+func WithMethodExtension(extension string) Option {
+	return func(o *ValidationOptions) {
+		o.MethodExtension = extension
+	}
+}
+
+// WithOperationCache will share an operation cache between the validators and path matchers of a document, so the
+// operations that are not part of its model (and the parameters rebuilt because of unresolved references) are only
+// built once. The cache must be created for the same document, with the same method extension. The validators of a
+// validator.Validator always share one cache.
+func WithOperationCache(operations *helpers.OperationCache) Option {
+	return func(o *ValidationOptions) {
+		o.Operations = operations
+	}
+}
+
+// OperationCacheFor returns the shared operation cache (see WithOperationCache), or if there is none, a new operation
+// cache for a document, using the method extension of the options.
+func (o *ValidationOptions) OperationCacheFor(document *v3.Document) *helpers.OperationCache {
+	if o.Operations != nil {
+		return o.Operations
+	}
+	return helpers.NewOperationCache(document, o.MethodExtension)
+}
+
+// WithStrictQueryNumbers will reject numeric query parameter values that are not written as JSON numbers. A value
+// may only have a '-' sign, and no leading zeros, so '-3', '0' and '0.5' are valid integers or numbers, but '+5' and
+// '007' are not. Without this option, signs and leading zeros are accepted, and the value is validated as the number
+// it represents, so '+5' and '007' are the integers 5 and 7.
+func WithStrictQueryNumbers() Option {
+	return func(o *ValidationOptions) {
+		o.StrictQueryNumbers = true
+	}
+}
+
+// WithDecimalFormatValidation will validate numeric path parameters with a 'decimal' format without converting them
+// into a float64. Values must be well-formed decimals, with no more decimal places than an 'x-scale' extension
+// allows, and multipleOf, minimum, maximum and enum keywords are checked exactly.
+func WithDecimalFormatValidation() Option {
+	return func(o *ValidationOptions) {
+		o.ValidateDecimalFormat = true
+	}
+}
+
+// WithCaseInsensitiveCompoundLiterals will match the literal text of compound path segments case-insensitively, so a
+// template of '{file}.json' accepts 'report.JSON'. Only the literal text is affected, the parameter values (such as
+// 'report') are extracted as they were sent, and validated against their schemas without any change of case.
+func WithCaseInsensitiveCompoundLiterals() Option {
+	return func(o *ValidationOptions) {
+		o.CaseInsensitiveCompoundLiterals = true
+	}
+}
+
+// WithJSONNumber will decode the numbers of JSON payloads as json.Number (see json.Decoder.UseNumber), rather than
+// float64, before they are validated. Large integers (such as 19 digit int64 values) are validated exactly, instead
+// of being rounded to the nearest float64 first.
+func WithJSONNumber() Option {
+	return func(o *ValidationOptions) {
+		o.UseJSONNumber = true
+	}
+}
+
+// WithExampleFetcher will use the supplied fetcher to fetch the external values of examples, so they can be validated
+// against their schemas. Fetching is disabled unless a fetcher is supplied, as fetching an arbitrary URL named in a
+// specification is not safe for every environment.
+func WithExampleFetcher(fetcher ExampleFetcher) Option {
+	return func(o *ValidationOptions) {
+		o.ExampleFetcher = fetcher
+	}
+}
+
+// WithAllErrors will report every error produced when validating a schema (and request / response bodies) exactly as
+// the JSON schema validator reported it, without any filtering. This is useful when debugging a schema.
+func WithAllErrors() Option {
+	return func(o *ValidationOptions) {
+		o.IncludeAllErrors = true
+	}
+}
+
+// WithMessageFormatter will format the Message, Reason and HowToFix of every validation error with the supplied
+// validation type and sub type (for example helpers.ParameterValidation and helpers.ParameterValidationQuery). An
+// empty sub type formats every error of the validation type, and an empty validation type formats every error that
+// has no more specific formatter. The structured fields of an error are never changed.
+func WithMessageFormatter(validationType, validationSubType string, formatter errors.MessageFormatter) Option {
+	return func(o *ValidationOptions) {
+		// copy the registry, so options copied from an existing instance are not modified.
+		formatters := maps.Clone(o.MessageFormatters)
+		if formatters == nil {
+			formatters = make(map[string]errors.MessageFormatter)
+		}
+		formatters[messageFormatterKey(validationType, validationSubType)] = formatter
+		o.MessageFormatters = formatters
+	}
+}
+
+// MessageFormatterFor returns the most specific message formatter registered for a validation error, or nil if no
+// formatter applies to it.
+func (o *ValidationOptions) MessageFormatterFor(validationError *errors.ValidationError) errors.MessageFormatter {
+	if len(o.MessageFormatters) == 0 {
+		return nil
+	}
+	for _, key := range []string{
+		messageFormatterKey(validationError.ValidationType, validationError.ValidationSubType),
+		messageFormatterKey(validationError.ValidationType, ""),
+		messageFormatterKey("", ""),
+	} {
+		if formatter := o.MessageFormatters[key]; formatter != nil {
+			return formatter
+		}
+	}
+	return nil
+}
+
+func messageFormatterKey(validationType, validationSubType string) string {
+	if validationSubType == "" {
+		return validationType
+	}
+	return validationType + "/" + validationSubType
+}
+
+// WithBodyDiscriminator will validate JSON request bodies against a single schema from the oneOf (or anyOf) of their
+// schema, selected by the value at a JSON pointer in the body (such as /operationName), rather than against every
+// schema. This suits specifications that route every operation through one path, such as /graphql, and tell the
+// operations apart by a field of the body. A schema is selected by the discriminator mapping of the body schema, or by
+// defining the property at the pointer with a const (or enum) of the value.
+func WithBodyDiscriminator(pointer string) Option {
+	return func(o *ValidationOptions) {
+		o.BodyDiscriminator = pointer
+	}
+}
+
+// WithMaxRequestBodySize will fail the validation of request bodies that are larger than limit bytes. Bodies are read
+// until they end, whether or not they have a Content-Length (chunked bodies have none), so without a limit a huge
+// body is read into memory in full. A body that is too large is not read beyond the limit, and it is replaced on the
+// request, so it can still be read in full later. By default, the limit is DefaultMaxRequestBodySize, and a limit of
+// zero (or less) removes it.
+func WithMaxRequestBodySize(limit int64) Option {
+	return func(o *ValidationOptions) {
+		o.MaxRequestBodySize = limit
+	}
+}
+
+// WithWarningHandler will invoke the handler with every validation warning, for example when a request is made to an
+// operation that is marked 'deprecated', so a gateway can add a Deprecation header, or log the usage. Warnings
+// never fail validation.
+func WithWarningHandler(handler WarningHandler) Option {
+	return func(o *ValidationOptions) {
+		o.WarningHandler = handler
+	}
+}
+
+// WithMetricsHandler will invoke the handler after every request (or response) is validated, with how long the
+// path took to match, how many paths were compared, and how long validation took. Use it to find slow specifications
+// in production, without profiling.
+func WithMetricsHandler(handler MetricsHandler) Option {
+	return func(o *ValidationOptions) {
+		o.MetricsHandler = handler
+	}
+}
+
+// WithSchemaFailureVisitor will invoke the visitor with every schema validation failure as it is discovered, so
+// failures can be streamed into a custom reporting pipeline. The failures are still returned as normal.
+func WithSchemaFailureVisitor(visitor SchemaFailureVisitor) Option {
+	return func(o *ValidationOptions) {
+		o.SchemaFailureVisitor = visitor
+	}
+}
@@ -0,0 +1,6 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+// Package config contains the options that can be used to configure how the validators behave. Options are
+// supplied using the functional options pattern, everything is disabled by default to keep strict semantics.
+package config
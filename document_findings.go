@@ -0,0 +1,116 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package validator
+
+import (
+	"slices"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/parameters"
+	"github.com/pb33f/libopenapi-validator/paths"
+	"github.com/pb33f/libopenapi-validator/schema_validation"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// FindingSeverity is how serious a DocumentFinding is.
+type FindingSeverity string
+
+const (
+	// SeverityError is a finding that breaks validation, for example a path parameter that can never be validated,
+	// or a default value that fails its own schema.
+	SeverityError FindingSeverity = "error"
+
+	// SeverityWarning is a finding that is often intended, but is worth confirming, for example two paths that match
+	// some of the same requests.
+	SeverityWarning FindingSeverity = "warning"
+)
+
+// DocumentFinding is a problem with the internal consistency of a document, found by ValidateDocument. The
+// validation error describes the problem, and where it is located in the specification.
+type DocumentFinding struct {
+	// Severity is how serious the finding is.
+	Severity FindingSeverity `json:"severity" yaml:"severity"`
+
+	*errors.ValidationError `yaml:",inline"`
+}
+
+// ValidateDocument will check a document for internal consistency, without any requests, so a contract can be
+// checked in CI or an editor before it is used for validation. It is a single entry point for the smaller checks
+// of the validator, and every finding they return is collected, in this order:
+//
+//   - path templates against the path parameters declared for them (see parameters.ValidatePathTemplates)
+//   - the examples of parameters, against the parameter schema (see parameters.ParameterExamples)
+//   - the defaults and examples of schemas and media types (see schema_validation.ValidateDocumentExamples)
+//   - the enum values of schemas (see schema_validation.ValidateDocumentEnumValues)
+//   - paths that are ambiguous, and can never be matched (see paths.DetectAmbiguousPaths)
+//   - paths that overlap, and match some of the same requests (see paths.DetectOverlappingPaths)
+//
+// Overlapping paths are often intended, so they are reported with SeverityWarning, every other finding is reported
+// with SeverityError. A finding that cannot be located in the specification has a SpecLine and SpecCol of -1. This
+// is not the same as Validator.ValidateDocument, which validates a document against the OpenAPI specification itself.
+func ValidateDocument(document *v3.Document, opts ...config.Option) []*DocumentFinding {
+	if document == nil {
+		return nil
+	}
+	var findings []*DocumentFinding
+	add := func(severity FindingSeverity, validationErrors ...*errors.ValidationError) {
+		for _, validationError := range validationErrors {
+			findings = append(findings, &DocumentFinding{Severity: severity, ValidationError: validationError})
+		}
+	}
+
+	add(SeverityError, parameters.ValidatePathTemplates(document)...)
+	add(SeverityError, parameterExampleErrors(document)...)
+	_, exampleErrors := schema_validation.ValidateDocumentExamples(document, opts...)
+	add(SeverityError, exampleErrors...)
+	_, enumErrors := schema_validation.ValidateDocumentEnumValues(document)
+	add(SeverityError, enumErrors...)
+	for _, ambiguous := range paths.DetectAmbiguousPaths(document) {
+		add(SeverityError, errors.PathAmbiguous(ambiguous.Second.Path, ambiguous.First.Path,
+			ambiguous.Second.Line, ambiguous.Second.Column, ambiguous.First.Line))
+	}
+	for _, overlapping := range paths.DetectOverlappingPaths(document) {
+		add(SeverityWarning, errors.PathOverlapping(overlapping.Second.Path, overlapping.First.Path,
+			overlapping.Second.Line, overlapping.Second.Column, overlapping.First.Line))
+	}
+	return findings
+}
+
+// parameterExampleErrors returns an error for every example of a path item or operation parameter that fails
+// validation against the parameter schema. A parameter shared by several operations is only checked once.
+func parameterExampleErrors(document *v3.Document) []*errors.ValidationError {
+	if document.Paths == nil {
+		return nil
+	}
+	var validationErrors []*errors.ValidationError
+	var checked []*v3.Parameter
+	check := func(params []*v3.Parameter) {
+		for _, param := range params {
+			if param == nil || slices.Contains(checked, param) {
+				continue
+			}
+			checked = append(checked, param)
+			for _, example := range parameters.ParameterExamples(param) {
+				if example.Valid {
+					continue
+				}
+				node := param.Example
+				if example.Name != "" {
+					node = param.Examples.GetOrZero(example.Name).Value
+				}
+				validationErrors = append(validationErrors,
+					errors.ParameterExampleInvalid(param, example.Name, example.Value, node, example.Errors))
+			}
+		}
+	}
+	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
+		check(pair.Value().Parameters)
+		for op := orderedmap.First(pair.Value().GetOperations()); op != nil; op = op.Next() {
+			check(op.Value().Parameters)
+		}
+	}
+	return validationErrors
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"fmt"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// PathAmbiguous is returned for a path template that matches exactly the same request paths as a path defined
+// before it, so it can never be matched. The line and column are the location of the path that cannot be matched.
+func PathAmbiguous(path, firstPath string, line, col, firstLine int) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidationPath,
+		ValidationSubType: helpers.PathAmbiguous,
+		Message:           fmt.Sprintf("Path '%s' is ambiguous with path '%s'", path, firstPath),
+		Reason: fmt.Sprintf("The path '%s' matches exactly the same request paths as '%s', which is defined "+
+			"before it, so it can never be matched", path, firstPath),
+		SpecLine: line,
+		SpecCol:  col,
+		SpecPath: path,
+		HowToFix: fmt.Sprintf(HowToFixAmbiguousPath, path, firstPath, firstLine),
+	}
+}
+
+// PathOverlapping is returned for a path template that matches some of the same request paths as a path defined
+// before it, where a literal segment of one path is matched by a template segment of the other. The line and column
+// are the location of the path defined second.
+func PathOverlapping(path, firstPath string, line, col, firstLine int) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidationPath,
+		ValidationSubType: helpers.PathOverlapping,
+		Message:           fmt.Sprintf("Path '%s' overlaps with path '%s'", path, firstPath),
+		Reason: fmt.Sprintf("The path '%s' matches some of the same request paths as '%s', which is defined "+
+			"before it, so those requests are matched by '%s'", path, firstPath, firstPath),
+		SpecLine: line,
+		SpecCol:  col,
+		SpecPath: path,
+		HowToFix: fmt.Sprintf(HowToFixOverlappingPath, path, firstPath, firstLine),
+	}
+}
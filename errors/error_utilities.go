@@ -14,3 +14,34 @@ func PopulateValidationErrors(validationErrors []*ValidationError, request *http
 		validationError.RequestPath = request.URL.Path
 	}
 }
+
+// SetValidationDirection sets the Direction of every validation error that does not already have one, to either
+// helpers.RequestDirection or helpers.ResponseDirection. Errors that were found validating a request while
+// validating a response (such as the request path not being found) keep the direction they were given.
+func SetValidationDirection(validationErrors []*ValidationError, direction string) {
+	for _, validationError := range validationErrors {
+		if validationError != nil && validationError.Direction == "" {
+			validationError.Direction = direction
+		}
+	}
+}
+
+// MessageFormatter formats the Message, Reason and HowToFix of a ValidationError, for localized or branded output.
+// It receives the error with the default messages, and all the structured fields populated.
+type MessageFormatter func(validationError *ValidationError) (message, reason, howToFix string)
+
+// FormatValidationErrors mutates the provided validation errors, replacing the Message, Reason and HowToFix with the
+// output of the formatter that lookup returns for each error (a nil formatter leaves the error as it is). No other
+// fields are changed. Errors are only ever formatted once, so errors passed up through several validators are
+// not formatted again.
+func FormatValidationErrors(validationErrors []*ValidationError, lookup func(*ValidationError) MessageFormatter) {
+	for _, validationError := range validationErrors {
+		if validationError == nil || validationError.formatted {
+			continue
+		}
+		if formatter := lookup(validationError); formatter != nil {
+			validationError.Message, validationError.Reason, validationError.HowToFix = formatter(validationError)
+			validationError.formatted = true
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"gopkg.in/yaml.v3"
 )
 
 func IncorrectFormEncoding(param *v3.Parameter, qp *helpers.QueryParam, i int) *ValidationError {
@@ -100,6 +101,19 @@ func HeaderParameterMissing(param *v3.Parameter) *ValidationError {
 	}
 }
 
+func CookieParameterMissing(param *v3.Parameter) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationCookie,
+		Message:           fmt.Sprintf("Cookie parameter '%s' is missing", param.Name),
+		Reason: fmt.Sprintf("The cookie parameter '%s' is defined as being required, "+
+			"however it's missing from the requests", param.Name),
+		SpecLine: param.GoLow().Required.KeyNode.Line,
+		SpecCol:  param.GoLow().Required.KeyNode.Column,
+		HowToFix: HowToFixMissingValue,
+	}
+}
+
 func HeaderParameterCannotBeDecoded(param *v3.Parameter, val string) *ValidationError {
 	return &ValidationError{
 		ValidationType:    helpers.ParameterValidation,
@@ -234,6 +248,20 @@ func InvalidQueryParamNumber(param *v3.Parameter, ef string, sch *base.Schema) *
 	}
 }
 
+func IncorrectQueryParamNumberFormat(param *v3.Parameter, ef string, sch *base.Schema) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationQuery,
+		Message:           fmt.Sprintf("Query parameter '%s' is not a strictly formatted number", param.Name),
+		Reason: fmt.Sprintf("The query parameter '%s' is defined as being a number, "+
+			"however the value '%s' has a '+' sign or leading zeros, which are not allowed", param.Name, ef),
+		SpecLine: param.GoLow().Schema.KeyNode.Line,
+		SpecCol:  param.GoLow().Schema.KeyNode.Column,
+		Context:  sch,
+		HowToFix: fmt.Sprintf(HowToFixParamStrictNumber, ef),
+	}
+}
+
 func IncorrectQueryParamEnum(param *v3.Parameter, ef string, sch *base.Schema) *ValidationError {
 	var enums []string
 	for i := range sch.Enum {
@@ -440,6 +468,64 @@ func IncorrectPathParamNumber(param *v3.Parameter, item string, sch *base.Schema
 	}
 }
 
+func IncorrectPathParamDecimal(param *v3.Parameter, item string, sch *base.Schema) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationPath,
+		Message:           fmt.Sprintf("Path parameter '%s' is not a valid decimal", param.Name),
+		Reason: fmt.Sprintf("The path parameter '%s' is defined as being a decimal, "+
+			"however the value '%s' is not a well-formed decimal", param.Name, item),
+		SpecLine: param.GoLow().Schema.KeyNode.Line,
+		SpecCol:  param.GoLow().Schema.KeyNode.Column,
+		Context:  sch,
+		HowToFix: fmt.Sprintf(HowToFixParamInvalidDecimal, item),
+	}
+}
+
+func PathParamDecimalConstraint(param *v3.Parameter, item string, sch *base.Schema,
+	keyword, limit, howToFix string) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationPath,
+		Message:           fmt.Sprintf("Path parameter '%s' failed decimal '%s' validation", param.Name, keyword),
+		Reason: fmt.Sprintf("The path parameter '%s' is defined as being a decimal with '%s' of '%s', "+
+			"however the value '%s' does not satisfy it", param.Name, keyword, limit, item),
+		SpecLine: param.GoLow().Schema.KeyNode.Line,
+		SpecCol:  param.GoLow().Schema.KeyNode.Column,
+		Context:  sch,
+		HowToFix: howToFix,
+	}
+}
+
+func IncorrectPathParamInteger(param *v3.Parameter, item string, sch *base.Schema) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationPath,
+		Message:           fmt.Sprintf("Path parameter '%s' is not a valid integer", param.Name),
+		Reason: fmt.Sprintf("The path parameter '%s' is defined as being an integer, "+
+			"however the value '%s' is a decimal (expected integer, got decimal)", param.Name, item),
+		SpecLine: param.GoLow().Schema.KeyNode.Line,
+		SpecCol:  param.GoLow().Schema.KeyNode.Column,
+		Context:  sch,
+		HowToFix: fmt.Sprintf(HowToFixParamInvalidInteger, item),
+	}
+}
+
+func IncorrectPathParamArrayInteger(
+	param *v3.Parameter, item string, sch *base.Schema, itemsSchema *base.Schema) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationPath,
+		Message:           fmt.Sprintf("Path array parameter '%s' is not a valid integer", param.Name),
+		Reason: fmt.Sprintf("The path parameter (which is an array) '%s' is defined as being an integer, "+
+			"however the value '%s' is a decimal (expected integer, got decimal)", param.Name, item),
+		SpecLine: sch.Items.A.GoLow().Schema().Type.KeyNode.Line,
+		SpecCol:  sch.Items.A.GoLow().Schema().Type.KeyNode.Column,
+		Context:  itemsSchema,
+		HowToFix: fmt.Sprintf(HowToFixParamInvalidInteger, item),
+	}
+}
+
 func IncorrectPathParamArrayNumber(
 	param *v3.Parameter, item string, sch *base.Schema, itemsSchema *base.Schema) *ValidationError {
 	return &ValidationError{
@@ -470,6 +556,139 @@ func IncorrectPathParamArrayBoolean(
 	}
 }
 
+func PathParameterMissingType(param *v3.Parameter, item string, sch *base.Schema) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationPath,
+		Message:           fmt.Sprintf("Path parameter '%s' has no schema type", param.Name),
+		Reason: fmt.Sprintf("The path parameter '%s' does not define a schema type, "+
+			"so the value '%s' cannot be validated", param.Name, item),
+		SpecLine: param.GoLow().Schema.KeyNode.Line,
+		SpecCol:  param.GoLow().Schema.KeyNode.Column,
+		Context:  sch,
+		HowToFix: HowToFixParamMissingType,
+	}
+}
+
+func PathParameterCustomValidationFailed(param *v3.Parameter, validator, item string, err error) *ValidationError {
+	line, col := -1, -1
+	var ext *yaml.Node
+	if param.Extensions != nil {
+		ext = param.Extensions.GetOrZero(helpers.XValidator)
+	}
+	if ext != nil {
+		line, col = ext.Line, ext.Column
+	} else if low := param.GoLow(); low != nil && low.Schema.KeyNode != nil {
+		line, col = low.Schema.KeyNode.Line, low.Schema.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationPath,
+		Message:           fmt.Sprintf("Path parameter '%s' failed '%s' validation", param.Name, validator),
+		Reason: fmt.Sprintf("The path parameter '%s' value '%s' was rejected by the '%s' validator: %s",
+			param.Name, item, validator, err.Error()),
+		SpecLine: line,
+		SpecCol:  col,
+		HowToFix: fmt.Sprintf(HowToFixParamCustomValidation, item, validator),
+	}
+}
+
+func PathParameterUndeclared(pathItem *v3.PathItem, name, path string) *ValidationError {
+	line, col := -1, -1
+	if low := pathItem.GoLow(); low != nil {
+		if low.KeyNode != nil {
+			line, col = low.KeyNode.Line, low.KeyNode.Column
+		} else if low.RootNode != nil {
+			line, col = low.RootNode.Line, low.RootNode.Column
+		}
+	}
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationPath,
+		Message:           fmt.Sprintf("Path parameter '%s' is not declared", name),
+		Reason: fmt.Sprintf("The path '%s' contains the template variable '%s', "+
+			"however there is no path parameter declared with that name", path, name),
+		SpecLine: line,
+		SpecCol:  col,
+		HowToFix: fmt.Sprintf(HowToFixParamUndeclared, name),
+	}
+}
+
+func PathParameterNotInTemplate(param *v3.Parameter, path string) *ValidationError {
+	line, col := -1, -1
+	if low := param.GoLow(); low != nil && low.Name.KeyNode != nil {
+		line, col = low.Name.KeyNode.Line, low.Name.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationPath,
+		Message:           fmt.Sprintf("Path parameter '%s' is not part of the path template", param.Name),
+		Reason: fmt.Sprintf("The path parameter '%s' is declared for the path '%s', "+
+			"however the path does not contain a template variable with that name", param.Name, path),
+		SpecLine: line,
+		SpecCol:  col,
+		SpecPath: path,
+		HowToFix: fmt.Sprintf(HowToFixParamNotInTemplate, param.Name, param.Name),
+	}
+}
+
+func ParameterExampleInvalid(param *v3.Parameter, name, value string, node *yaml.Node,
+	errs []*ValidationError) *ValidationError {
+	line, col := -1, -1
+	if node != nil {
+		line, col = node.Line, node.Column
+	}
+	example := "example"
+	if name != "" {
+		example = fmt.Sprintf("example '%s'", name)
+	}
+	var failures []*SchemaValidationFailure
+	var reasons []string
+	for _, e := range errs {
+		failures = append(failures, e.SchemaValidationErrors...)
+		reasons = append(reasons, e.Reason)
+	}
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.SchemaExampleInvalid,
+		Message:           fmt.Sprintf("The %s of %s parameter '%s' is not valid for its schema", example, param.In, param.Name),
+		Reason: fmt.Sprintf("The %s value '%s' of the %s parameter '%s' does not pass validation: %s",
+			example, value, param.In, param.Name, strings.Join(reasons, "; ")),
+		SpecLine:               line,
+		SpecCol:                col,
+		SchemaValidationErrors: failures,
+		HowToFix:               fmt.Sprintf(HowToFixParamInvalidExample, value),
+	}
+}
+
+func ParameterReferenceUnresolved(reference *yaml.Node) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterRefUnresolved,
+		Message:           fmt.Sprintf("Parameter reference '%s' cannot be resolved", reference.Value),
+		Reason: fmt.Sprintf("The parameter reference '%s' cannot be found in the specification, "+
+			"so the parameter it refers to cannot be validated", reference.Value),
+		SpecLine: reference.Line,
+		SpecCol:  reference.Column,
+		HowToFix: fmt.Sprintf(HowToFixParamUnresolvedRef, reference.Value),
+	}
+}
+
+func PathParameterContainsSlash(param *v3.Parameter, value string) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationPath,
+		Message:           fmt.Sprintf("Path parameter '%s' cannot contain a '/'", param.Name),
+		Reason: fmt.Sprintf("The path parameter '%s' is a single path segment, however the value '%s' contains "+
+			"a slash (raw, or encoded as '%s'), which is only allowed when the parameter allows reserved characters",
+			param.Name, value, helpers.EncodedSlash),
+		SpecLine: param.GoLow().Name.KeyNode.Line,
+		SpecCol:  param.GoLow().Name.KeyNode.Column,
+		Context:  param,
+		HowToFix: fmt.Sprintf(HowToFixParamSlash, value),
+	}
+}
+
 func PathParameterMissing(param *v3.Parameter) *ValidationError {
 	return &ValidationError{
 		ValidationType:    helpers.ParameterValidation,
@@ -482,3 +701,47 @@ func PathParameterMissing(param *v3.Parameter) *ValidationError {
 		HowToFix: HowToFixMissingValue,
 	}
 }
+
+func ParameterBindTargetInvalid(target any) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterBinding,
+		Message:           fmt.Sprintf("Parameters cannot be bound to '%T'", target),
+		Reason: fmt.Sprintf("The target that parameters are bound to must be a non-nil pointer to a struct, "+
+			"however it is '%T'", target),
+		SpecLine: 1,
+		SpecCol:  0,
+		HowToFix: HowToFixParamBindTarget,
+	}
+}
+
+func ParameterBindFailed(param *v3.Parameter, field string, value string, err error) *ValidationError {
+	line, col := 1, 0
+	if low := param.GoLow(); low != nil && low.Name.KeyNode != nil {
+		line, col = low.Name.KeyNode.Line, low.Name.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterBinding,
+		Message:           fmt.Sprintf("Parameter '%s' cannot be bound to the field '%s'", param.Name, field),
+		Reason: fmt.Sprintf("The %s parameter '%s' has the value '%s', which cannot be bound to the field '%s': %s",
+			param.In, param.Name, value, field, err.Error()),
+		SpecLine: line,
+		SpecCol:  col,
+		Context:  param,
+		HowToFix: fmt.Sprintf(HowToFixParamBindField, field),
+	}
+}
+
+func ParameterBindUndefined(field string, name string, location string) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterBinding,
+		Message:           fmt.Sprintf("Parameter '%s' of the field '%s' is not defined", name, field),
+		Reason: fmt.Sprintf("The field '%s' is bound to the %s parameter '%s', however the operation "+
+			"does not define it", field, location, name),
+		SpecLine: 1,
+		SpecCol:  0,
+		HowToFix: fmt.Sprintf(HowToFixParamBindUndefined, location, name, field),
+	}
+}
@@ -7,11 +7,34 @@ const (
 	HowToFixReservedValues string = "parameter values need to URL Encoded to ensure reserved " +
 		"values are correctly encoded, for example: '%s'"
 	HowToFixParamInvalidNumber                      string = "Convert the value '%s' into a number"
+	HowToFixParamStrictNumber                       string = "Remove the '+' sign and any leading zeros from the value '%s'"
 	HowToFixParamInvalidString                      string = "Convert the value '%s' into a string (cannot start with a number, or be a floating point)"
 	HowToFixParamInvalidBoolean                     string = "Convert the value '%s' into a true/false value"
+	HowToFixParamInvalidInteger                     string = "Convert the value '%s' into an integer (a whole number, without a decimal point)"
+	HowToFixParamInvalidPattern                     string = "Change the value '%s' so it matches the pattern '%s'"
+	HowToFixParamInvalidMinLength                   string = "Change the value '%s' so it is at least %d characters long"
+	HowToFixParamInvalidMaxLength                   string = "Change the value '%s' so it is no more than %d characters long"
+	HowToFixParamInvalidMinimum                     string = "Change the value '%s' so it is greater than or equal to %v"
+	HowToFixParamInvalidMaximum                     string = "Change the value '%s' so it is less than or equal to %v"
+	HowToFixParamInvalidExclusiveMinimum            string = "Change the value '%s' so it is greater than %v"
+	HowToFixParamInvalidExclusiveMaximum            string = "Change the value '%s' so it is less than %v"
+	HowToFixParamInvalidMultipleOf                  string = "Change the value '%s' so it is a multiple of %v"
+	HowToFixParamInvalidFormat                      string = "Change the value '%s' so it is a valid '%s'"
 	HowToFixParamInvalidEnum                        string = "Instead of '%s', use one of the allowed values: '%s'"
+	HowToFixParamInvalidDecimal                     string = "Convert the value '%s' into a decimal (digits, with an optional sign and decimal point, but no exponent)"
+	HowToFixParamDecimalScale                       string = "Change the value '%s' so it has no more than %d decimal places"
 	HowToFixParamInvalidFormEncode                  string = "Use a form style encoding for parameter values, for example: '%s'"
 	HowToFixInvalidSchema                           string = "Ensure that the object being submitted, matches the schema correctly"
+	HowToFixParamCustomValidation                   string = "Change the value '%s' so it satisfies the '%s' validator"
+	HowToFixParamUndeclared                         string = "Declare a parameter named '%s' (with 'in: path') for the path in the specification, or remove it from the path template"
+	HowToFixParamUnresolvedRef                      string = "Define the parameter '%s' refers to in the specification (for example in 'components.parameters'), or correct the reference"
+	HowToFixParamMissingType                        string = "Define a 'type' for the parameter schema in the specification, so values can be validated"
+	HowToFixParamSlash                              string = "Remove the '/' from the value '%s', or set 'allowReserved' on the parameter in the specification to allow it"
+	HowToFixParamNotInTemplate                      string = "Add '{%s}' to the path template, or remove the path parameter '%s' from the specification"
+	HowToFixParamInvalidExample                     string = "Change the example '%s' so it is valid for the parameter schema, or remove it"
+	HowToFixParamBindTarget                         string = "Bind parameters to a pointer to a struct, for example: '&params'"
+	HowToFixParamBindField                          string = "Change the type of the field '%s' so it can hold a value of the parameter schema"
+	HowToFixParamBindUndefined                      string = "Define a %s parameter named '%s' for the operation, or correct the 'openapi' tag of the field '%s'"
 	HowToFixParamInvalidSpaceDelimitedObjectExplode string = "When using 'explode' with space delimited parameters, " +
 		"they should be separated by spaces. For example: '%s'"
 	HowToFixParamInvalidPipeDelimitedObjectExplode string = "When using 'explode' with pipe delimited parameters, " +
@@ -21,9 +44,32 @@ const (
 	HowToFixInvalidJSON         string = "The JSON submitted is invalid, please check the syntax"
 	HowToFixDecodingError              = "The object can't be decoded, so make sure it's being encoded correctly according to the spec."
 	HowToFixInvalidContentType         = "The content type is invalid, Use one of the %d supported types for this operation: %s"
+	HowToFixNotAcceptable              = "Send an 'Accept' header that allows one of the %d response content types for this operation: %s"
 	HowToFixInvalidResponseCode        = "The service is responding with a code that is not defined in the spec, fix the service or add the code to the specification"
 	HowToFixInvalidEncoding            = "Ensure the correct encoding has been used on the object"
 	HowToFixMissingValue               = "Ensure the value has been set"
+	HowToFixMissingRequestBody         = "Ensure a request body has been sent, the operation defines the request body as required"
+	HowToFixUndeclaredBody             = "Remove the request body, or define a requestBody for the operation in the contract"
+	HowToFixRequestBodyTooLarge        = "Send a request body that is no larger than %d bytes"
+	HowToFixBodyDiscriminator          = "Set '%s' of the request body to a value that selects one of the request body schemas"
 	HowToFixPath                       = "Check the path is correct, and check that the correct HTTP method has been used (e.g. GET, POST, PUT, DELETE)"
 	HowToFixPathMethod                 = "Add the missing operation to the contract for the path"
+	HowToFixPathMethodDefined          = "Check the correct HTTP method has been used, the path defines: %s"
+	HowToFixOperationId                = "Check the operationId '%s' is correct (operation IDs are case-sensitive), or add it to an operation in the contract"
+	HowToFixOperationIdMethod          = "Send a %s request to validate it against the operation '%s'"
+	HowToFixOperationIdPath            = "Send a request with a path that matches '%s' to validate it against the operation '%s'"
+	HowToFixSchemaRender               = "Check every $ref within the schema can be resolved, and that the schema is built from a valid document"
+	HowToFixInvalidEnumValue           = "Change the enum value so it matches the type, format and bounds of the schema, or remove it"
+	HowToFixInvalidDefault             = "Change the default value so it is valid for its schema, or remove it"
+	HowToFixInvalidExample             = "Change the example so it is valid for its schema, or remove it"
+	HowToFixAmbiguousPath              = "Remove the path '%s', or change its literal segments, only '%s' (line %d) can ever be matched"
+	HowToFixOverlappingPath            = "Check the path '%s' is meant to be defined after '%s' (line %d), requests that match both are matched by the path defined first"
+	HowToFixDeprecatedOperation        = "Migrate to the operation that replaces it, deprecated operations may be removed in a future version"
+	HowToFixDefaultResponse            = "Declare a response for the status code '%d' in the specification, if the service is expected to return it"
+	HowToFixPathSuggestion             = "Check the path is correct, the most similar path in the specification is '%s' (line %d)"
+	HowToFixMissingSchema              = "Check the schema name is correct, or add the schema to 'components.schemas' in the contract"
+	HowToFixExampleFetching            = "Configure an example fetcher (see config.WithExampleFetcher) to validate external example values"
+	HowToFixExampleFetchFailed         = "Check the external value '%s' of the example can be reached, or use an inline value instead"
+	HowToFixPartContentType            = "Send the '%s' part using one of the content types defined by its encoding: %s"
+	HowToFixUnsupportedPattern         = "Rewrite the pattern without lookaround assertions, backreferences or atomic groups, which Go's regular expression engine (RE2) does not support"
 )
@@ -0,0 +1,97 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// ProblemJSONContentType is the media type of an RFC 7807 problem details payload.
+const ProblemJSONContentType = "application/problem+json"
+
+// ProblemDetails is an RFC 7807 problem details object, describing a set of validation errors. The standard members
+// (type, title, status and detail) are populated, and the validation errors are included as an extension member,
+// so clients that only understand RFC 7807 can still render the problem.
+type ProblemDetails struct {
+	// Type is a URI reference that identifies the problem type. Validation errors have no specific type, so this is
+	// always 'about:blank'.
+	Type string `json:"type" yaml:"type"`
+
+	// Title is a short, human-readable summary of the problem type.
+	Title string `json:"title" yaml:"title"`
+
+	// Status is the HTTP status code that best fits the validation errors (see ProblemStatus).
+	Status int `json:"status" yaml:"status"`
+
+	// Detail is a human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty" yaml:"detail,omitempty"`
+
+	// Instance is a URI reference that identifies the specific occurrence of the problem, the request path.
+	Instance string `json:"instance,omitempty" yaml:"instance,omitempty"`
+
+	// Errors are the validation errors, including any nested schema validation failures.
+	Errors []*ValidationError `json:"errors" yaml:"errors"`
+}
+
+// NewProblemDetails will create an RFC 7807 problem details object for a set of validation errors. The status is the
+// status of the first error (see ProblemStatus), and the detail is the message of the error when there is only one,
+// or a count of the errors when there are several.
+func NewProblemDetails(errs []*ValidationError) *ProblemDetails {
+	problem := &ProblemDetails{
+		Type:   "about:blank",
+		Status: http.StatusBadRequest,
+		Errors: make([]*ValidationError, 0, len(errs)),
+	}
+	for _, e := range errs {
+		if e != nil {
+			problem.Errors = append(problem.Errors, e)
+		}
+	}
+	switch len(problem.Errors) {
+	case 0:
+	case 1:
+		problem.Detail = problem.Errors[0].Message
+	default:
+		problem.Detail = fmt.Sprintf("%d validation errors were found", len(problem.Errors))
+	}
+	if len(problem.Errors) > 0 {
+		problem.Status = ProblemStatus(problem.Errors[0])
+		problem.Instance = problem.Errors[0].RequestPath
+	}
+	problem.Title = http.StatusText(problem.Status)
+	return problem
+}
+
+// ToProblemJSON will marshal a set of validation errors into an RFC 7807 problem details payload (see
+// NewProblemDetails), that can be returned with the ProblemJSONContentType media type.
+func ToProblemJSON(errs []*ValidationError) ([]byte, error) {
+	return json.Marshal(NewProblemDetails(errs))
+}
+
+// ProblemStatus returns the HTTP status code that best fits a validation error. A response that does not match the
+// contract is 502, as the server is at fault rather than the client. A path that cannot be found is 404, an operation
+// that is not defined is 405, a response that cannot be acceptable is 406, and an unsupported request content type is
+// 415, and a request body that is too large is 413. Every other failure is 400.
+func ProblemStatus(validationError *ValidationError) int {
+	switch {
+	case validationError.IsServerError():
+		return http.StatusBadGateway
+	case validationError.IsPathMissingError():
+		return http.StatusNotFound
+	case validationError.ValidationSubType == helpers.RequestMissingOperation:
+		return http.StatusMethodNotAllowed
+	case validationError.ValidationSubType == helpers.RequestNotAcceptable:
+		return http.StatusNotAcceptable
+	case validationError.ValidationType == helpers.RequestBodyValidation &&
+		validationError.ValidationSubType == helpers.RequestBodyContentType:
+		return http.StatusUnsupportedMediaType
+	case validationError.ValidationSubType == helpers.RequestBodyTooLarge:
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
@@ -52,3 +52,203 @@ func OperationNotFound(pathItem *v3.PathItem, request *http.Request, method stri
 		SpecPath:      specPath,
 	}
 }
+
+func OperationIdNotFound(request *http.Request, operationId string) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.RequestValidation,
+		ValidationSubType: helpers.RequestUnknownOperationId,
+		Message:           fmt.Sprintf("Operation '%s' not found", operationId),
+		Reason:            fmt.Sprintf("There is no operation with the operationId '%s' in the specification", operationId),
+		SpecLine:          -1,
+		SpecCol:           -1,
+		HowToFix:          fmt.Sprintf(HowToFixOperationId, operationId),
+		RequestPath:       request.URL.Path,
+		RequestMethod:     request.Method,
+	}
+}
+
+func OperationIdMethodMismatch(op *v3.Operation, request *http.Request, method string, specPath string) *ValidationError {
+	line, col := -1, -1
+	if low := op.GoLow(); low != nil && low.OperationId.KeyNode != nil {
+		line, col = low.OperationId.KeyNode.Line, low.OperationId.KeyNode.Column
+	}
+	method = strings.ToUpper(method)
+	return &ValidationError{
+		ValidationType:    helpers.RequestValidation,
+		ValidationSubType: helpers.RequestMissingOperation,
+		Message: fmt.Sprintf("%s request does not match the %s operation '%s'",
+			request.Method, method, op.OperationId),
+		Reason: fmt.Sprintf("The operation '%s' is the %s operation of the path '%s', however the request "+
+			"method is %s", op.OperationId, method, specPath, request.Method),
+		SpecLine:      line,
+		SpecCol:       col,
+		Context:       op,
+		HowToFix:      fmt.Sprintf(HowToFixOperationIdMethod, method, op.OperationId),
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+		SpecPath:      specPath,
+	}
+}
+
+func OperationIdPathMismatch(op *v3.Operation, request *http.Request, specPath string) *ValidationError {
+	line, col := -1, -1
+	if low := op.GoLow(); low != nil && low.OperationId.KeyNode != nil {
+		line, col = low.OperationId.KeyNode.Line, low.OperationId.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidationPath,
+		ValidationSubType: "missing",
+		Message: fmt.Sprintf("%s Path '%s' does not match the path '%s' of the operation '%s'",
+			request.Method, request.URL.Path, specPath, op.OperationId),
+		Reason: fmt.Sprintf("The %s request contains a path of '%s', however the operation '%s' is defined "+
+			"for the path '%s', and the request path does not match it", request.Method, request.URL.Path,
+			op.OperationId, specPath),
+		SpecLine:      line,
+		SpecCol:       col,
+		Context:       op,
+		HowToFix:      fmt.Sprintf(HowToFixOperationIdPath, specPath, op.OperationId),
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+		SpecPath:      specPath,
+	}
+}
+
+func RequestBodyMissing(op *v3.Operation, request *http.Request, specPath string) *ValidationError {
+	line, col := -1, -1
+	if op.RequestBody.GoLow().Required.KeyNode != nil {
+		line = op.RequestBody.GoLow().Required.KeyNode.Line
+		col = op.RequestBody.GoLow().Required.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.RequestBodyValidation,
+		ValidationSubType: helpers.RequestBodyMissing,
+		Message: fmt.Sprintf("%s request body is empty for '%s'",
+			request.Method, request.URL.Path),
+		Reason:        fmt.Sprintf("The %s request body is defined as being required, however it's missing", request.Method),
+		SpecLine:      line,
+		SpecCol:       col,
+		Context:       op,
+		HowToFix:      HowToFixMissingRequestBody,
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+		SpecPath:      specPath,
+	}
+}
+
+// RequestBodyUndeclared is returned when a request sends a body to an operation that does not define a requestBody
+// (see config.WithUndeclaredRequestBodyRejection).
+func RequestBodyUndeclared(op *v3.Operation, request *http.Request, specPath string) *ValidationError {
+	line, col := -1, -1
+	if low := op.GoLow(); low != nil && low.KeyNode != nil {
+		line, col = low.KeyNode.Line, low.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.RequestBodyValidation,
+		ValidationSubType: helpers.RequestBodyUndeclared,
+		Message: fmt.Sprintf("%s request body for '%s' is not defined by the operation",
+			request.Method, request.URL.Path),
+		Reason: fmt.Sprintf("The %s request contains a body, however the operation does not define a "+
+			"requestBody", request.Method),
+		SpecLine:      line,
+		SpecCol:       col,
+		Context:       op,
+		HowToFix:      HowToFixUndeclaredBody,
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+		SpecPath:      specPath,
+	}
+}
+
+// RequestBodyTooLarge is returned when a request body is larger than the maximum size that will be read (see
+// config.WithMaxRequestBodySize). The body is not validated.
+func RequestBodyTooLarge(request *http.Request, limit int64) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.RequestBodyValidation,
+		ValidationSubType: helpers.RequestBodyTooLarge,
+		Message: fmt.Sprintf("%s request body for '%s' is too large",
+			request.Method, request.URL.Path),
+		Reason: fmt.Sprintf("The payload is too large, the %s request body is larger than the maximum of %d bytes",
+			request.Method, limit),
+		SpecLine:      1,
+		SpecCol:       0,
+		HowToFix:      fmt.Sprintf(HowToFixRequestBodyTooLarge, limit),
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+	}
+}
+
+// RequestBodyDiscriminatorMismatch is returned when the discriminator of a request body (the value at a JSON pointer
+// in the body) is missing, or does not select any of the schemas of the request body.
+func RequestBodyDiscriminatorMismatch(op *v3.Operation, request *http.Request, specPath, pointer string,
+	value string, found bool) *ValidationError {
+	line, col := -1, -1
+	if op.RequestBody.GoLow() != nil && op.RequestBody.GoLow().Content.KeyNode != nil {
+		line = op.RequestBody.GoLow().Content.KeyNode.Line
+		col = op.RequestBody.GoLow().Content.KeyNode.Column
+	}
+	reason := fmt.Sprintf("The %s request body has no value at '%s', so a schema cannot be selected for it",
+		request.Method, pointer)
+	if found {
+		reason = fmt.Sprintf("The %s request body has a value of '%s' at '%s', however no request body schema "+
+			"is selected by that value", request.Method, value, pointer)
+	}
+	return &ValidationError{
+		ValidationType:    helpers.RequestBodyValidation,
+		ValidationSubType: helpers.RequestBodyDiscriminator,
+		Message: fmt.Sprintf("%s request body discriminator '%s' does not select a schema",
+			request.Method, pointer),
+		Reason:        reason,
+		SpecLine:      line,
+		SpecCol:       col,
+		Context:       op,
+		HowToFix:      fmt.Sprintf(HowToFixBodyDiscriminator, pointer),
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+		SpecPath:      specPath,
+	}
+}
+
+func RequestPartContentTypeInvalid(encoding *v3.Encoding, request *http.Request, partName, partContentType string) *ValidationError {
+	line, col := -1, -1
+	if encoding.GoLow() != nil && encoding.GoLow().ContentType.KeyNode != nil {
+		line = encoding.GoLow().ContentType.KeyNode.Line
+		col = encoding.GoLow().ContentType.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.RequestBodyValidation,
+		ValidationSubType: helpers.RequestBodyContentType,
+		Message: fmt.Sprintf("%s request body part '%s' content type '%s' is not allowed",
+			request.Method, partName, partContentType),
+		Reason: fmt.Sprintf("The '%s' part of the %s request body has a content type of '%s', "+
+			"however the encoding for the part only allows '%s'", partName, request.Method, partContentType, encoding.ContentType),
+		SpecLine:      line,
+		SpecCol:       col,
+		Context:       encoding,
+		HowToFix:      fmt.Sprintf(HowToFixPartContentType, partName, encoding.ContentType),
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+	}
+}
+
+func RequestNotAcceptable(op *v3.Operation, request *http.Request, specPath string, contentTypes []string) *ValidationError {
+	accept := request.Header.Get(helpers.AcceptHeader)
+	line, col := -1, -1
+	if low := op.GoLow(); low != nil && low.Responses.KeyNode != nil {
+		line, col = low.Responses.KeyNode.Line, low.Responses.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.RequestValidation,
+		ValidationSubType: helpers.RequestNotAcceptable,
+		Message: fmt.Sprintf("%s operation request accept header '%s' cannot be satisfied",
+			request.Method, accept),
+		Reason: fmt.Sprintf("The accept header '%s' of the %s request does not accept any of the "+
+			"response content types defined for the operation", accept, request.Method),
+		SpecLine:      line,
+		SpecCol:       col,
+		Context:       op,
+		HowToFix:      fmt.Sprintf(HowToFixNotAcceptable, len(contentTypes), strings.Join(contentTypes, ", ")),
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+		SpecPath:      specPath,
+	}
+}
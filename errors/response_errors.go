@@ -57,6 +57,10 @@ func ResponseContentTypeNotFound(op *v3.Operation,
 }
 
 func ResponseCodeNotFound(op *v3.Operation, request *http.Request, code int) *ValidationError {
+	line, col := -1, -1
+	if low := op.GoLow(); low != nil && low.Responses.KeyNode != nil {
+		line, col = low.Responses.KeyNode.Line, low.Responses.KeyNode.Column
+	}
 	return &ValidationError{
 		ValidationType:    helpers.ResponseBodyValidation,
 		ValidationSubType: helpers.ResponseBodyResponseCode,
@@ -64,8 +68,8 @@ func ResponseCodeNotFound(op *v3.Operation, request *http.Request, code int) *Va
 			request.Method, code),
 		Reason: fmt.Sprintf("The reponse code '%d' of the %s request submitted has not "+
 			"been defined, it's an unknown type", code, request.Method),
-		SpecLine: op.GoLow().Responses.KeyNode.Line,
-		SpecCol:  op.GoLow().Responses.KeyNode.Column,
+		SpecLine: line,
+		SpecCol:  col,
 		Context:  op,
 		HowToFix: HowToFixInvalidResponseCode,
 	}
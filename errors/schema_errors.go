@@ -0,0 +1,175 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"fmt"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaNotFound is returned when a schema referenced by name cannot be found in the components of a document.
+func SchemaNotFound(schemaName string) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.Schema,
+		ValidationSubType: helpers.SchemaMissing,
+		Message:           fmt.Sprintf("schema '%s' does not exist", schemaName),
+		Reason: fmt.Sprintf("The schema '%s' is not defined in 'components.schemas' of the specification",
+			schemaName),
+		SpecLine: 1,
+		SpecCol:  0,
+		HowToFix: HowToFixMissingSchema,
+	}
+}
+
+// ExampleFetchingDisabled is returned when an example references an external value, but no example fetcher has been
+// configured, so the value cannot be fetched.
+func ExampleFetchingDisabled(example *base.Example) *ValidationError {
+	line, col := exampleExternalValueLocation(example)
+	return &ValidationError{
+		ValidationType:    helpers.Schema,
+		ValidationSubType: helpers.ExampleFetchDisabled,
+		Message:           fmt.Sprintf("example external value '%s' cannot be fetched", example.ExternalValue),
+		Reason: fmt.Sprintf("The example references the external value '%s', however fetching external "+
+			"values is disabled", example.ExternalValue),
+		SpecLine: line,
+		SpecCol:  col,
+		HowToFix: HowToFixExampleFetching,
+	}
+}
+
+// ExampleFetchFailed is returned when the external value of an example cannot be fetched.
+func ExampleFetchFailed(example *base.Example, err error) *ValidationError {
+	line, col := exampleExternalValueLocation(example)
+	return &ValidationError{
+		ValidationType:    helpers.Schema,
+		ValidationSubType: helpers.ExampleFetchFailed,
+		Message:           fmt.Sprintf("example external value '%s' failed to be fetched", example.ExternalValue),
+		Reason: fmt.Sprintf("The example references the external value '%s', which could not be fetched: %s",
+			example.ExternalValue, err.Error()),
+		SpecLine: line,
+		SpecCol:  col,
+		HowToFix: fmt.Sprintf(HowToFixExampleFetchFailed, example.ExternalValue),
+	}
+}
+
+// SchemaPatternUnsupported is returned when a schema contains patterns that use regex features Go's regexp package
+// does not support, so the schema cannot be compiled, and values can never be validated against it. Each pattern is
+// described by one of the failures.
+func SchemaPatternUnsupported(failures []*SchemaValidationFailure, renderedSchema string) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.Schema,
+		ValidationSubType: helpers.SchemaPatternUnsupported,
+		Message:           "pattern uses unsupported regex feature",
+		Reason: fmt.Sprintf("The schema contains %d pattern(s) that cannot be evaluated by Go's regular "+
+			"expression engine, so values can never be validated against the schema", len(failures)),
+		SpecLine:               1,
+		SpecCol:                0,
+		SchemaValidationErrors: failures,
+		HowToFix:               HowToFixUnsupportedPattern,
+		Context:                renderedSchema,
+	}
+}
+
+// SchemaRenderFailed is returned when a schema cannot be rendered so it can be compiled, usually because a reference
+// within it cannot be resolved. The location is the JSON pointer to the schema that failed within the rendered schema,
+// and the node is where it is defined, if it is known.
+func SchemaRenderFailed(reason, location string, node *yaml.Node) *ValidationError {
+	line, col := 1, 0
+	if node != nil {
+		line, col = node.Line, node.Column
+	}
+	var failures []*SchemaValidationFailure
+	if location != "" {
+		failures = append(failures, &SchemaValidationFailure{
+			Reason:     reason,
+			Location:   location,
+			Line:       line,
+			Column:     col,
+			SchemaNode: node,
+		})
+	}
+	return &ValidationError{
+		ValidationType:         helpers.Schema,
+		ValidationSubType:      helpers.SchemaRenderFailed,
+		Message:                "schema cannot be rendered, it likely contains an unresolved reference",
+		Reason:                 fmt.Sprintf("The schema cannot be rendered for validation: %s", reason),
+		SpecLine:               line,
+		SpecCol:                col,
+		SchemaValidationErrors: failures,
+		HowToFix:               HowToFixSchemaRender,
+	}
+}
+
+// SchemaEnumValueInvalid is returned when an enum value of a schema does not conform to the type, format or bounds
+// of the schema, so it can never be a valid value. The failures describe each keyword the value violates.
+func SchemaEnumValueInvalid(value, location string, node *yaml.Node,
+	failures []*SchemaValidationFailure) *ValidationError {
+	line, col := -1, -1
+	if node != nil {
+		line, col = node.Line, node.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.Schema,
+		ValidationSubType: helpers.SchemaEnumValueInvalid,
+		Message:           fmt.Sprintf("enum value %s at '%s' is not valid for its schema", value, location),
+		Reason: fmt.Sprintf("The enum value %s does not conform to the type, format or bounds of its schema, "+
+			"so it can never be a valid value", value),
+		SpecLine:               line,
+		SpecCol:                col,
+		SchemaValidationErrors: failures,
+		HowToFix:               HowToFixInvalidEnumValue,
+	}
+}
+
+// SchemaDefaultInvalid is returned when the default value of a schema does not pass validation against the schema,
+// so a value filled in from the default would fail validation. The failures describe each keyword the value violates.
+func SchemaDefaultInvalid(value, location string, node *yaml.Node,
+	failures []*SchemaValidationFailure) *ValidationError {
+	line, col := -1, -1
+	if node != nil {
+		line, col = node.Line, node.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.Schema,
+		ValidationSubType: helpers.SchemaDefaultInvalid,
+		Message:           fmt.Sprintf("default value %s at '%s' is not valid for its schema", value, location),
+		Reason: fmt.Sprintf("The default value %s does not pass validation against its schema, "+
+			"so a value that is filled in from it would fail validation", value),
+		SpecLine:               line,
+		SpecCol:                col,
+		SchemaValidationErrors: failures,
+		HowToFix:               HowToFixInvalidDefault,
+	}
+}
+
+// SchemaExampleInvalid is returned when an example of a schema (or of a media type) does not pass validation
+// against the schema it is an example of. The failures describe each keyword the example violates.
+func SchemaExampleInvalid(value, location string, node *yaml.Node,
+	failures []*SchemaValidationFailure) *ValidationError {
+	line, col := -1, -1
+	if node != nil {
+		line, col = node.Line, node.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.Schema,
+		ValidationSubType: helpers.SchemaExampleInvalid,
+		Message:           fmt.Sprintf("example %s at '%s' is not valid for its schema", value, location),
+		Reason: fmt.Sprintf("The example %s does not pass validation against its schema, "+
+			"so it does not show a value that can be sent", value),
+		SpecLine:               line,
+		SpecCol:                col,
+		SchemaValidationErrors: failures,
+		HowToFix:               HowToFixInvalidExample,
+	}
+}
+
+func exampleExternalValueLocation(example *base.Example) (int, int) {
+	if low := example.GoLow(); low != nil && low.ExternalValue.ValueNode != nil {
+		return low.ExternalValue.ValueNode.Line, low.ExternalValue.ValueNode.Column
+	}
+	return 1, 0
+}
@@ -5,7 +5,10 @@ package errors
 
 import (
 	"fmt"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
 )
 
 // SchemaValidationFailure is a wrapper around the jsonschema.ValidationError object, to provide a more
@@ -17,6 +20,9 @@ type SchemaValidationFailure struct {
 	// Location is the XPath-like location of the validation failure
 	Location string `json:"location,omitempty" yaml:"location,omitempty"`
 
+	// InstanceLocation is the JSON pointer to the value that failed validation, within the validated object.
+	InstanceLocation string `json:"instanceLocation,omitempty" yaml:"instanceLocation,omitempty"`
+
 	// DeepLocation is the path to the validation failure as exposed by the jsonschema library.
 	DeepLocation string `json:"deepLocation,omitempty" yaml:"deepLocation,omitempty"`
 
@@ -42,8 +48,17 @@ type SchemaValidationFailure struct {
 	// ReferenceExample is an example object generated from the schema that was referenced in the validation failure.
 	ReferenceExample string `json:"referenceExample,omitempty" yaml:"referenceExample,omitempty"`
 
+	// SchemaNode is the node of the violated schema property, located within the rendered schema (or the document,
+	// when validating a document). Line and Column are taken from this node, use it to render the violated
+	// sub-schema, or to read keywords around it (such as a description). nil if the property could not be located.
+	SchemaNode *yaml.Node `json:"-" yaml:"-"`
+
 	// The original error object, which is a jsonschema.ValidationError object.
 	OriginalError *jsonschema.ValidationError `json:"-" yaml:"-"`
+
+	// LeafError is the specific jsonschema.ValidationError (found within the causes of OriginalError) that is
+	// responsible for this failure. Use it to introspect the keyword, absolute keyword location and causes.
+	LeafError *jsonschema.ValidationError `json:"-" yaml:"-"`
 }
 
 // Error returns a string representation of the error
@@ -51,6 +66,21 @@ func (s *SchemaValidationFailure) Error() string {
 	return fmt.Sprintf("Reason: %s, Location: %s", s.Reason, s.Location)
 }
 
+// PathSuggestion is a path from the specification that is similar to a request path that could not be found.
+type PathSuggestion struct {
+	// Path is the path from the specification.
+	Path string `json:"path" yaml:"path"`
+
+	// Line is the line number of the path in the specification.
+	Line int `json:"line" yaml:"line"`
+
+	// Column is the column number of the path in the specification.
+	Column int `json:"column" yaml:"column"`
+
+	// MatchingSegments is the number of leading segments of the request path that match the path.
+	MatchingSegments int `json:"matchingSegments" yaml:"matchingSegments"`
+}
+
 // ValidationError is a struct that contains all the information about a validation error.
 type ValidationError struct {
 
@@ -84,13 +114,34 @@ type ValidationError struct {
 	// RequestMethod is the HTTP method of the request
 	RequestMethod string `json:"requestMethod" yaml:"requestMethod"`
 
+	// Direction is 'request' if the error was found validating a request, which is a client error, or 'response' if it
+	// was found validating a response, which is a server (or contract) error. It is empty for errors found validating
+	// schemas or documents directly.
+	Direction string `json:"direction,omitempty" yaml:"direction,omitempty"`
+
+	// ResponseCode is the response of the operation that a response was validated against: a status code (such as
+	// '200'), a range (such as '2XX'), or 'default' when no status code matched and the default response was used.
+	// It is only populated for errors found validating a response against a declared response.
+	ResponseCode string `json:"responseCode,omitempty" yaml:"responseCode,omitempty"`
+
 	// SchemaValidationErrors is a slice of SchemaValidationFailure objects that describe the validation errors
 	// This is only populated whe the validation type is against a schema.
 	SchemaValidationErrors []*SchemaValidationFailure `json:"validationErrors,omitempty" yaml:"validationErrors,omitempty"`
 
+	// PathSuggestions is a slice of the specification paths most similar to the request path. This is only
+	// populated when a path cannot be found, and suggestions have been enabled.
+	PathSuggestions []*PathSuggestion `json:"pathSuggestions,omitempty" yaml:"pathSuggestions,omitempty"`
+
+	// ParameterDefinition is the parameter definition (from the path, or the operation) that was applied when the
+	// error occurred. This is only populated for parameter validation errors.
+	ParameterDefinition *v3.Parameter `json:"-" yaml:"-"`
+
 	// Context is the object that the validation error occurred on. This is usually a pointer to a schema
 	// or a parameter object.
 	Context interface{} `json:"-" yaml:"-"`
+
+	// formatted is true once the messages have been replaced by a MessageFormatter.
+	formatted bool
 }
 
 // Error returns a string representation of the error
@@ -118,3 +169,15 @@ func (v *ValidationError) Error() string {
 func (v *ValidationError) IsPathMissingError() bool {
 	return v.ValidationType == "path" && v.ValidationSubType == "missing"
 }
+
+// IsClientError returns true if the error was found validating a request, so the client sent something that does not
+// match the contract (a 4xx status).
+func (v *ValidationError) IsClientError() bool {
+	return v.Direction == helpers.RequestDirection
+}
+
+// IsServerError returns true if the error was found validating a response, so the server (or the contract) is at
+// fault, not the client (a 5xx status).
+func (v *ValidationError) IsServerError() bool {
+	return v.Direction == helpers.ResponseDirection
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package errors
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// ValidationWarning is something worth reporting about a request that does not fail validation, for example a
+// request for an operation that is deprecated.
+type ValidationWarning struct {
+	// WarningType is the type of warning, for example 'deprecated'.
+	WarningType string `json:"warningType" yaml:"warningType"`
+
+	// Message is a human-readable message describing the warning.
+	Message string `json:"message" yaml:"message"`
+
+	// Reason is a human-readable message describing the reason for the warning.
+	Reason string `json:"reason" yaml:"reason"`
+
+	// SpecLine is the line number in the spec where the warning originates.
+	SpecLine int `json:"specLine" yaml:"specLine"`
+
+	// SpecCol is the column number in the spec where the warning originates.
+	SpecCol int `json:"specColumn" yaml:"specColumn"`
+
+	// HowToFix is a human-readable message describing how to avoid the warning.
+	HowToFix string `json:"howToFix" yaml:"howToFix"`
+
+	// RequestPath is the path of the request.
+	RequestPath string `json:"requestPath" yaml:"requestPath"`
+
+	// SpecPath is the path from the specification that corresponds to the request.
+	SpecPath string `json:"specPath" yaml:"specPath"`
+
+	// RequestMethod is the HTTP method of the request.
+	RequestMethod string `json:"requestMethod" yaml:"requestMethod"`
+}
+
+// OperationDeprecated returns a warning for a request to an operation that is marked as deprecated.
+func OperationDeprecated(request *http.Request, operation *v3.Operation, path string) *ValidationWarning {
+	specLine, specCol := -1, -1
+	if low := operation.GoLow(); low != nil && low.Deprecated.KeyNode != nil {
+		specLine, specCol = low.Deprecated.KeyNode.Line, low.Deprecated.KeyNode.Column
+	}
+	return &ValidationWarning{
+		WarningType: helpers.OperationDeprecated,
+		Message:     fmt.Sprintf("%s operation for path '%s' is deprecated", request.Method, path),
+		Reason: fmt.Sprintf("The %s operation for path '%s' is marked as deprecated in the specification, "+
+			"it may be removed in the future", request.Method, path),
+		SpecLine:      specLine,
+		SpecCol:       specCol,
+		HowToFix:      HowToFixDeprecatedOperation,
+		RequestPath:   request.URL.Path,
+		SpecPath:      path,
+		RequestMethod: request.Method,
+	}
+}
+
+// DefaultResponseUsed returns a warning for a response with a status code that the operation does not declare, which
+// was validated against the 'default' response of the operation instead.
+func DefaultResponseUsed(request *http.Request, response *http.Response, operation *v3.Operation,
+	path string) *ValidationWarning {
+	specLine, specCol := -1, -1
+	if low := operation.GoLow(); low != nil && low.Responses.Value != nil && low.Responses.Value.Default.KeyNode != nil {
+		specLine, specCol = low.Responses.Value.Default.KeyNode.Line, low.Responses.Value.Default.KeyNode.Column
+	}
+	return &ValidationWarning{
+		WarningType: helpers.DefaultResponseUsed,
+		Message: fmt.Sprintf("%s response code '%d' for path '%s' was validated against the default response",
+			request.Method, response.StatusCode, path),
+		Reason: fmt.Sprintf("The response code '%d' is not declared by the %s operation for path '%s', so the "+
+			"response was validated against the 'default' response", response.StatusCode, request.Method, path),
+		SpecLine:      specLine,
+		SpecCol:       specCol,
+		HowToFix:      fmt.Sprintf(HowToFixDefaultResponse, response.StatusCode),
+		RequestPath:   request.URL.Path,
+		SpecPath:      path,
+		RequestMethod: request.Method,
+	}
+}
@@ -10,11 +10,34 @@ const (
 	ParameterValidationHeader = "header"
 	ParameterValidationCookie = "cookie"
 	RequestValidation         = "request"
+	RequestDirection          = "request"
+	ResponseDirection         = "response"
 	RequestBodyValidation     = "requestBody"
 	Schema                    = "schema"
 	ResponseBodyValidation    = "response"
 	RequestBodyContentType    = "contentType"
 	RequestMissingOperation   = "missingOperation"
+	RequestUnknownOperationId = "unknownOperationId"
+	RequestBodyMissing        = "missingBody"
+	RequestBodyDiscriminator  = "discriminator"
+	RequestBodyTooLarge       = "bodyTooLarge"
+	RequestBodyUndeclared     = "undeclaredBody"
+	RequestNotAcceptable      = "notAcceptable"
+	SchemaMissing             = "missingSchema"
+	ExampleFetchDisabled      = "exampleFetchDisabled"
+	ExampleFetchFailed        = "exampleFetchFailed"
+	SchemaPatternUnsupported  = "unsupportedPattern"
+	SchemaEnumValueInvalid    = "invalidEnumValue"
+	SchemaDefaultInvalid      = "invalidDefault"
+	SchemaExampleInvalid      = "invalidExample"
+	PathAmbiguous             = "ambiguousPath"
+	PathOverlapping           = "overlappingPath"
+	SchemaRenderFailed        = "renderFailed"
+	ParameterRefUnresolved    = "unresolvedReference"
+	ParameterBinding          = "binding"
+	OperationDeprecated       = "deprecated"
+	DefaultResponse           = "default"
+	DefaultResponseUsed       = "defaultResponse"
 	ResponseBodyResponseCode  = "statusCode"
 	SpaceDelimited            = "spaceDelimited"
 	PipeDelimited             = "pipeDelimited"
@@ -31,10 +54,16 @@ const (
 	Integer                   = "integer"
 	Number                    = "number"
 	Slash                     = "/"
+	EncodedSlash              = "%2F"
+	EncodedPercent            = "%25"
 	Object                    = "object"
 	String                    = "string"
 	Array                     = "array"
 	Boolean                   = "boolean"
+	Null                      = "null"
+	ByteFormat                = "byte"
+	BinaryFormat              = "binary"
+	DecimalFormat             = "decimal"
 	DeepObject                = "deepObject"
 	Header                    = "header"
 	Cookie                    = "cookie"
@@ -43,10 +72,18 @@ const (
 	Query                     = "query"
 	JSONContentType           = "application/json"
 	JSONType                  = "json"
+	FormURLEncodedType        = "application/x-www-form-urlencoded"
+	MultipartFormDataType     = "multipart/form-data"
+	XMLType                   = "xml"
+	TextPlainType             = "text/plain"
 	ContentTypeHeader         = "Content-Type"
+	AcceptHeader              = "Accept"
 	AuthorizationHeader       = "Authorization"
 	Charset                   = "charset"
 	Boundary                  = "boundary"
 	Preferred                 = "preferred"
 	FailSegment               = "**&&FAIL&&**"
+	XValidator                = "x-validator"
+	XScale                    = "x-scale"
+	XAllowReserved            = "x-allow-reserved"
 )
@@ -4,13 +4,69 @@
 package helpers
 
 import (
+	"context"
 	"github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/datamodel/low"
+	lowv3 "github.com/pb33f/libopenapi/datamodel/low/v3"
+	"github.com/pb33f/libopenapi/index"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 )
 
+// OperationCache builds the operations of a document that are not part of its model when they are first needed, and
+// keeps them: the OpenAPI path item object has no 'connect' field, so CONNECT operations are built from the path item
+// node, and the operations selected by a method extension (see config.WithMethodExtension) are built from the
+// extension node. The parameter lists rebuilt by ResolveParameterRefs are kept too. Validators and path matchers each
+// hold a cache for their document (unless one is shared, see config.WithOperationCache), so what it keeps is
+// released with them.
+type OperationCache struct {
+	document        *v3.Document
+	methodExtension string
+	connect         sync.Map
+	extension       sync.Map
+
+	// parameters are keyed by the node of the operation (or path item) that defines them.
+	parameters sync.Map
+}
+
+type extensionOperationKey struct {
+	item   *v3.PathItem
+	method string
+}
+
+// NewOperationCache will create a new OperationCache for a document. The index of the document is used to resolve
+// the references of the operations it builds. If a method extension is supplied (such as 'x-method'), then it selects
+// the operations of the methods a path item does not define.
+func NewOperationCache(document *v3.Document, methodExtension string) *OperationCache {
+	return &OperationCache{document: document, methodExtension: methodExtension}
+}
+
+// ExtractOperation is the same as the package level ExtractOperation, except CONNECT operations (see
+// ExtractConnectOperation) and the operations selected by the method extension (see ExtractExtensionOperation) are
+// also returned.
+func (c *OperationCache) ExtractOperation(request *http.Request, item *v3.PathItem) *v3.Operation {
+	if op := ExtractOperation(request, item); op != nil {
+		return op
+	}
+	if request.Method == http.MethodConnect {
+		if op := c.ExtractConnectOperation(item); op != nil {
+			return op
+		}
+	}
+	if c.methodExtension != "" {
+		return c.ExtractExtensionOperation(item, request.Method)
+	}
+	return nil
+}
+
 // ExtractOperation extracts the operation from the path item based on the request method. If there is no
-// matching operation found, then nil is returned.
+// matching operation found, then nil is returned. CONNECT operations and the operations selected by a method
+// extension are not part of the path item model, so they are only returned by OperationCache.ExtractOperation.
 func ExtractOperation(request *http.Request, item *v3.PathItem) *v3.Operation {
 	switch request.Method {
 	case http.MethodGet:
@@ -33,6 +89,76 @@ func ExtractOperation(request *http.Request, item *v3.PathItem) *v3.Operation {
 	return nil
 }
 
+// ExtractConnectOperation extracts the CONNECT operation of a path item. The OpenAPI path item object has no
+// 'connect' field, so the operation is built from the 'connect' key of the path item node (the first time it is
+// needed), using the index of the document to resolve any references. If the path item does not define a CONNECT
+// operation, then nil is returned.
+func (c *OperationCache) ExtractConnectOperation(item *v3.PathItem) *v3.Operation {
+	if op, ok := c.connect.Load(item); ok {
+		return op.(*v3.Operation)
+	}
+	op, _ := c.connect.LoadOrStore(item, buildConnectOperation(item, c.document))
+	return op.(*v3.Operation)
+}
+
+func buildConnectOperation(item *v3.PathItem, document *v3.Document) *v3.Operation {
+	if item == nil || item.GoLow() == nil {
+		return nil
+	}
+	return buildOperationFromNode(item.GoLow().RootNode, http.MethodConnect, document)
+}
+
+// ExtractExtensionOperation extracts the operation a method extension of a path item (such as 'x-method') selects
+// for a method. Some specifications define non-standard operations (or operations that the standard method fields
+// cannot hold) with an extension that maps method names to operations:
+//
+//	/cache:
+//	  x-method:
+//	    PURGE:
+//	      operationId: purgeCache
+//
+// The method extension of the cache is used, methods are matched case-insensitively, and references are resolved
+// using the index of the document. The operation is built the first time it is needed. If the extension does not
+// define an operation for the method, then nil is returned.
+func (c *OperationCache) ExtractExtensionOperation(item *v3.PathItem, method string) *v3.Operation {
+	key := extensionOperationKey{item, strings.ToUpper(method)}
+	if op, ok := c.extension.Load(key); ok {
+		return op.(*v3.Operation)
+	}
+	var op *v3.Operation
+	if item != nil && item.Extensions != nil && c.methodExtension != "" {
+		if node, ok := item.Extensions.Get(c.methodExtension); ok {
+			op = buildOperationFromNode(node, method, c.document)
+		}
+	}
+	stored, _ := c.extension.LoadOrStore(key, op)
+	return stored.(*v3.Operation)
+}
+
+// buildOperationFromNode builds the operation defined under the key of a mapping node that matches a method.
+func buildOperationFromNode(node *yaml.Node, method string, document *v3.Document) *v3.Operation {
+	if node == nil || document == nil || document.GoLow() == nil || document.GoLow().Index == nil {
+		return nil
+	}
+	idx := document.GoLow().Index
+	root := utils.NodeAlias(node)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if !strings.EqualFold(root.Content[i].Value, method) {
+			continue
+		}
+		var op lowv3.Operation
+		if low.BuildModel(root.Content[i+1], &op) != nil {
+			return nil
+		}
+		ctx := context.WithValue(context.Background(), index.FoundIndexKey, idx)
+		if op.Build(ctx, root.Content[i], root.Content[i+1], idx) != nil {
+			return nil
+		}
+		return v3.NewOperation(&op)
+	}
+	return nil
+}
+
 // ExtractContentType extracts the content type from the request header. First return argument is the content type
 // of the request.The second (optional) argument is the charset of the request. The third (optional)
 // argument is the boundary of the type (only used with forms really).
@@ -57,3 +183,180 @@ func ExtractContentType(contentType string) (string, string, string) {
 	}
 	return contentType, charset, boundary
 }
+
+// MediaRange is a media range from an Accept header (such as 'application/json', 'text/*' or '*/*'), and the quality
+// (q-value) it was given.
+type MediaRange struct {
+	MediaRange string
+	Quality    float64
+}
+
+// ParseAcceptHeader parses the media ranges of an Accept header, and returns them with the highest quality first.
+// Media ranges without a q-value have a quality of 1, and media ranges with an invalid q-value are ignored.
+func ParseAcceptHeader(accept string) []*MediaRange {
+	var ranges []*MediaRange
+	for _, r := range strings.Split(accept, Comma) {
+		segs := strings.Split(r, SemiColon)
+		mediaRange := strings.ToLower(strings.TrimSpace(segs[0]))
+		if mediaRange == "" {
+			continue
+		}
+		if mediaRange == Asterisk {
+			mediaRange = "*/*" // some clients send a single asterisk.
+		}
+		quality, valid := 1.0, true
+		for _, param := range segs[1:] {
+			kv := strings.SplitN(param, Equals, 2)
+			if len(kv) == 2 && strings.TrimSpace(strings.ToLower(kv[0])) == "q" {
+				q, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+				if err != nil || q < 0 || q > 1 {
+					valid = false
+				}
+				quality = q
+			}
+		}
+		if valid {
+			ranges = append(ranges, &MediaRange{MediaRange: mediaRange, Quality: quality})
+		}
+	}
+	slices.SortStableFunc(ranges, func(a, b *MediaRange) int {
+		switch {
+		case a.Quality > b.Quality:
+			return -1
+		case a.Quality < b.Quality:
+			return 1
+		}
+		return 0
+	})
+	return ranges
+}
+
+// MediaTypeQuality returns the quality an Accept header has given a media type. The most specific media range that
+// matches the media type decides the quality (so 'text/html' beats 'text/*', which beats '*/*'). The media type may
+// itself be a range (such as 'image/*'), which matches any media range it overlaps with. If no media range matches,
+// the quality is 0, and the media type is not acceptable.
+func MediaTypeQuality(mediaType string, ranges []*MediaRange) float64 {
+	mediaType, _, _ = ExtractContentType(strings.ToLower(mediaType))
+	typ, subType, _ := strings.Cut(mediaType, Slash)
+	quality, specificity := 0.0, -1
+	for _, r := range ranges {
+		rangeType, rangeSubType, _ := strings.Cut(r.MediaRange, Slash)
+		s := 0
+		switch {
+		case rangeType == Asterisk:
+		case rangeType != typ && typ != Asterisk:
+			continue
+		case rangeSubType == Asterisk:
+			s = 1
+		case rangeSubType != subType && subType != Asterisk:
+			continue
+		default:
+			s = 2
+		}
+		if s > specificity {
+			quality, specificity = r.Quality, s
+		}
+	}
+	return quality
+}
+
+// rebuiltParameterSet holds the parameters that were missing from the model, because one of them is a reference that
+// cannot be resolved, once they are rebuilt one at a time by ResolveParameterRefs.
+type rebuiltParameterSet struct {
+	parameters []*v3.Parameter
+	unresolved []*yaml.Node
+}
+
+// ResolveParameterRefs checks that every parameter of a path item, and of the operation for the request method, made
+// it into the model. When libopenapi cannot resolve a parameter '$ref', none of the parameters in the same list are
+// built, so they would silently not be validated. Such lists are rebuilt one parameter at a time, using the index of
+// the document, so every parameter that can be resolved is still returned by ExtractParamsForOperation. The '$ref'
+// value nodes of the parameters that cannot be resolved are returned.
+func (c *OperationCache) ResolveParameterRefs(request *http.Request, item *v3.PathItem) []*yaml.Node {
+	if item == nil {
+		return nil
+	}
+	var unresolved []*yaml.Node
+	if item.GoLow() != nil {
+		unresolved = append(unresolved, c.rebuildParameters(item.GoLow().RootNode, len(item.Parameters))...)
+	}
+	if op := c.ExtractOperation(request, item); op != nil && op.GoLow() != nil {
+		unresolved = append(unresolved, c.rebuildParameters(op.GoLow().RootNode, len(op.Parameters))...)
+	}
+	return unresolved
+}
+
+// resolvedParameters returns the parameters rebuilt for the node of an operation (or path item), if they were
+// rebuilt, otherwise the parameters of the model are returned.
+func (c *OperationCache) resolvedParameters(root *yaml.Node, parameters []*v3.Parameter) []*v3.Parameter {
+	if root == nil {
+		return parameters
+	}
+	if rebuilt, ok := c.parameters.Load(root); ok {
+		return rebuilt.(*rebuiltParameterSet).parameters
+	}
+	return parameters
+}
+
+// rebuildParameters rebuilds the parameters defined by the node of an operation (or path item), if fewer than were
+// defined are in the model. The '$ref' value nodes of the parameters that cannot be resolved are returned.
+func (c *OperationCache) rebuildParameters(root *yaml.Node, built int) []*yaml.Node {
+	if root == nil || c.document == nil || c.document.GoLow() == nil || c.document.GoLow().Index == nil {
+		return nil
+	}
+	if rebuilt, ok := c.parameters.Load(root); ok {
+		return rebuilt.(*rebuiltParameterSet).unresolved
+	}
+	params := parametersNode(utils.NodeAlias(root))
+	if params == nil || len(params.Content) == built {
+		return nil
+	}
+
+	idx := c.document.GoLow().Index
+	set := &rebuiltParameterSet{}
+	for _, node := range params.Content {
+		paramIdx := idx
+		if ref := refValueNode(node); ref != nil {
+			located, locatedIdx, err := low.LocateRefNode(node, idx)
+			if err != nil || located == nil {
+				set.unresolved = append(set.unresolved, ref)
+				continue
+			}
+			node = located
+			if locatedIdx != nil {
+				paramIdx = locatedIdx // the reference may be within another document.
+			}
+		}
+		var param lowv3.Parameter
+		if low.BuildModel(node, &param) != nil {
+			continue
+		}
+		ctx := context.WithValue(context.Background(), index.FoundIndexKey, paramIdx)
+		if param.Build(ctx, nil, node, paramIdx) != nil {
+			continue
+		}
+		set.parameters = append(set.parameters, v3.NewParameter(&param))
+	}
+	rebuilt, _ := c.parameters.LoadOrStore(root, set)
+	return rebuilt.(*rebuiltParameterSet).unresolved
+}
+
+// parametersNode returns the 'parameters' sequence of an operation (or path item) node, or nil if there is none.
+func parametersNode(root *yaml.Node) *yaml.Node {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "parameters" && root.Content[i+1].Kind == yaml.SequenceNode {
+			return root.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// refValueNode returns the value node of the '$ref' of a node, or nil if the node is not a reference.
+func refValueNode(node *yaml.Node) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "$ref" {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
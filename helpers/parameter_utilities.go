@@ -8,11 +8,24 @@ import (
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"net/http"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+var compoundParamRegex = regexp.MustCompile(`{([^{}]+)}`)
+
+// compoundSegmentRegexes keeps the expression compiled for each compound segment template, as segments are matched
+// against every request. Templates come from specifications, so only a bounded number of them are ever kept.
+var compoundSegmentRegexes sync.Map
+
+type compoundSegmentKey struct {
+	segment  string
+	foldCase bool
+}
+
 // QueryParam is a struct that holds the key, values and property name for a query parameter
 // it's used for complex query types that need to be parsed and tracked differently depending
 // on the encoding styles used.
@@ -20,47 +33,82 @@ type QueryParam struct {
 	Key      string
 	Values   []string
 	Property string
+
+	// NestedProperties are the keys of a deepObject parameter that follow the property, for nested objects. For
+	// example, filter[address][city] has the property 'address', and the nested property 'city'.
+	NestedProperties []string
+
+	// RawValues are the values as they were sent, before any percent-decoding. If set, there is a raw value for
+	// every value in Values.
+	RawValues []string
 }
 
 // ExtractParamsForOperation will extract the parameters for the operation based on the request method.
-// Both the path level params and the method level params will be returned.
+// Both the path level params and the method level params will be returned, merged by MergeParams.
 func ExtractParamsForOperation(request *http.Request, item *v3.PathItem) []*v3.Parameter {
-	params := item.Parameters
-	switch request.Method {
-	case http.MethodGet:
-		if item.Get != nil {
-			params = append(params, item.Get.Parameters...)
-		}
-	case http.MethodPost:
-		if item.Post != nil {
-			params = append(params, item.Post.Parameters...)
-		}
-	case http.MethodPut:
-		if item.Put != nil {
-			params = append(params, item.Put.Parameters...)
-		}
-	case http.MethodDelete:
-		if item.Delete != nil {
-			params = append(params, item.Delete.Parameters...)
-		}
-	case http.MethodOptions:
-		if item.Options != nil {
-			params = append(params, item.Options.Parameters...)
+	var opParams []*v3.Parameter
+	if op := ExtractOperation(request, item); op != nil {
+		opParams = op.Parameters
+	}
+	return MergeParams(item.Parameters, opParams)
+}
+
+// ExtractParamsForOperation is the same as the package level ExtractParamsForOperation, except the parameters of
+// CONNECT operations and the operations selected by the method extension are also returned (see
+// OperationCache.ExtractOperation). If the parameters have been rebuilt by ResolveParameterRefs, then the rebuilt
+// parameters are returned.
+func (c *OperationCache) ExtractParamsForOperation(request *http.Request, item *v3.PathItem) []*v3.Parameter {
+	var opParams []*v3.Parameter
+	if op := c.ExtractOperation(request, item); op != nil {
+		opParams = op.Parameters
+		if op.GoLow() != nil {
+			opParams = c.resolvedParameters(op.GoLow().RootNode, opParams)
 		}
-	case http.MethodHead:
-		if item.Head != nil {
-			params = append(params, item.Head.Parameters...)
+	}
+	pathParams := item.Parameters
+	if item.GoLow() != nil {
+		pathParams = c.resolvedParameters(item.GoLow().RootNode, pathParams)
+	}
+	return MergeParams(pathParams, opParams)
+}
+
+// MergeParams will merge the path level params and the method level params of an operation. A method level param
+// overrides a path level param with the same name and location, so only the method level param is returned.
+func MergeParams(pathParams, operationParams []*v3.Parameter) []*v3.Parameter {
+	params := make([]*v3.Parameter, 0, len(pathParams)+len(operationParams))
+	for _, p := range pathParams {
+		overridden := slices.ContainsFunc(operationParams, func(op *v3.Parameter) bool {
+			return op != nil && p != nil && op.Name == p.Name && op.In == p.In
+		})
+		if !overridden {
+			params = append(params, p)
 		}
-	case http.MethodPatch:
-		if item.Patch != nil {
-			params = append(params, item.Patch.Parameters...)
+	}
+	return append(params, operationParams...)
+}
+
+// RequiredPathParams will return the path parameters that must be supplied to call an operation, for example to fill
+// in a path template when generating a sample request. The path item and operation parameters are merged (see
+// MergeParams), and only the parameters that are in the path and required are returned, each name only once. The
+// operation can be nil, in which case only the path item parameters are used.
+func RequiredPathParams(pathItem *v3.PathItem, operation *v3.Operation) []*v3.Parameter {
+	var pathParams, operationParams []*v3.Parameter
+	if pathItem != nil {
+		pathParams = pathItem.Parameters
+	}
+	if operation != nil {
+		operationParams = operation.Parameters
+	}
+	var required []*v3.Parameter
+	for _, p := range MergeParams(pathParams, operationParams) {
+		if p == nil || p.In != Path || p.Required == nil || !*p.Required {
+			continue
 		}
-	case http.MethodTrace:
-		if item.Trace != nil {
-			params = append(params, item.Trace.Parameters...)
+		if !slices.ContainsFunc(required, func(r *v3.Parameter) bool { return r.Name == p.Name }) {
+			required = append(required, p)
 		}
 	}
-	return params
+	return required
 }
 
 // ExtractSecurityForOperation will extract the security requirements for the operation based on the request method.
@@ -103,6 +151,19 @@ func ExtractSecurityForOperation(request *http.Request, item *v3.PathItem) []*ba
 	return schemes
 }
 
+// ExtractSecurityForOperation is the same as the package level ExtractSecurityForOperation, except the security
+// requirements of CONNECT operations and the operations selected by the method extension are also returned (see
+// OperationCache.ExtractOperation).
+func (c *OperationCache) ExtractSecurityForOperation(request *http.Request,
+	item *v3.PathItem) []*base.SecurityRequirement {
+	if ExtractOperation(request, item) == nil {
+		if op := c.ExtractOperation(request, item); op != nil {
+			return op.Security
+		}
+	}
+	return ExtractSecurityForOperation(request, item)
+}
+
 func cast(v string) any {
 
 	if v == "true" || v == "false" {
@@ -122,60 +183,72 @@ func cast(v string) any {
 
 // ConstructParamMapFromDeepObjectEncoding will construct a map from the query parameters that are encoded as
 // deep objects. It's kind of a crazy way to do things, but hey, each to their own.
+//
+// Nested keys (such as filter[address][city]) are assembled into nested objects. A property is decoded as an array
+// of every value sent for it if its schema (or the additionalProperties schema of its object) is an array, otherwise
+// the first value is used.
 func ConstructParamMapFromDeepObjectEncoding(values []*QueryParam, sch *base.Schema) map[string]interface{} {
 	// deepObject encoding is a technique used to encode objects into query parameters. Kinda nuts.
 	decoded := make(map[string]interface{})
 	for _, v := range values {
-		if decoded[v.Key] == nil {
-
-			props := make(map[string]interface{})
-			rawValues := make([]interface{}, len(v.Values))
-			for i := range v.Values {
-				rawValues[i] = cast(v.Values[i])
-			}
-			// check if the schema for the param is an array
-			if sch != nil && slices.Contains(sch.Type, Array) {
-				props[v.Property] = rawValues
-			}
-			// check if schema has additional properties defined as an array
-			if sch != nil && sch.AdditionalProperties != nil &&
-				sch.AdditionalProperties.IsA() &&
-				slices.Contains(sch.AdditionalProperties.A.Schema().Type, Array) {
-				props[v.Property] = rawValues
-			}
-
-			if len(props) == 0 {
-				props[v.Property] = cast(v.Values[0])
-			}
+		props, ok := decoded[v.Key].(map[string]interface{})
+		if !ok {
+			props = make(map[string]interface{})
 			decoded[v.Key] = props
-		} else {
-
-			added := false
-			rawValues := make([]interface{}, len(v.Values))
-			for i := range v.Values {
-				rawValues[i] = cast(v.Values[i])
-			}
-			// check if the schema for the param is an array
-			if sch != nil && slices.Contains(sch.Type, Array) {
-				decoded[v.Key].(map[string]interface{})[v.Property] = rawValues
-				added = true
-			}
-			// check if schema has additional properties defined as an array
-			if sch != nil && sch.AdditionalProperties != nil &&
-				sch.AdditionalProperties.IsA() &&
-				slices.Contains(sch.AdditionalProperties.A.Schema().Type, Array) {
-				decoded[v.Key].(map[string]interface{})[v.Property] = rawValues
-				added = true
-			}
-			if !added {
-				decoded[v.Key].(map[string]interface{})[v.Property] = cast(v.Values[0])
+		}
+		keys := append([]string{v.Property}, v.NestedProperties...)
+		for _, key := range keys[:len(keys)-1] {
+			nested, isMap := props[key].(map[string]interface{})
+			if !isMap {
+				nested = make(map[string]interface{})
+				props[key] = nested
 			}
-
+			props = nested
+		}
+		if !DeepObjectAllowsMultipleValues(sch, v) {
+			props[keys[len(keys)-1]] = cast(v.Values[0])
+			continue
+		}
+		rawValues := make([]interface{}, len(v.Values))
+		for i := range v.Values {
+			rawValues[i] = cast(v.Values[i])
 		}
+		props[keys[len(keys)-1]] = rawValues
 	}
 	return decoded
 }
 
+// DeepObjectAllowsMultipleValues returns true if a property of a deepObject query parameter can be sent more than
+// once, because the schema of the property (or the additionalProperties schema of its object) is an array. For
+// legacy reasons, every property can be sent more than once if the schema of the parameter is an array.
+func DeepObjectAllowsMultipleValues(sch *base.Schema, qp *QueryParam) bool {
+	if sch != nil && slices.Contains(sch.Type, Array) {
+		return true
+	}
+	propSchema := deepObjectPropertySchema(sch, qp.Property)
+	for _, key := range qp.NestedProperties {
+		propSchema = deepObjectPropertySchema(propSchema, key)
+	}
+	return propSchema != nil && slices.Contains(propSchema.Type, Array)
+}
+
+// deepObjectPropertySchema returns the schema of a property of an object schema, which is the additionalProperties
+// schema if the property is not defined.
+func deepObjectPropertySchema(sch *base.Schema, property string) *base.Schema {
+	if sch == nil {
+		return nil
+	}
+	if sch.Properties != nil {
+		if proxy := sch.Properties.GetOrZero(property); proxy != nil {
+			return proxy.Schema()
+		}
+	}
+	if sch.AdditionalProperties != nil && sch.AdditionalProperties.IsA() && sch.AdditionalProperties.A != nil {
+		return sch.AdditionalProperties.A.Schema()
+	}
+	return nil
+}
+
 // ConstructParamMapFromQueryParamInput will construct a param map from an existing map of *QueryParam slices.
 func ConstructParamMapFromQueryParamInput(values map[string][]*QueryParam) map[string]interface{} {
 	decoded := make(map[string]interface{})
@@ -288,10 +361,15 @@ func ConstructParamMapFromFormEncodingArray(values []*QueryParam) map[string]int
 	decoded := make(map[string]interface{})
 	for _, v := range values {
 		props := make(map[string]interface{})
-		// explode SSV into array
+		// explode CSV into array
 		exploded := strings.Split(v.Values[0], Comma)
 		for i := range exploded {
 			if i%2 == 0 {
+				if len(exploded) == i+1 {
+					// a key without a value, nothing to pair it with.
+					props[exploded[i]] = ""
+					break
+				}
 				props[exploded[i]] = cast(exploded[i+1])
 			}
 		}
@@ -300,6 +378,47 @@ func ConstructParamMapFromFormEncodingArray(values []*QueryParam) map[string]int
 	return decoded
 }
 
+// ConstructParamMapFromExplodedFormEncoding will construct an object from query parameters that are encoded as
+// exploded form values, where each property of the object is sent as its own query key (?lat=1&lon=2).
+// Only the keys of properties defined by the schema are used, unless the schema defines no properties, in which case
+// every key that is not reserved (by another parameter) is used. Properties with an array schema collect every value
+// of their key, and keys that repeat for any other property become arrays, so the schema can reject them.
+func ConstructParamMapFromExplodedFormEncoding(values map[string][]*QueryParam, sch *base.Schema,
+	reserved []string) map[string]interface{} {
+	decoded := make(map[string]interface{})
+	for key, params := range values {
+		var propSchema *base.Schema
+		if sch != nil && sch.Properties != nil && sch.Properties.Len() > 0 {
+			proxy, ok := sch.Properties.Get(key)
+			if !ok {
+				continue
+			}
+			propSchema = proxy.Schema()
+		} else if slices.Contains(reserved, key) {
+			continue
+		}
+		var items []interface{}
+		for _, qp := range params {
+			if qp.Property != "" {
+				// deepObject encoded keys are not form values.
+				continue
+			}
+			for _, v := range qp.Values {
+				items = append(items, cast(v))
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+		if len(items) == 1 && (propSchema == nil || !slices.Contains(propSchema.Type, Array)) {
+			decoded[key] = items[0]
+			continue
+		}
+		decoded[key] = items
+	}
+	return decoded
+}
+
 // DoesFormParamContainDelimiter will determine if a form parameter contains a delimiter.
 func DoesFormParamContainDelimiter(value, style string) bool {
 	if strings.Contains(value, Comma) && (style == "" || style == Form) {
@@ -333,3 +452,207 @@ func CollapseCSVIntoSpaceDelimitedStyle(key string, values []string) string {
 func CollapseCSVIntoPipeDelimitedStyle(key string, values []string) string {
 	return fmt.Sprintf("%s=%s", key, strings.Join(values, Pipe))
 }
+
+// IsValidPathSegmentTemplate returns true if every brace in a path segment template is part of a well-formed,
+// non-empty parameter, for example '{id}' or '{year}-{month}'. Segments such as '{', '}', '{}' or '{a{b}}'
+// are malformed.
+func IsValidPathSegmentTemplate(segment string) bool {
+	open := false
+	nameLength := 0
+	for _, r := range segment {
+		switch r {
+		case '{':
+			if open {
+				return false
+			}
+			open = true
+			nameLength = 0
+		case '}':
+			if !open || nameLength == 0 {
+				return false
+			}
+			open = false
+		default:
+			if open {
+				nameLength++
+			}
+		}
+	}
+	return !open
+}
+
+// IsCompoundPathSegment returns true if a path segment template contains more than one parameter, or contains literal
+// text alongside a parameter, for example '{year}-{month}' or '{id}.json'.
+func IsCompoundPathSegment(segment string) bool {
+	return strings.Count(segment, "{") > 1 ||
+		!strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}")
+}
+
+// ExtractPathSegmentParamNames returns the names of all the parameters defined in a path segment template. Any
+// label (.), matrix (;) or explode (*) modifiers are removed from the names of non-compound segments.
+func ExtractPathSegmentParamNames(segment string) []string {
+	var names []string
+	for _, m := range compoundParamRegex.FindAllStringSubmatch(segment, -1) {
+		name := m[1]
+		if !IsCompoundPathSegment(segment) {
+			name = strings.TrimSuffix(name, Asterisk)
+			name = strings.TrimPrefix(strings.TrimPrefix(name, Period), SemiColon)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// MatchCompoundPathSegment extracts the value of each parameter in a compound segment template from a submitted
+// segment. If the submitted segment does not match the template, no values are returned.
+func MatchCompoundPathSegment(segment, value string) map[string]string {
+	return matchCompoundPathSegment(segment, value, false)
+}
+
+// MatchCompoundPathSegmentFold is the same as MatchCompoundPathSegment, except the literal text of the segment
+// template (such as the '.json' of '{file}.json') is matched case-insensitively. Parameter values are always
+// returned exactly as they were submitted.
+func MatchCompoundPathSegmentFold(segment, value string) map[string]string {
+	return matchCompoundPathSegment(segment, value, true)
+}
+
+func matchCompoundPathSegment(segment, value string, foldCase bool) map[string]string {
+	names := ExtractPathSegmentParamNames(segment)
+	values := make(map[string]string)
+	rx := compoundSegmentRegex(segment, len(names), foldCase)
+	if rx == nil {
+		return values
+	}
+	if m := rx.FindStringSubmatch(value); m != nil {
+		for n, name := range names {
+			values[name] = m[n+1]
+		}
+	}
+	return values
+}
+
+// compoundSegmentRegex returns the expression that captures the value of each of the parameters of a compound
+// segment template, compiling it the first time the template is matched. If it cannot be compiled, nil is returned.
+func compoundSegmentRegex(segment string, params int, foldCase bool) *regexp.Regexp {
+	key := compoundSegmentKey{segment: segment, foldCase: foldCase}
+	if rx, ok := compoundSegmentRegexes.Load(key); ok {
+		return rx.(*regexp.Regexp)
+	}
+	var sb strings.Builder
+	if foldCase {
+		sb.WriteString("(?i)") // only changes how literals match, the captured values keep their case.
+	}
+	sb.WriteString("^")
+	for _, literal := range compoundParamRegex.Split(segment, -1)[:params] {
+		sb.WriteString(regexp.QuoteMeta(literal))
+		sb.WriteString("(.+?)")
+	}
+	sb.WriteString(regexp.QuoteMeta(segment[strings.LastIndex(segment, "}")+1:]))
+	sb.WriteString("$")
+
+	rx, _ := regexp.Compile(sb.String()) // nil if it cannot be compiled, which is kept so it is not compiled again.
+	stored, _ := compoundSegmentRegexes.LoadOrStore(key, rx)
+	return stored.(*regexp.Regexp)
+}
+
+// ReservedPathParamNames returns the names of the path parameters that allow reserved characters in their values,
+// because they set 'allowReserved', or an 'x-allow-reserved' extension of true.
+func ReservedPathParamNames(params []*v3.Parameter) []string {
+	var names []string
+	for _, p := range params {
+		if p == nil || p.In != Path {
+			continue
+		}
+		allowed := p.AllowReserved
+		if p.Extensions != nil {
+			if ext, ok := p.Extensions.Get(XAllowReserved); ok && ext != nil {
+				allowed = allowed || ext.Value == "true"
+			}
+		}
+		if allowed && !slices.Contains(names, p.Name) {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// MatchReservedPathSegments will match a request path against a path template, where the values of the reserved
+// parameters can contain reserved characters, including '/'. A reserved parameter must fill a whole segment of the
+// template (e.g. '/files/{path}'), and matches one or more segments of the request path greedily. If the request path
+// matches, it is returned split into one value for each segment of the template, so the values of reserved
+// parameters keep their slashes.
+func MatchReservedPathSegments(template, requestPath string, reserved []string) ([]string, bool) {
+	return matchReservedPath(template, requestPath, reserved, false)
+}
+
+// MatchReservedPathSuffix is the same as MatchReservedPathSegments, except the template is matched against the
+// shortest tail of the request path that it matches, for request paths that may still have their base path.
+func MatchReservedPathSuffix(template, requestPath string, reserved []string) ([]string, bool) {
+	return matchReservedPath(template, requestPath, reserved, true)
+}
+
+// reservedPathRegexes keeps the expression compiled for each template and set of reserved parameters, as paths are
+// matched against every request.
+var reservedPathRegexes sync.Map
+
+type reservedPathKey struct {
+	template string
+	reserved string
+	suffix   bool
+}
+
+func matchReservedPath(template, requestPath string, reserved []string, suffix bool) ([]string, bool) {
+	rx := reservedPathRegex(template, reserved, suffix)
+	if rx == nil {
+		return nil, false
+	}
+	m := rx.FindStringSubmatch(requestPath)
+	if m == nil {
+		return nil, false
+	}
+	return m[1:], true
+}
+
+// reservedPathRegex returns the expression that captures each segment of a template, compiling it the first time
+// the template is matched. A suffix expression starts with a greedy prefix that ends at a slash, so the shortest tail
+// of whole segments is captured.
+func reservedPathRegex(template string, reserved []string, suffix bool) *regexp.Regexp {
+	key := reservedPathKey{template: template, reserved: strings.Join(reserved, "\x00"), suffix: suffix}
+	if rx, ok := reservedPathRegexes.Load(key); ok {
+		return rx.(*regexp.Regexp)
+	}
+	templateSegments := strings.Split(template, Slash)
+	var sb strings.Builder
+	sb.WriteString("^")
+	switch {
+	case suffix && strings.HasPrefix(template, Slash):
+		sb.WriteString("(?:.*)")
+	case suffix:
+		sb.WriteString("(?:.*/)?")
+	}
+	for x, segment := range templateSegments {
+		if x > 0 {
+			sb.WriteString(Slash)
+		}
+		switch {
+		case !strings.Contains(segment, "{") || !IsValidPathSegmentTemplate(segment):
+			sb.WriteString("(" + regexp.QuoteMeta(segment) + ")")
+		case !IsCompoundPathSegment(segment) && slices.Contains(reserved, ExtractPathSegmentParamNames(segment)[0]):
+			sb.WriteString("(.+)")
+		default:
+			sb.WriteString("([^/]+)")
+		}
+	}
+	sb.WriteString("$")
+
+	rx, _ := regexp.Compile(sb.String()) // nil if it cannot be compiled, which is kept so it is not compiled again.
+	stored, _ := reservedPathRegexes.LoadOrStore(key, rx)
+	return stored.(*regexp.Regexp)
+}
+
+// HasEncodedSlashes returns true if the path of a request contains an encoded slash ('%2F' or '%2f'). The encoded
+// slashes of such a path are left encoded while it is matched, along with any '%' of the path (as '%25'), so that
+// every '%' of a path parameter value is an escape.
+func HasEncodedSlashes(request *http.Request) bool {
+	return strings.Contains(strings.ToUpper(request.URL.EscapedPath()), EncodedSlash)
+}
@@ -0,0 +1,260 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package parameters
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// BindTag is the struct tag that names the parameter a field is bound to, along with its location, for example
+// `openapi:"id,in=path"`. If the location is omitted, the first parameter of the operation with the name is bound.
+const BindTag = "openapi"
+
+// anyLocation describes the location of a tag that does not name one.
+const anyLocation = "path, query, header or cookie"
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// BindParameters will validate the path, query, header and cookie parameters of an operation, and populate the
+// fields of a struct with them, so strongly-typed handlers can use the values directly. The target must be a pointer
+// to a struct, and each field is bound to the parameter named by its 'openapi' tag (see BindTag). The parameters of
+// the path item and the operation are merged (see helpers.MergeParams), so either can be bound.
+//
+// Each value is validated against its parameter schema (see ValidateParameterValue, which applies the options too)
+// before it is bound, so a field is only populated if its value is valid. Fields can be strings, booleans, integers,
+// floats, types that implement encoding.TextUnmarshaler, pointers to any of those (which are left nil when the
+// parameter is not sent), or slices of them for array parameters.
+//
+// An operation alone is not enough to bind every parameter: parameters shared by all the operations of a path are
+// declared on its path item, and neither knows the path template the request matched, so the raw values of path
+// parameters are supplied as pathValues, keyed by name. They can come from a router, or from the Params of a
+// paths.MatchPath result. The path item can be nil if the operation declares all of its parameters.
+//
+// Every failure is returned as a validation error: a required parameter that is missing, a value that is invalid, a
+// value that does not fit the field, or a tag that names a parameter the operation does not define.
+func BindParameters(request *http.Request, pathItem *v3.PathItem, operation *v3.Operation,
+	pathValues map[string]string, target any, opts ...config.Option) []*errors.ValidationError {
+
+	value := reflect.ValueOf(target)
+	if target == nil || value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return []*errors.ValidationError{errors.ParameterBindTargetInvalid(target)}
+	}
+	options := config.NewValidationOptions(opts...)
+
+	var pathParams, operationParams []*v3.Parameter
+	if pathItem != nil {
+		pathParams = pathItem.Parameters
+	}
+	if operation != nil {
+		operationParams = operation.Parameters
+	}
+	params := helpers.MergeParams(pathParams, operationParams)
+
+	var validationErrors []*errors.ValidationError
+	structValue := value.Elem()
+	for i := 0; i < structValue.NumField(); i++ {
+		field := structValue.Type().Field(i)
+		tag, ok := field.Tag.Lookup(BindTag)
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, location := parseBindTag(tag, field.Name)
+		param := findBindParameter(params, name, location)
+		if param == nil {
+			if location == "" {
+				location = anyLocation
+			}
+			validationErrors = append(validationErrors, errors.ParameterBindUndefined(field.Name, name, location))
+			continue
+		}
+
+		raw, found := bindParameterValue(request, param, pathValues)
+		if !found {
+			if param.Required != nil && *param.Required {
+				validationErrors = append(validationErrors, bindParameterMissing(param))
+			}
+			continue
+		}
+		if paramErrors := ValidateParameterValue(param, raw, opts...); len(paramErrors) > 0 {
+			validationErrors = append(validationErrors, paramErrors...)
+			continue
+		}
+		if err := setBindField(structValue.Field(i), param, raw); err != nil {
+			validationErrors = append(validationErrors, errors.ParameterBindFailed(param, field.Name, raw, err))
+		}
+	}
+	errors.PopulateValidationErrors(validationErrors, request, "")
+	errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+	errors.FormatValidationErrors(validationErrors, options.MessageFormatterFor)
+	return validationErrors
+}
+
+// parseBindTag returns the parameter name and location of an 'openapi' tag. A tag without a name is bound to the
+// parameter named after the field.
+func parseBindTag(tag string, fieldName string) (string, string) {
+	name, rest, _ := strings.Cut(tag, helpers.Comma)
+	if name == "" {
+		name = fieldName
+	}
+	var location string
+	for _, option := range strings.Split(rest, helpers.Comma) {
+		if in, ok := strings.CutPrefix(strings.TrimSpace(option), "in="); ok {
+			location = in
+		}
+	}
+	return name, location
+}
+
+// findBindParameter returns the parameter of an operation with a name (and location, if there is one). Header names
+// are not case-sensitive.
+func findBindParameter(params []*v3.Parameter, name string, location string) *v3.Parameter {
+	for _, param := range params {
+		if param == nil || (location != "" && param.In != location) {
+			continue
+		}
+		if param.Name == name || (param.In == helpers.Header && strings.EqualFold(param.Name, name)) {
+			return param
+		}
+	}
+	return nil
+}
+
+// bindParameterValue returns the raw value of a parameter sent with a request (or of a path parameter, supplied with
+// the path values). Values that are sent more than once (such as exploded query arrays) are joined with the delimiter
+// of the parameter style, so they are validated and bound as a single array.
+func bindParameterValue(request *http.Request, param *v3.Parameter, pathValues map[string]string) (string, bool) {
+	switch param.In {
+	case helpers.Path:
+		value, ok := pathValues[param.Name]
+		if !ok || value == "" {
+			return "", false
+		}
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		return rawPathParamValue(param, strings.HasPrefix(value, helpers.Period),
+			strings.HasPrefix(value, helpers.SemiColon), value), true
+	case helpers.Query:
+		values, ok := request.URL.Query()[param.Name]
+		if !ok {
+			return "", false
+		}
+		delimiter := helpers.Comma
+		switch param.Style {
+		case helpers.SpaceDelimited:
+			delimiter = helpers.Space
+		case helpers.PipeDelimited:
+			delimiter = helpers.Pipe
+		}
+		return strings.Join(values, delimiter), true
+	case helpers.Header:
+		values := request.Header.Values(param.Name)
+		if len(values) == 0 {
+			return "", false
+		}
+		return strings.Join(values, helpers.Comma), true
+	case helpers.Cookie:
+		cookie, err := request.Cookie(param.Name)
+		if err != nil {
+			return "", false
+		}
+		return cookie.Value, true
+	}
+	return "", false
+}
+
+// bindParameterMissing returns the error for a required parameter that was not sent, as the validator of its
+// location would report it.
+func bindParameterMissing(param *v3.Parameter) *errors.ValidationError {
+	switch param.In {
+	case helpers.Path:
+		return errors.PathParameterMissing(param)
+	case helpers.Header:
+		return errors.HeaderParameterMissing(param)
+	case helpers.Cookie:
+		return errors.CookieParameterMissing(param)
+	}
+	return errors.QueryParameterMissing(param)
+}
+
+// setBindField sets a struct field to a raw parameter value, which has already been validated.
+func setBindField(field reflect.Value, param *v3.Parameter, raw string) error {
+	if field.Kind() == reflect.Slice && !field.Type().Implements(textUnmarshalerType) &&
+		!reflect.PointerTo(field.Type()).Implements(textUnmarshalerType) {
+		var items []string
+		if param.Style == helpers.LabelStyle {
+			items = strings.Split(raw, helpers.Period)
+		} else {
+			items = helpers.ExplodeQueryValue(raw, param.Style)
+		}
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setBindValue(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setBindValue(field, raw)
+}
+
+// setBindValue converts a raw value into the type of a field, and sets it.
+func setBindValue(field reflect.Value, raw string) error {
+	if field.Kind() == reflect.Pointer {
+		value := reflect.New(field.Type().Elem())
+		if err := setBindValue(value.Elem(), raw); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+	if field.CanAddr() {
+		if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return unmarshaler.UnmarshalText([]byte(raw))
+		}
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("a field of type '%s' is not supported", field.Type())
+	}
+	return nil
+}
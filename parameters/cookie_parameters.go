@@ -5,6 +5,7 @@ package parameters
 
 import (
 	"fmt"
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
@@ -23,7 +24,7 @@ func (v *paramValidator) ValidateCookieParams(request *http.Request) (bool, []*e
 	var errs []*errors.ValidationError
 
 	if v.pathItem == nil {
-		pathItem, errs, foundPath = paths.FindPath(request, v.document)
+		pathItem, errs, foundPath = paths.FindPath(request, v.document, config.WithExistingOpts(v.options))
 		if pathItem == nil || errs != nil {
 			v.errors = errs
 			return false, errs
@@ -33,11 +34,15 @@ func (v *paramValidator) ValidateCookieParams(request *http.Request) (bool, []*e
 		foundPath = v.pathValue
 	}
 
-	// extract params for the operation
-	var params = helpers.ExtractParamsForOperation(request, pathItem)
+	// extract params for the operation, any that are missing because of an unresolved reference are rebuilt first
+	// (unresolved references are reported by ValidatePathParams).
+	v.operations.ResolveParameterRefs(request, pathItem)
+	var params = v.operations.ExtractParamsForOperation(request, pathItem)
 	var validationErrors []*errors.ValidationError
+	var applied appliedParameter
 	for _, p := range params {
 		if p.In == helpers.Cookie {
+			applied.apply(validationErrors, p)
 			for _, cookie := range request.Cookies() {
 				if cookie.Name == p.Name { // cookies are case-sensitive, an exact match is required
 
@@ -91,14 +96,12 @@ func (v *paramValidator) ValidateCookieParams(request *http.Request) (bool, []*e
 							}
 						case helpers.Array:
 
-							if !p.IsExploded() {
-								// well we're already in an array, so we need to check the items schema
-								// to ensure this array items matches the type
-								// only check if items is a schema, not a boolean
-								if sch.Items.IsA() {
-									validationErrors = append(validationErrors,
-										ValidateCookieArray(sch, p, cookie.Value)...)
-								}
+							// well we're already in an array, so we need to check the items schema
+							// to ensure this array items matches the type
+							// only check if items is a schema, not a boolean
+							if sch.Items.IsA() {
+								validationErrors = append(validationErrors,
+									ValidateCookieArray(sch, p, cookie.Value)...)
 							}
 
 						case helpers.String:
@@ -125,7 +128,11 @@ func (v *paramValidator) ValidateCookieParams(request *http.Request) (bool, []*e
 		}
 	}
 
+	applied.apply(validationErrors, nil)
+
 	errors.PopulateValidationErrors(validationErrors, request, foundPath)
+	errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+	errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
 
 	if len(validationErrors) > 0 {
 		return false, validationErrors
@@ -5,6 +5,8 @@ package parameters
 
 import (
 	"github.com/pb33f/libopenapi"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
 	"github.com/stretchr/testify/assert"
 	"net/http"
@@ -558,3 +560,194 @@ paths:
 	assert.Len(t, errors, 1)
 	assert.Equal(t, "Instead of '2500', use one of the allowed values: '1, 2, 99'", errors[0].HowToFix)
 }
+
+func TestNewValidator_CookieParamArrayItemMaximum(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/beef:
+    get:
+      parameters:
+        - name: PattyPreference
+          in: cookie
+          required: true
+          schema:
+            type: array
+            items:
+              type: integer
+              maximum: 5`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/beef", nil)
+	request.AddCookie(&http.Cookie{Name: "PattyPreference", Value: "1,2,10"})
+
+	valid, errors := v.ValidateCookieParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Cookie array parameter 'PattyPreference' failed to validate", errors[0].Message)
+}
+
+func TestNewValidator_CookieParamArrayExploded(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/beef:
+    get:
+      parameters:
+        - name: PattyPreference
+          in: cookie
+          required: true
+          explode: true
+          schema:
+            type: array
+            items:
+              type: integer`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/beef", nil)
+	request.AddCookie(&http.Cookie{Name: "PattyPreference", Value: "1"})
+	request.AddCookie(&http.Cookie{Name: "PattyPreference", Value: "beef"})
+
+	valid, errors := v.ValidateCookieParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Cookie array parameter 'PattyPreference' is not a valid number", errors[0].Message)
+}
+
+func TestNewValidator_ComponentParameterRefs(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+components:
+  parameters:
+    Region:
+      name: region
+      in: path
+      required: true
+      schema:
+        type: integer
+    Force:
+      $ref: '#/components/parameters/ForceFlag'
+    ForceFlag:
+      name: force
+      in: query
+      required: true
+      schema:
+        type: boolean
+    Chef:
+      name: X-Chef
+      in: header
+      required: true
+      schema:
+        $ref: '#/components/schemas/Count'
+    Sessions:
+      name: sessions
+      in: cookie
+      required: true
+      schema:
+        type: array
+        items:
+          $ref: '#/components/schemas/Count'
+  schemas:
+    Count:
+      type: integer
+paths:
+  /cache/{region}:
+    parameters:
+      - $ref: '#/components/parameters/Region'
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Force'
+        - $ref: '#/components/parameters/Chef'
+        - $ref: '#/components/parameters/Sessions'`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/cache/eu?force=maybe", nil)
+	request.Header.Set("X-Chef", "bob")
+	request.Header.Set("Cookie", "sessions=1,two")
+
+	valid, errors := v.ValidatePathParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'region' is not a valid number", errors[0].Message)
+
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Query parameter 'force' is not a valid boolean", errors[0].Message)
+
+	valid, errors = v.ValidateHeaderParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Header parameter 'X-Chef' is not a valid number", errors[0].Message)
+
+	valid, errors = v.ValidateCookieParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Cookie array parameter 'sessions' is not a valid number", errors[0].Message)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/cache/1?force=true", nil)
+	request.Header.Set("X-Chef", "2")
+	request.Header.Set("Cookie", "sessions=1,2")
+	for _, validate := range []func(*http.Request) (bool, []*liberrors.ValidationError){
+		v.ValidatePathParams, v.ValidateQueryParams, v.ValidateHeaderParams, v.ValidateCookieParams} {
+		valid, errors = validate(request)
+		assert.True(t, valid)
+		assert.Len(t, errors, 0)
+	}
+}
+
+func TestNewValidator_UnresolvedParameterRef(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+components:
+  parameters:
+    Sessions:
+      name: sessions
+      in: cookie
+      required: true
+      schema:
+        type: array
+        items:
+          type: integer
+paths:
+  /cache:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Missing'
+        - $ref: '#/components/parameters/Sessions'`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model() // the missing reference is reported, however the model is still built.
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/cache", nil)
+	request.Header.Set("Cookie", "sessions=1,two")
+
+	// the parameters next to the unresolved reference are still validated.
+	valid, errors := v.ValidateCookieParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Cookie array parameter 'sessions' is not a valid number", errors[0].Message)
+
+	// and the unresolved reference is reported once, by the path parameters.
+	valid, errors = v.ValidatePathParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Parameter reference '#/components/parameters/Missing' cannot be resolved", errors[0].Message)
+	assert.Equal(t, helpers.ParameterRefUnresolved, errors[0].ValidationSubType)
+	assert.Equal(t, 16, errors[0].SpecLine)
+	assert.Equal(t, "/cache", errors[0].SpecPath)
+}
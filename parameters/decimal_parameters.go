@@ -0,0 +1,148 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package parameters
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// a decimal is an optional sign, digits, and an optional fraction. Exponents are not allowed, as they are not
+// fixed precision.
+var decimalRegex = regexp.MustCompile(`^[+-]?\d+(?:\.(\d+))?$`)
+
+// isDecimalFormat returns true if a schema declares a 'decimal' format.
+func isDecimalFormat(sch *base.Schema) bool {
+	return sch != nil && sch.Format == helpers.DecimalFormat
+}
+
+// validateDecimalPathParam validates a path parameter value with a 'decimal' format, without converting it through
+// float64, so no precision is lost. The value must be well-formed, have no more fractional digits than the scale
+// declared by an 'x-scale' extension, and satisfy any multipleOf, minimum, maximum and enum keywords exactly.
+func validateDecimalPathParam(sch *base.Schema, p *v3.Parameter, value string) []*errors.ValidationError {
+	m := decimalRegex.FindStringSubmatch(value)
+	if m == nil {
+		return []*errors.ValidationError{errors.IncorrectPathParamDecimal(p, value, sch)}
+	}
+	decimal, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return []*errors.ValidationError{errors.IncorrectPathParamDecimal(p, value, sch)}
+	}
+
+	var validationErrors []*errors.ValidationError
+	if scale, ok := decimalScale(sch); ok && len(m[1]) > scale {
+		validationErrors = append(validationErrors, errors.PathParamDecimalConstraint(p, value, sch,
+			helpers.XScale, strconv.Itoa(scale), fmt.Sprintf(errors.HowToFixParamDecimalScale, value, scale)))
+	}
+	if sch.MultipleOf != nil && *sch.MultipleOf > 0 {
+		multipleOf := exactDecimal(*sch.MultipleOf)
+		if !new(big.Rat).Quo(decimal, multipleOf).IsInt() {
+			validationErrors = append(validationErrors, errors.PathParamDecimalConstraint(p, value, sch,
+				"multipleOf", multipleOf.FloatString(decimalPlaces(multipleOf)),
+				fmt.Sprintf(errors.HowToFixParamInvalidMultipleOf, value, *sch.MultipleOf)))
+		}
+	}
+	if sch.Minimum != nil {
+		exclusive := sch.ExclusiveMinimum != nil && sch.ExclusiveMinimum.IsA() && sch.ExclusiveMinimum.A
+		if c := decimal.Cmp(exactDecimal(*sch.Minimum)); c < 0 || (exclusive && c == 0) {
+			howToFix := fmt.Sprintf(errors.HowToFixParamInvalidMinimum, value, *sch.Minimum)
+			if exclusive {
+				howToFix = fmt.Sprintf(errors.HowToFixParamInvalidExclusiveMinimum, value, *sch.Minimum)
+			}
+			validationErrors = append(validationErrors, errors.PathParamDecimalConstraint(p, value, sch,
+				"minimum", fmt.Sprint(*sch.Minimum), howToFix))
+		}
+	}
+	if sch.ExclusiveMinimum != nil && sch.ExclusiveMinimum.IsB() &&
+		decimal.Cmp(exactDecimal(sch.ExclusiveMinimum.B)) <= 0 {
+		validationErrors = append(validationErrors, errors.PathParamDecimalConstraint(p, value, sch,
+			"exclusiveMinimum", fmt.Sprint(sch.ExclusiveMinimum.B),
+			fmt.Sprintf(errors.HowToFixParamInvalidExclusiveMinimum, value, sch.ExclusiveMinimum.B)))
+	}
+	if sch.Maximum != nil {
+		exclusive := sch.ExclusiveMaximum != nil && sch.ExclusiveMaximum.IsA() && sch.ExclusiveMaximum.A
+		if c := decimal.Cmp(exactDecimal(*sch.Maximum)); c > 0 || (exclusive && c == 0) {
+			howToFix := fmt.Sprintf(errors.HowToFixParamInvalidMaximum, value, *sch.Maximum)
+			if exclusive {
+				howToFix = fmt.Sprintf(errors.HowToFixParamInvalidExclusiveMaximum, value, *sch.Maximum)
+			}
+			validationErrors = append(validationErrors, errors.PathParamDecimalConstraint(p, value, sch,
+				"maximum", fmt.Sprint(*sch.Maximum), howToFix))
+		}
+	}
+	if sch.ExclusiveMaximum != nil && sch.ExclusiveMaximum.IsB() &&
+		decimal.Cmp(exactDecimal(sch.ExclusiveMaximum.B)) >= 0 {
+		validationErrors = append(validationErrors, errors.PathParamDecimalConstraint(p, value, sch,
+			"exclusiveMaximum", fmt.Sprint(sch.ExclusiveMaximum.B),
+			fmt.Sprintf(errors.HowToFixParamInvalidExclusiveMaximum, value, sch.ExclusiveMaximum.B)))
+	}
+	if sch.Enum != nil && !decimalEnumContains(sch, decimal) {
+		validationErrors = append(validationErrors, errors.IncorrectPathParamEnum(p, value, sch))
+	}
+	return validationErrors
+}
+
+// decimalScale returns the maximum number of fractional digits declared by the 'x-scale' extension of a schema.
+func decimalScale(sch *base.Schema) (int, bool) {
+	if sch.Extensions == nil {
+		return 0, false
+	}
+	ext, ok := sch.Extensions.Get(helpers.XScale)
+	if !ok || ext == nil {
+		return 0, false
+	}
+	scale, err := strconv.Atoi(ext.Value)
+	if err != nil || scale < 0 {
+		return 0, false
+	}
+	return scale, true
+}
+
+// decimalEnumContains returns true if the value is exactly equal to any enum value of the schema. Enum values are
+// compared as they are written in the specification, so they are not rounded either.
+func decimalEnumContains(sch *base.Schema, value *big.Rat) bool {
+	for _, enumVal := range sch.Enum {
+		if enumParsed, ok := new(big.Rat).SetString(strings.TrimSpace(enumVal.Value)); ok && enumParsed.Cmp(value) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// exactDecimal converts a schema keyword value into the decimal it was written as (e.g. 0.01), rather than the
+// binary approximation held by the float64.
+func exactDecimal(value float64) *big.Rat {
+	decimal, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	return decimal
+}
+
+// decimalPlaces returns the number of fractional digits needed to render a decimal exactly.
+func decimalPlaces(value *big.Rat) int {
+	places := 0
+	scaled := new(big.Rat).Set(value)
+	for !scaled.IsInt() {
+		scaled.Mul(scaled, big.NewRat(10, 1))
+		places++
+	}
+	return places
+}
+
+// rawPathParamValue returns the value of a path parameter, without any label or matrix style prefix.
+func rawPathParamValue(p *v3.Parameter, isLabel bool, isMatrix bool, paramValue string) string {
+	if isLabel && p.Style == helpers.LabelStyle {
+		return paramValue[1:]
+	}
+	if isMatrix && p.Style == helpers.MatrixStyle {
+		return strings.Replace(paramValue[1:], fmt.Sprintf("%s=", p.Name), "", 1)
+	}
+	return paramValue
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package parameters
+
+import (
+	"net/http"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/paths"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// FindPathAndValidate is the same as paths.FindPath, except the parameters of the matched operation are validated
+// as part of the lookup, so a single call can find the path and validate the request. The locations of the parameters
+// that are validated are chosen with config.WithValidateLocations, by default only path parameters are validated.
+//
+// If no path is found, the errors explain why, as they do for paths.FindPath. Otherwise, the path item and the path
+// that was found are returned, along with the errors of every parameter that failed validation.
+func FindPathAndValidate(request *http.Request, document *v3.Document,
+	opts ...config.Option) (*v3.PathItem, []*errors.ValidationError, string) {
+
+	options := config.NewValidationOptions(opts...)
+	pathItem, errs, foundPath := paths.FindPath(request, document, config.WithExistingOpts(options))
+	if pathItem == nil || errs != nil {
+		return pathItem, errs, foundPath
+	}
+
+	locations := options.ValidateLocations
+	if locations == 0 {
+		locations = config.PathLocation
+	}
+
+	v := NewParameterValidator(document, config.WithExistingOpts(options))
+	v.SetPathItem(pathItem, foundPath)
+
+	validators := []struct {
+		location config.ParameterLocations
+		validate func(request *http.Request) (bool, []*errors.ValidationError)
+	}{
+		{config.PathLocation, v.ValidatePathParams},
+		{config.QueryLocation, v.ValidateQueryParams},
+		{config.HeaderLocation, v.ValidateHeaderParams},
+		{config.CookieLocation, v.ValidateCookieParams},
+	}
+	var validationErrors []*errors.ValidationError
+	for _, validator := range validators {
+		if locations&validator.location != 0 {
+			_, locationErrors := validator.validate(request)
+			validationErrors = append(validationErrors, locationErrors...)
+		}
+	}
+	return pathItem, validationErrors, foundPath
+}
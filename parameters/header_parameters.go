@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
@@ -22,7 +23,7 @@ func (v *paramValidator) ValidateHeaderParams(request *http.Request) (bool, []*e
 	var specPath string
 	var errs []*errors.ValidationError
 	if v.pathItem == nil {
-		pathItem, errs, specPath = paths.FindPath(request, v.document)
+		pathItem, errs, specPath = paths.FindPath(request, v.document, config.WithExistingOpts(v.options))
 		if pathItem == nil || errs != nil {
 			v.errors = errs
 			return false, errs
@@ -32,13 +33,17 @@ func (v *paramValidator) ValidateHeaderParams(request *http.Request) (bool, []*e
 		specPath = v.pathValue
 	}
 
-	// extract params for the operation
-	params := helpers.ExtractParamsForOperation(request, pathItem)
+	// extract params for the operation, any that are missing because of an unresolved reference are rebuilt first
+	// (unresolved references are reported by ValidatePathParams).
+	v.operations.ResolveParameterRefs(request, pathItem)
+	params := v.operations.ExtractParamsForOperation(request, pathItem)
 
 	var validationErrors []*errors.ValidationError
 	seenHeaders := make(map[string]bool)
+	var applied appliedParameter
 	for _, p := range params {
 		if p.In == helpers.Header {
+			applied.apply(validationErrors, p)
 
 			seenHeaders[strings.ToLower(p.Name)] = true
 			if param := request.Header.Get(p.Name); param != "" {
@@ -111,11 +116,11 @@ func (v *paramValidator) ValidateHeaderParams(request *http.Request) (bool, []*e
 						}
 
 					case helpers.Array:
-						if !p.IsExploded() { // only unexploded arrays are supported for cookie params
-							if sch.Items.IsA() {
-								validationErrors = append(validationErrors,
-									ValidateHeaderArray(sch, p, param)...)
-							}
+						// header arrays are always encoded as CSV (the simple style), regardless of explode. A
+						// header that is sent more than once is the same as one header with the values joined.
+						if sch.Items != nil && sch.Items.IsA() {
+							validationErrors = append(validationErrors,
+								ValidateHeaderArray(sch, p, strings.Join(request.Header.Values(p.Name), helpers.Comma))...)
 						}
 
 					case helpers.String:
@@ -145,7 +150,11 @@ func (v *paramValidator) ValidateHeaderParams(request *http.Request) (bool, []*e
 		}
 	}
 
+	applied.apply(validationErrors, nil)
+
 	errors.PopulateValidationErrors(validationErrors, request, specPath)
+	errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+	errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
 
 	if len(validationErrors) > 0 {
 		return false, validationErrors
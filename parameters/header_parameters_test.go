@@ -633,3 +633,86 @@ paths:
 	assert.Equal(t, "Instead of '1200', "+
 		"use one of the allowed values: '1, 2, 99'", errors[0].HowToFix)
 }
+
+func TestNewValidator_HeaderParamArrayItemPattern_Exploded(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /vending/drinks:
+    get:
+      parameters:
+        - name: coffeeCups
+          in: header
+          required: true
+          explode: true
+          schema:
+            type: array
+            items:
+              type: string
+              pattern: ^[a-z]+$`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/vending/drinks", nil)
+	request.Header.Set("coffeecups", "latte,mocha,FLAT-WHITE")
+
+	valid, errors := v.ValidateHeaderParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Header array parameter 'coffeeCups' failed to validate", errors[0].Message)
+}
+
+func TestNewValidator_HeaderParamArrayInteger(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      parameters:
+        - name: X-Ids
+          in: header
+          required: true
+          explode: true
+          schema:
+            type: array
+            items:
+              type: integer
+              maximum: 10`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	// explode makes no difference to an array header, the items are always separated by commas.
+	for _, ids := range []string{"1,2,3", "1, 2, 3", "7"} {
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+		request.Header.Set("X-Ids", ids)
+		valid, errors := v.ValidateHeaderParams(request)
+		assert.True(t, valid, ids)
+		assert.Empty(t, errors, ids)
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+	request.Header.Set("X-Ids", "1,two,3")
+	valid, errors := v.ValidateHeaderParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Header array parameter 'X-Ids' is not a valid number", errors[0].Message)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+	request.Header.Set("X-Ids", "1,2.5,30")
+	valid, errors = v.ValidateHeaderParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 2)
+
+	// a header sent more than once is validated as one list of items.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+	request.Header.Add("X-Ids", "1,2")
+	request.Header.Add("X-Ids", "11")
+	valid, errors = v.ValidateHeaderParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Header array parameter 'X-Ids' failed to validate", errors[0].Message)
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package parameters
+
+import (
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// ParameterExample is an example value declared by a parameter, and the result of validating it against the schema
+// of the parameter.
+type ParameterExample struct {
+	// Name is the name of the example in 'examples', or empty for the 'example' of the parameter.
+	Name string
+
+	// Value is the example, serialized as it would be sent in a request for the style of the parameter.
+	Value string
+
+	// Valid is true if the value passes validation against the schema of the parameter.
+	Valid bool
+
+	// Errors holds the validation errors of the value, if it is not valid.
+	Errors []*errors.ValidationError
+}
+
+// ParameterExamples will return the 'example' and 'examples' values declared by a parameter, each validated against
+// the schema of the parameter (see ValidateParameterValue), so valid and invalid requests can be generated from a
+// contract. The 'example' comes first, followed by 'examples' in the order they are defined. Examples that only have
+// an external value are skipped, as there is no value to validate.
+func ParameterExamples(param *v3.Parameter) []ParameterExample {
+	if param == nil {
+		return nil
+	}
+	var examples []ParameterExample
+	if param.Example != nil {
+		examples = append(examples, newParameterExample(param, "", param.Example))
+	}
+	for pair := orderedmap.First(param.Examples); pair != nil; pair = pair.Next() {
+		if pair.Value() != nil && pair.Value().Value != nil {
+			examples = append(examples, newParameterExample(param, pair.Key(), pair.Value().Value))
+		}
+	}
+	return examples
+}
+
+func newParameterExample(param *v3.Parameter, name string, node *yaml.Node) ParameterExample {
+	value := serializeParameterExample(param, node)
+	validationErrors := ValidateParameterValue(param, value)
+	return ParameterExample{
+		Name:   name,
+		Value:  value,
+		Valid:  len(validationErrors) == 0,
+		Errors: validationErrors,
+	}
+}
+
+// serializeParameterExample renders an example as the raw value of a parameter. Arrays are joined using the
+// delimiter of the parameter style, and objects are rendered as comma separated keys and values (or key=value pairs
+// if the parameter is exploded).
+func serializeParameterExample(param *v3.Parameter, node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			return serializeParameterExample(param, node.Content[0])
+		}
+	case yaml.SequenceNode:
+		items := make([]string, len(node.Content))
+		for i, item := range node.Content {
+			items[i] = item.Value
+		}
+		switch param.Style {
+		case helpers.LabelStyle:
+			return strings.Join(items, helpers.Period)
+		case helpers.SpaceDelimited:
+			return strings.Join(items, helpers.Space)
+		case helpers.PipeDelimited:
+			return strings.Join(items, helpers.Pipe)
+		}
+		return strings.Join(items, helpers.Comma)
+	case yaml.MappingNode:
+		var props []string
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if param.IsExploded() {
+				props = append(props, node.Content[i].Value+helpers.Equals+node.Content[i+1].Value)
+			} else {
+				props = append(props, node.Content[i].Value, node.Content[i+1].Value)
+			}
+		}
+		return strings.Join(props, helpers.Comma)
+	}
+	return node.Value
+}
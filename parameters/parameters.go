@@ -4,7 +4,9 @@
 package parameters
 
 import (
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"net/http"
 )
@@ -53,13 +55,40 @@ func (v *paramValidator) SetPathItem(path *v3.PathItem, pathValue string) {
 }
 
 // NewParameterValidator will create a new ParameterValidator from an OpenAPI 3+ document
-func NewParameterValidator(document *v3.Document) ParameterValidator {
-	return &paramValidator{document: document}
+func NewParameterValidator(document *v3.Document, opts ...config.Option) ParameterValidator {
+	options := config.NewValidationOptions(opts...)
+	return &paramValidator{
+		document:   document,
+		options:    options,
+		operations: options.OperationCacheFor(document),
+	}
 }
 
 type paramValidator struct {
-	document  *v3.Document
-	pathItem  *v3.PathItem
-	pathValue string
-	errors    []*errors.ValidationError
+	document   *v3.Document
+	options    *config.ValidationOptions
+	operations *helpers.OperationCache
+	pathItem   *v3.PathItem
+	pathValue  string
+	errors     []*errors.ValidationError
+}
+
+// appliedParameter tracks the parameter definition that is being validated, so it can be attached to the errors
+// produced while it was applied.
+type appliedParameter struct {
+	param *v3.Parameter
+	from  int
+}
+
+// apply will attach the current parameter definition to every error produced since it was applied (unless the error
+// already has one), and then apply param. Apply nil once all parameters have been validated.
+func (a *appliedParameter) apply(validationErrors []*errors.ValidationError, param *v3.Parameter) {
+	if a.param != nil && a.from < len(validationErrors) {
+		for _, e := range validationErrors[a.from:] {
+			if e.ParameterDefinition == nil {
+				e.ParameterDefinition = a.param
+			}
+		}
+	}
+	a.param, a.from = param, len(validationErrors)
 }
@@ -5,10 +5,14 @@ package parameters
 
 import (
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
@@ -23,7 +27,7 @@ func (v *paramValidator) ValidatePathParams(request *http.Request) (bool, []*err
 	var errs []*errors.ValidationError
 	var foundPath string
 	if v.pathItem == nil && v.pathValue == "" {
-		pathItem, errs, foundPath = paths.FindPath(request, v.document)
+		pathItem, errs, foundPath = paths.FindPath(request, v.document, config.WithExistingOpts(v.options))
 		if pathItem == nil || errs != nil {
 			v.errors = errs
 			return false, errs
@@ -33,15 +37,111 @@ func (v *paramValidator) ValidatePathParams(request *http.Request) (bool, []*err
 		foundPath = v.pathValue
 	}
 
-	// split the path into segments
-	submittedSegments := strings.Split(paths.StripRequestPath(request, v.document), helpers.Slash)
-	pathSegments := strings.Split(foundPath, helpers.Slash)
+	// extract params for the operation, any that are missing because of an unresolved reference are rebuilt first.
+	// The location of a parameter that cannot be resolved is unknown, so unresolved references are reported here,
+	// rather than by every parameter validator.
+	unresolved := v.operations.ResolveParameterRefs(request, pathItem)
+	var params = v.operations.ExtractParamsForOperation(request, pathItem)
+
+	// split the path into segments, the base path may come from the document, path item or operation servers, so
+	// only the segments that line up with the matched path are used.
+	submittedSegments := matchedPathSegments(request, foundPath, params, v.options)
+	validationErrors := v.validatePathParams(pathItem, params, foundPath, submittedSegments,
+		helpers.HasEncodedSlashes(request))
+	for _, reference := range unresolved {
+		validationErrors = append(validationErrors, errors.ParameterReferenceUnresolved(reference))
+	}
+
+	errors.PopulateValidationErrors(validationErrors, request, foundPath)
+	errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+	errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
+
+	if len(validationErrors) > 0 {
+		return false, validationErrors
+	}
+	return true, nil
+}
+
+// ValidatePathParams will validate the path parameters of a request against a path that has already been matched
+// (for example by paths.MatchPath), so the path is not looked up again. The parameters of the path item and the
+// operation are validated against the segments of the request path, and every error is returned.
+func ValidatePathParams(pathItem *v3.PathItem, operation *v3.Operation, request *http.Request, foundPath string,
+	opts ...config.Option) []*errors.ValidationError {
+	v := &paramValidator{options: config.NewValidationOptions(opts...)}
+
+	var operationParams []*v3.Parameter
+	if operation != nil {
+		operationParams = operation.Parameters
+	}
+	params := helpers.MergeParams(pathItem.Parameters, operationParams)
+
+	validationErrors := v.validatePathParams(pathItem, params, foundPath,
+		matchedPathSegments(request, foundPath, params, v.options), helpers.HasEncodedSlashes(request))
+	errors.PopulateValidationErrors(validationErrors, request, foundPath)
+	errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+	errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
+	return validationErrors
+}
+
+// matchedPathSegments returns the segments of a request path that line up with the segments of the path it has
+// been matched against. Any base path the request path has (from a server URL) precedes them, so it is dropped.
+// The values of path parameters that allow reserved characters can span several segments of the request path (see
+// config.WithReservedPathParameters), these are kept together as one segment.
+func matchedPathSegments(request *http.Request, foundPath string, params []*v3.Parameter,
+	options *config.ValidationOptions) []string {
+	// without a document, there are no base paths to strip, only duplicate slashes and fragments are handled.
+	requestPath := paths.StripRequestPath(request, &v3.Document{}, config.WithExistingOpts(options))
+	trailingSlash := options.IgnoreTrailingSlash && len(foundPath) > 1 && strings.HasSuffix(foundPath, helpers.Slash)
+	if options.AllowReservedPathParameters {
+		if submitted, ok := reservedPathSegments(requestPath, foundPath, params, trailingSlash); ok {
+			return submitted
+		}
+	}
+	submitted := strings.Split(requestPath, helpers.Slash)
+	if trailingSlash {
+		submitted = append(submitted, "") // the trailing slash of the request path has been removed.
+	}
+	count := len(strings.Split(foundPath, helpers.Slash))
+	if len(submitted) >= count {
+		return submitted[len(submitted)-count:]
+	}
+	return append(make([]string, count-len(submitted)), submitted...)
+}
+
+// reservedPathSegments splits a request path into one value for each segment of the path it has been matched against,
+// where the values of reserved path parameters can contain slashes. The base path is not known, so the shortest tail of
+// the request path that matches is used, as it is for paths without reserved parameters.
+func reservedPathSegments(requestPath, foundPath string, params []*v3.Parameter, trailingSlash bool) ([]string, bool) {
+	names := helpers.ReservedPathParamNames(params)
+	if len(names) == 0 {
+		return nil, false
+	}
+	template := foundPath
+	if trailingSlash {
+		template = strings.TrimSuffix(foundPath, helpers.Slash)
+	}
+	submitted, ok := helpers.MatchReservedPathSuffix(template, requestPath, names)
+	if !ok {
+		return nil, false
+	}
+	if trailingSlash {
+		submitted = append(submitted, "")
+	}
+	return submitted, true
+}
+
+// validatePathParams will validate the path parameters of an operation, against the segments of a request path
+// that line up with the segments of the path it was matched against. If the request path has encoded slashes, they
+// are left encoded in the segments, along with every '%' (see helpers.HasEncodedSlashes).
+func (v *paramValidator) validatePathParams(pathItem *v3.PathItem, params []*v3.Parameter, foundPath string,
+	submittedSegments []string, encodedSlashes bool) []*errors.ValidationError {
 
-	// extract params for the operation
-	var params = helpers.ExtractParamsForOperation(request, pathItem)
+	pathSegments := strings.Split(foundPath, helpers.Slash)
 	var validationErrors []*errors.ValidationError
+	var applied appliedParameter
 	for _, p := range params {
 		if p.In == helpers.Path {
+			applied.apply(validationErrors, p)
 
 			// var paramTemplate string
 			for x := range pathSegments {
@@ -49,39 +149,54 @@ func (v *paramValidator) ValidatePathParams(request *http.Request) (bool, []*err
 					continue
 				}
 				i := strings.IndexRune(pathSegments[x], '{')
-				if i > -1 {
+				if i > -1 && helpers.IsValidPathSegmentTemplate(pathSegments[x]) {
 					isMatrix := false
 					isLabel := false
 					// isExplode := false
 					isSimple := true
-					paramTemplate := pathSegments[x][i+1 : len(pathSegments[x])-1]
-					paramName := paramTemplate
-					// check for an asterisk on the end of the parameter (explode)
-					if strings.HasSuffix(paramTemplate, helpers.Asterisk) {
-						// isExplode = true
-						paramName = paramTemplate[:len(paramTemplate)-1]
-					}
-					if strings.HasPrefix(paramTemplate, helpers.Period) {
-						isLabel = true
-						isSimple = false
-						paramName = paramName[1:]
-					}
-					if strings.HasPrefix(paramTemplate, helpers.SemiColon) {
-						isMatrix = true
-						isSimple = false
-						paramName = paramName[1:]
-					}
+					paramValue := ""
 
-					// does this param name match the current path segment param name
-					if paramName != p.Name {
-						continue
-					}
+					if helpers.IsCompoundPathSegment(pathSegments[x]) {
+						// compound segments (e.g. {year}-{month}) contain more than one parameter, or literal
+						// text around a parameter, so each parameter value is extracted using the template.
+						if !slices.Contains(helpers.ExtractPathSegmentParamNames(pathSegments[x]), p.Name) {
+							continue
+						}
+						if x < len(submittedSegments) {
+							match := helpers.MatchCompoundPathSegment
+							if v.options.CaseInsensitiveCompoundLiterals {
+								match = helpers.MatchCompoundPathSegmentFold
+							}
+							paramValue = match(pathSegments[x], submittedSegments[x])[p.Name]
+						}
+					} else {
+						paramTemplate := pathSegments[x][i+1 : len(pathSegments[x])-1]
+						paramName := paramTemplate
+						// check for an asterisk on the end of the parameter (explode)
+						if strings.HasSuffix(paramTemplate, helpers.Asterisk) {
+							// isExplode = true
+							paramName = paramTemplate[:len(paramTemplate)-1]
+						}
+						if strings.HasPrefix(paramTemplate, helpers.Period) {
+							isLabel = true
+							isSimple = false
+							paramName = paramName[1:]
+						}
+						if strings.HasPrefix(paramTemplate, helpers.SemiColon) {
+							isMatrix = true
+							isSimple = false
+							paramName = paramName[1:]
+						}
 
-					paramValue := ""
+						// does this param name match the current path segment param name
+						if paramName != p.Name {
+							continue
+						}
 
-					// extract the parameter value from the path.
-					if x < len(submittedSegments) {
-						paramValue = submittedSegments[x]
+						// extract the parameter value from the path.
+						if x < len(submittedSegments) {
+							paramValue = submittedSegments[x]
+						}
 					}
 
 					if paramValue == "" {
@@ -92,6 +207,27 @@ func (v *paramValidator) ValidatePathParams(request *http.Request) (bool, []*err
 						continue
 					}
 
+					// a value is a single segment, so it cannot contain a slash (even an encoded one), unless the
+					// parameter allows reserved characters, in which case the encoded slashes are decoded.
+					if strings.Contains(paramValue, helpers.Slash) ||
+						(encodedSlashes && strings.Contains(paramValue, helpers.EncodedSlash)) {
+						if !slices.Contains(helpers.ReservedPathParamNames([]*v3.Parameter{p}), p.Name) {
+							validationErrors = append(validationErrors, errors.PathParameterContainsSlash(p, paramValue))
+							continue
+						}
+					}
+					if encodedSlashes {
+						paramValue = decodeEncodedValue(paramValue)
+					}
+
+					// a nullable parameter accepts 'null' as its value, in place of a value of its type.
+					if paramValue == helpers.Null && p.Schema != nil && isNullableSchema(p.Schema.Schema()) {
+						continue
+					}
+
+					// run any custom validator registered for this parameter.
+					validationErrors = append(validationErrors, v.runCustomPathParamValidator(p, paramValue)...)
+
 					// extract the schema from the parameter
 					sch := p.Schema.Schema()
 
@@ -110,6 +246,21 @@ func (v *paramValidator) ValidatePathParams(request *http.Request) (bool, []*err
 						}
 					}
 
+					// check numeric enum (if present), the segment is compared as a number, so '2', '2.0' and '02'
+					// will all match an enum value of 2.
+					numericEnumCheck := func(paramValue string, paramValueParsed float64) {
+						if !numericEnumContains(sch, paramValueParsed) {
+							validationErrors = append(validationErrors,
+								errors.IncorrectPathParamEnum(p, strings.ToLower(paramValue), sch))
+						}
+					}
+
+					// an untyped schema matches anything, unless configured to reject under-specified parameters.
+					if v.options.RejectUntypedPathParameters && isUntypedSchema(sch) {
+						validationErrors = append(validationErrors, errors.PathParameterMissingType(p, paramValue, sch))
+						continue
+					}
+
 					// for each type, check the value.
 					if sch != nil && sch.Type != nil {
 						for typ := range sch.Type {
@@ -125,37 +276,42 @@ func (v *paramValidator) ValidatePathParams(request *http.Request) (bool, []*err
 									break
 								}
 								validationErrors = append(validationErrors,
-									ValidateSingleParameterSchema(
-										sch,
-										paramValue,
-										"Path parameter",
-										"The path parameter",
-										p.Name,
-										helpers.ParameterValidation,
-										helpers.ParameterValidationPath,
-									)...)
+									validatePathParamSchema(sch, p, paramValue, paramValue)...)
 
 							case helpers.Integer, helpers.Number:
+								// decimals are validated as they are written, so no precision is lost.
+								if v.options.ValidateDecimalFormat && isDecimalFormat(sch) {
+									rawParamValue := rawPathParamValue(p, isLabel, isMatrix, paramValue)
+									if sch.Type[typ] == helpers.Integer && !slices.Contains(sch.Type, helpers.Number) &&
+										strings.Contains(rawParamValue, helpers.Period) {
+										validationErrors = append(validationErrors,
+											errors.IncorrectPathParamInteger(p, rawParamValue, sch))
+										break
+									}
+									validationErrors = append(validationErrors,
+										validateDecimalPathParam(sch, p, rawParamValue)...)
+									break
+								}
 								// simple use case is already handled in find param.
 								rawParamValue, paramValueParsed, err := v.resolveNumber(sch, p, isLabel, isMatrix, paramValue)
 								if err != nil {
 									validationErrors = append(validationErrors, err...)
 									break
 								}
+								// integers must be whole numbers, unless the schema also allows any number.
+								if sch.Type[typ] == helpers.Integer && !slices.Contains(sch.Type, helpers.Number) &&
+									!isWholeNumber(paramValueParsed) {
+									validationErrors = append(validationErrors,
+										errors.IncorrectPathParamInteger(p, rawParamValue, sch))
+									break
+								}
 								// check if the param is within the enum
 								if sch.Enum != nil {
-									enumCheck(rawParamValue)
+									numericEnumCheck(rawParamValue, paramValueParsed)
 									break
 								}
-								validationErrors = append(validationErrors, ValidateSingleParameterSchema(
-									sch,
-									paramValueParsed,
-									"Path parameter",
-									"The path parameter",
-									p.Name,
-									helpers.ParameterValidation,
-									helpers.ParameterValidationPath,
-								)...)
+								validationErrors = append(validationErrors,
+									validatePathParamSchema(sch, p, rawParamValue, paramValueParsed)...)
 
 							case helpers.Boolean:
 								if isLabel && p.Style == helpers.LabelStyle {
@@ -248,9 +404,16 @@ func (v *paramValidator) ValidatePathParams(request *http.Request) (bool, []*err
 										switch iSch.Type[n] {
 										case helpers.Integer, helpers.Number:
 											for pv := range arrayValues {
-												if _, err := strconv.ParseFloat(arrayValues[pv], 64); err != nil {
+												f, err := strconv.ParseFloat(arrayValues[pv], 64)
+												if err != nil {
 													validationErrors = append(validationErrors,
 														errors.IncorrectPathParamArrayNumber(p, arrayValues[pv], sch, iSch))
+													continue
+												}
+												if iSch.Type[n] == helpers.Integer && !slices.Contains(iSch.Type, helpers.Number) &&
+													!isWholeNumber(f) {
+													validationErrors = append(validationErrors,
+														errors.IncorrectPathParamArrayInteger(p, arrayValues[pv], sch, iSch))
 												}
 											}
 										case helpers.Boolean:
@@ -282,12 +445,10 @@ func (v *paramValidator) ValidatePathParams(request *http.Request) (bool, []*err
 		}
 	}
 
-	errors.PopulateValidationErrors(validationErrors, request, foundPath)
+	applied.apply(validationErrors, nil)
 
-	if len(validationErrors) > 0 {
-		return false, validationErrors
-	}
-	return true, nil
+	// every template variable in the path must have a path parameter declared for it.
+	return append(validationErrors, undeclaredPathParams(pathItem, foundPath, params)...)
 }
 
 func (v *paramValidator) resolveNumber(sch *base.Schema, p *v3.Parameter, isLabel bool, isMatrix bool, paramValue string) (string, float64, []*errors.ValidationError) {
@@ -313,3 +474,176 @@ func (v *paramValidator) resolveNumber(sch *base.Schema, p *v3.Parameter, isLabe
 	}
 	return paramValue, paramValueParsed, nil
 }
+
+// undeclaredPathParams returns an error for every template variable in a path that has no matching path
+// parameter declared, as the value of that variable can never be validated. The parameters are those of the matched
+// operation, merged with those of the path item, so a variable declared for another operation is still reported.
+// A variable that appears more than once in the path is only reported once.
+func undeclaredPathParams(pathItem *v3.PathItem, path string, params []*v3.Parameter) []*errors.ValidationError {
+	var validationErrors []*errors.ValidationError
+	var reported []string
+	for _, segment := range strings.Split(path, helpers.Slash) {
+		if !strings.Contains(segment, "{") || !helpers.IsValidPathSegmentTemplate(segment) {
+			continue
+		}
+		for _, name := range helpers.ExtractPathSegmentParamNames(segment) {
+			if slices.Contains(reported, name) || slices.ContainsFunc(params, func(p *v3.Parameter) bool {
+				return p.In == helpers.Path && p.Name == name
+			}) {
+				continue
+			}
+			reported = append(reported, name)
+			validationErrors = append(validationErrors, errors.PathParameterUndeclared(pathItem, name, path))
+		}
+	}
+	return validationErrors
+}
+
+// runCustomPathParamValidator will invoke the custom validator named by the 'x-validator' extension of a
+// parameter. Parameters without the extension, or naming a validator that has not been registered, are ignored.
+func (v *paramValidator) runCustomPathParamValidator(p *v3.Parameter, value string) []*errors.ValidationError {
+	if p.Extensions == nil || len(v.options.PathParameterValidators) == 0 {
+		return nil
+	}
+	ext, ok := p.Extensions.Get(helpers.XValidator)
+	if !ok || ext == nil {
+		return nil
+	}
+	validator := v.options.PathParameterValidators[ext.Value]
+	if validator == nil {
+		return nil
+	}
+	if err := validator(p, value); err != nil {
+		return []*errors.ValidationError{errors.PathParameterCustomValidationFailed(p, ext.Value, value, err)}
+	}
+	return nil
+}
+
+// isUntypedSchema returns true if the schema defines no type, and no composition that could define one.
+func isUntypedSchema(sch *base.Schema) bool {
+	return sch != nil && len(sch.Type) == 0 &&
+		len(sch.AllOf) == 0 && len(sch.OneOf) == 0 && len(sch.AnyOf) == 0
+}
+
+// isNullableSchema returns true if a schema allows null, either with 'nullable: true' (OpenAPI 3.0) or with a
+// 'null' type (OpenAPI 3.1).
+func isNullableSchema(sch *base.Schema) bool {
+	return sch != nil && ((sch.Nullable != nil && *sch.Nullable) || slices.Contains(sch.Type, helpers.Null))
+}
+
+// numericEnumContains returns true if the parsed value is equal to any enum value of the schema, once that
+// enum value has also been parsed as a number. Enum values that are not numbers can never match.
+func numericEnumContains(sch *base.Schema, value float64) bool {
+	for _, enumVal := range sch.Enum {
+		enumParsed, err := strconv.ParseFloat(fmt.Sprint(enumVal.Value), 64)
+		if err == nil && enumParsed == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isWholeNumber returns true if the parsed value has no fractional part.
+func isWholeNumber(value float64) bool {
+	return value == math.Trunc(value)
+}
+
+// validatePathParamSchema validates a path parameter value against the parameter schema. Any failures have their
+// HowToFix advice tailored to the schema keyword that was violated.
+func validatePathParamSchema(sch *base.Schema, p *v3.Parameter, rawValue string, value any) []*errors.ValidationError {
+	validationErrors := ValidateSingleParameterSchema(
+		sch,
+		value,
+		"Path parameter",
+		"The path parameter",
+		p.Name,
+		helpers.ParameterValidation,
+		helpers.ParameterValidationPath,
+	)
+	for _, validationError := range validationErrors {
+		validationError.HowToFix = pathParamHowToFix(sch, rawValue, validationError.SchemaValidationErrors)
+	}
+	return validationErrors
+}
+
+// pathParamHowToFix builds a HowToFix message from the first recognized schema keyword that was violated,
+// falling back to errors.HowToFixInvalidSchema.
+func pathParamHowToFix(sch *base.Schema, value string, failures []*errors.SchemaValidationFailure) string {
+	for _, failure := range failures {
+		keyword := failure.Location[strings.LastIndex(failure.Location, helpers.Slash)+1:]
+		switch keyword {
+		case "type":
+			switch {
+			case slices.Contains(sch.Type, helpers.Integer):
+				return fmt.Sprintf(errors.HowToFixParamInvalidInteger, value)
+			case slices.Contains(sch.Type, helpers.Number):
+				return fmt.Sprintf(errors.HowToFixParamInvalidNumber, value)
+			case slices.Contains(sch.Type, helpers.Boolean):
+				return fmt.Sprintf(errors.HowToFixParamInvalidBoolean, value)
+			case slices.Contains(sch.Type, helpers.String):
+				return fmt.Sprintf(errors.HowToFixParamInvalidString, value)
+			}
+		case "pattern":
+			return fmt.Sprintf(errors.HowToFixParamInvalidPattern, value, sch.Pattern)
+		case "enum":
+			var enums []string
+			for i := range sch.Enum {
+				enums = append(enums, fmt.Sprint(sch.Enum[i].Value))
+			}
+			return fmt.Sprintf(errors.HowToFixParamInvalidEnum, value, strings.Join(enums, ", "))
+		case "minLength":
+			if sch.MinLength != nil {
+				return fmt.Sprintf(errors.HowToFixParamInvalidMinLength, value, *sch.MinLength)
+			}
+		case "maxLength":
+			if sch.MaxLength != nil {
+				return fmt.Sprintf(errors.HowToFixParamInvalidMaxLength, value, *sch.MaxLength)
+			}
+		case "minimum":
+			if sch.Minimum != nil && sch.ExclusiveMinimum != nil && sch.ExclusiveMinimum.IsA() && sch.ExclusiveMinimum.A {
+				return fmt.Sprintf(errors.HowToFixParamInvalidExclusiveMinimum, value, *sch.Minimum)
+			}
+			if sch.Minimum != nil {
+				return fmt.Sprintf(errors.HowToFixParamInvalidMinimum, value, *sch.Minimum)
+			}
+		case "maximum":
+			if sch.Maximum != nil && sch.ExclusiveMaximum != nil && sch.ExclusiveMaximum.IsA() && sch.ExclusiveMaximum.A {
+				return fmt.Sprintf(errors.HowToFixParamInvalidExclusiveMaximum, value, *sch.Maximum)
+			}
+			if sch.Maximum != nil {
+				return fmt.Sprintf(errors.HowToFixParamInvalidMaximum, value, *sch.Maximum)
+			}
+		case "exclusiveMinimum":
+			if sch.ExclusiveMinimum != nil && sch.ExclusiveMinimum.IsB() {
+				return fmt.Sprintf(errors.HowToFixParamInvalidExclusiveMinimum, value, sch.ExclusiveMinimum.B)
+			}
+			if sch.Minimum != nil {
+				return fmt.Sprintf(errors.HowToFixParamInvalidExclusiveMinimum, value, *sch.Minimum)
+			}
+		case "exclusiveMaximum":
+			if sch.ExclusiveMaximum != nil && sch.ExclusiveMaximum.IsB() {
+				return fmt.Sprintf(errors.HowToFixParamInvalidExclusiveMaximum, value, sch.ExclusiveMaximum.B)
+			}
+			if sch.Maximum != nil {
+				return fmt.Sprintf(errors.HowToFixParamInvalidExclusiveMaximum, value, *sch.Maximum)
+			}
+		case "multipleOf":
+			if sch.MultipleOf != nil {
+				return fmt.Sprintf(errors.HowToFixParamInvalidMultipleOf, value, *sch.MultipleOf)
+			}
+		case "format":
+			return fmt.Sprintf(errors.HowToFixParamInvalidFormat, value, sch.Format)
+		}
+	}
+	return errors.HowToFixInvalidSchema
+}
+
+// decodeEncodedValue decodes the encoded slashes ('%2F') and percent signs ('%25') of a path parameter value. They
+// are left encoded while a request path with encoded slashes is matched, so they do not split the segment they were
+// sent in.
+func decodeEncodedValue(value string) string {
+	if decoded, err := url.PathUnescape(value); err == nil {
+		return decoded
+	}
+	return value
+}
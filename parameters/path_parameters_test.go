@@ -4,11 +4,14 @@
 package parameters
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/paths"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -1227,6 +1230,70 @@ paths:
 	assert.Equal(t, "Path parameter 'burgerId' failed to validate", errors[0].Message)
 	assert.Len(t, errors[0].SchemaValidationErrors, 1)
 	assert.Equal(t, "Reason: length must be >= 4, but got 3, Location: /minLength", errors[0].SchemaValidationErrors[0].Error())
+	assert.Equal(t, "Change the value 'big' so it is at least 4 characters long", errors[0].HowToFix)
+}
+
+func TestNewValidator_PathParamStringPatternViolation(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}/locate:
+    parameters:
+      - name: burgerId
+        in: path
+        schema:
+          type: string
+          pattern: '^[a-z]+$'
+    get:
+      operationId: locateBurgers`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/BIG/locate", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Change the value 'BIG' so it matches the pattern '^[a-z]+$'", errors[0].HowToFix)
+}
+
+func TestNewValidator_PathParamNumberMinimumViolation(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}/locate:
+    parameters:
+      - name: burgerId
+        in: path
+        schema:
+          type: integer
+          minimum: 10
+    get:
+      operationId: locateBurgers`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/5/locate", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Change the value '5' so it is greater than or equal to 10", errors[0].HowToFix)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/12.5/locate", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Convert the value '12.5' into an integer (a whole number, without a decimal point)", errors[0].HowToFix)
 }
 
 func TestNewValidator_PathParamIntegerEnumValid(t *testing.T) {
@@ -1284,6 +1351,46 @@ paths:
 	assert.Equal(t, "Path parameter 'burgerId' does not match allowed values", errors[0].Message)
 }
 
+func TestNewValidator_PathParamIntegerEnumCoercion(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /levels/{level}:
+    parameters:
+      - name: level
+        in: path
+        schema:
+          type: integer
+          enum: [1, 2, 3]
+    get:
+      operationId: getLevel`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/levels/2", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/levels/02", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/levels/5", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'level' does not match allowed values", errors[0].Message)
+}
+
 func TestNewValidator_PathLabelEumValid(t *testing.T) {
 
 	spec := `openapi: 3.1.0
@@ -1464,3 +1571,1087 @@ paths:
 	assert.False(t, valid)
 	assert.Len(t, errors, 1)
 }
+
+func TestNewValidator_PathParamIntegerRejectsDecimal(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}/locate:
+    parameters:
+      - name: burgerId
+        in: path
+        schema:
+          type: integer
+    get:
+      operationId: locateBurgers`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/3.14/locate", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'burgerId' is not a valid integer", errors[0].Message)
+	assert.Contains(t, errors[0].Reason, "expected integer, got decimal")
+	assert.Equal(t, "Convert the value '3.14' into an integer (a whole number, without a decimal point)", errors[0].HowToFix)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/3/locate", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestNewValidator_PathParamNumberAcceptsDecimal(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}/locate:
+    parameters:
+      - name: burgerId
+        in: path
+        schema:
+          type: number
+    get:
+      operationId: locateBurgers`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/3.14/locate", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestNewValidator_PathParamIntegerArrayRejectsDecimal(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerIds}/locate:
+    parameters:
+      - name: burgerIds
+        in: path
+        schema:
+          type: array
+          items:
+            type: integer
+    get:
+      operationId: locateBurgers`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/1,2.5,3/locate", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path array parameter 'burgerIds' is not a valid integer", errors[0].Message)
+	assert.Contains(t, errors[0].Reason, "expected integer, got decimal")
+}
+
+func TestNewValidator_PathParamUntypedSchema(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}/locate:
+    parameters:
+      - name: burgerId
+        in: path
+        schema:
+          description: no type defined
+    get:
+      operationId: locateBurgers`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/anything/locate", nil)
+
+	// permissive by default
+	v := NewParameterValidator(&m.Model)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	v = NewParameterValidator(&m.Model, config.WithUntypedPathParameterRejection())
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'burgerId' has no schema type", errors[0].Message)
+	assert.Equal(t, "/burgers/{burgerId}/locate", errors[0].SpecPath)
+}
+
+func TestNewValidator_PathParamCompoundSegment(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /archive/{year}-{month}:
+    parameters:
+      - name: year
+        in: path
+        required: true
+        schema:
+          type: integer
+          minimum: 2000
+      - name: month
+        in: path
+        required: true
+        schema:
+          type: integer
+          maximum: 12
+    get:
+      operationId: getArchive`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/archive/2024-06", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestNewValidator_PathParamCompoundSegment_MultipleErrors(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /archive/{year}-{month}:
+    parameters:
+      - name: year
+        in: path
+        required: true
+        schema:
+          type: integer
+          minimum: 2000
+      - name: month
+        in: path
+        required: true
+        schema:
+          type: integer
+          maximum: 12
+    get:
+      operationId: getArchive`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/archive/1999-13", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 2)
+	assert.Equal(t, "Path parameter 'year' failed to validate", errors[0].Message)
+	assert.Equal(t, "Path parameter 'month' failed to validate", errors[1].Message)
+}
+
+func TestNewValidator_PathParamCustomValidator(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /accounts/{accountNumber}:
+    parameters:
+      - name: accountNumber
+        in: path
+        required: true
+        x-validator: luhn
+        schema:
+          type: string
+    get:
+      operationId: getAccount`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	luhn := func(param *v3.Parameter, value string) error {
+		sum := 0
+		for i := range value {
+			d := int(value[len(value)-1-i] - '0')
+			if i%2 == 1 {
+				d *= 2
+				if d > 9 {
+					d -= 9
+				}
+			}
+			sum += d
+		}
+		if sum%10 != 0 {
+			return fmt.Errorf("checksum of '%s' is invalid", value)
+		}
+		return nil
+	}
+
+	v := NewParameterValidator(&m.Model, config.WithPathParameterValidator("luhn", luhn))
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/accounts/79927398713", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/accounts/79927398710", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'accountNumber' failed 'luhn' validation", errors[0].Message)
+	assert.Contains(t, errors[0].Reason, "checksum of '79927398710' is invalid")
+	assert.Equal(t, 8, errors[0].SpecLine)
+
+	// without a registered validator, the extension is ignored.
+	v = NewParameterValidator(&m.Model)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestNewValidator_PathParamCustomValidator_Content(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /accounts/{accountNumber}:
+    parameters:
+      - name: accountNumber
+        in: path
+        required: true
+        x-validator: closed
+        content:
+          text/plain:
+            schema:
+              type: string
+    get:
+      operationId: getAccount`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	closed := func(param *v3.Parameter, value string) error {
+		return fmt.Errorf("account '%s' is closed", value)
+	}
+	v := NewParameterValidator(&m.Model, config.WithPathParameterValidator("closed", closed))
+
+	// a parameter with content rather than a schema is located at its extension.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/accounts/123", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'accountNumber' failed 'closed' validation", errors[0].Message)
+	assert.Equal(t, 8, errors[0].SpecLine)
+}
+
+func TestNewValidator_PathParamMalformedTemplate(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}/{:
+    parameters:
+      - name: burgerId
+        in: path
+        schema:
+          type: integer
+    get:
+      operationId: locateBurgers`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+	v.SetPathItem(m.Model.Paths.PathItems.GetOrZero("/burgers/{burgerId}/{"), "/burgers/{burgerId}/{")
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/123/x", nil)
+	assert.NotPanics(t, func() {
+		valid, errors := v.ValidatePathParams(request)
+		assert.True(t, valid)
+		assert.Len(t, errors, 0)
+	})
+}
+
+func TestNewValidator_PathParamUndeclared(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}/{sauce}:
+    parameters:
+      - name: burgerId
+        in: path
+        schema:
+          type: integer
+      - name: sauce
+        in: query
+        schema:
+          type: string
+    get:
+      operationId: locateBurgers`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/123/ketchup", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'sauce' is not declared", errors[0].Message)
+	assert.Equal(t, "/burgers/{burgerId}/{sauce}", errors[0].SpecPath)
+	assert.Equal(t, "Declare a parameter named 'sauce' (with 'in: path') for the path in the specification, "+
+		"or remove it from the path template", errors[0].HowToFix)
+}
+
+func TestNewValidator_PathParamUndeclared_OperationLevels(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /a/{b}/c/{d}/{f}/e/{f}:
+    parameters:
+      - name: b
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: getA
+      parameters:
+        - name: d
+          in: path
+          required: true
+          schema:
+            type: string
+    post:
+      operationId: postA
+      parameters:
+        - name: f
+          in: path
+          required: true
+          schema:
+            type: string`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	// 'f' is only declared for the post operation, so it is undeclared for get (and only reported once).
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/a/1/c/2/3/e/3", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'f' is not declared", errors[0].Message)
+	assert.Equal(t, "/a/{b}/c/{d}/{f}/e/{f}", errors[0].SpecPath)
+	assert.Equal(t, 3, errors[0].SpecLine)
+	assert.Equal(t, 3, errors[0].SpecCol)
+
+	// 'd' is only declared for the get operation.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/a/1/c/2/3/e/3", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'd' is not declared", errors[0].Message)
+}
+
+func TestValidatePathParams_Standalone(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api/v1
+paths:
+  /burgers/{burgerId}/locate/{locationId}:
+    parameters:
+      - name: burgerId
+        in: path
+        schema:
+          type: string
+      - name: locationId
+        in: path
+        schema:
+          type: integer
+    get:
+      parameters:
+        - name: burgerId
+          in: path
+          schema:
+            type: integer
+            minimum: 10
+      operationId: locateBurger`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/v1/burgers/5/locate/here", nil)
+
+	result := paths.MatchPath(request, &m.Model)
+	assert.NotNil(t, result.PathItem)
+
+	errs := ValidatePathParams(result.PathItem, result.Operation, request, result.FoundPath)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "Path parameter 'locationId' is not a valid number", errs[0].Message)
+	assert.Equal(t, "/burgers/{burgerId}/locate/{locationId}", errs[0].SpecPath)
+	assert.Equal(t, "Path parameter 'burgerId' failed to validate", errs[1].Message)
+	assert.Same(t, result.Operation.Parameters[0], errs[1].ParameterDefinition)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/v1/burgers/12/locate/3", nil)
+	result = paths.MatchPath(request, &m.Model)
+	assert.Empty(t, ValidatePathParams(result.PathItem, result.Operation, request, result.FoundPath))
+}
+
+func TestNewValidator_PathParamDecimalFormat(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /payments/{amount}:
+    get:
+      parameters:
+        - name: amount
+          in: path
+          required: true
+          schema:
+            type: number
+            format: decimal
+            x-scale: 2
+            maximum: 100000000000000000
+      operationId: getPayment`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model, config.WithDecimalFormatValidation())
+
+	// more precision than a float64 can hold.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/payments/99999999999999999.99", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// as a float64 this rounds down to the maximum, as a decimal it is above it.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/payments/100000000000000000.01", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'amount' failed decimal 'maximum' validation", errors[0].Message)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/payments/12.345", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'amount' failed decimal 'x-scale' validation", errors[0].Message)
+	assert.Equal(t, "Change the value '12.345' so it has no more than 2 decimal places", errors[0].HowToFix)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/payments/1e5", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'amount' is not a valid decimal", errors[0].Message)
+
+	// without the option, the value is validated as a float64.
+	v = NewParameterValidator(&m.Model)
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/payments/1e5", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestNewValidator_PathParamDecimalFormat_MultipleOf(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /prices/{price}:
+    get:
+      parameters:
+        - name: price
+          in: path
+          required: true
+          schema:
+            type: number
+            format: decimal
+            multipleOf: 0.01
+            enum: [0.1, 0.3, 12345678901234567.89]
+      operationId: getPrice`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model, config.WithDecimalFormatValidation())
+
+	// 0.3 is not an exact multiple of 0.01 as a float64, but it is as a decimal.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/prices/0.30", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/prices/12345678901234567.89", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// a float64 cannot tell this apart from the enum value.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/prices/12345678901234567.88", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'price' does not match allowed values", errors[0].Message)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/prices/0.305", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 2)
+	assert.Equal(t, "Path parameter 'price' failed decimal 'multipleOf' validation", errors[0].Message)
+	assert.Contains(t, errors[0].Reason, "'multipleOf' of '0.01'")
+}
+
+func TestNewValidator_PathParamCompoundSegment_CaseInsensitiveLiterals(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /files/{file}.json:
+    parameters:
+      - name: file
+        in: path
+        required: true
+        schema:
+          type: string
+          pattern: '^[a-z]+$'
+    get:
+      operationId: getFile`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	// by default, the literal text of a compound segment is case-sensitive, so the path does not match.
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/files/report.JSON", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "GET Path '/files/report.JSON' not found", errors[0].Message)
+
+	v = NewParameterValidator(&m.Model, config.WithCaseInsensitiveCompoundLiterals())
+
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// the parameter value is still validated with its case intact.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/files/REPORT.JSON", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'file' failed to validate", errors[0].Message)
+
+	result := paths.MatchPath(request, &m.Model, config.WithCaseInsensitiveCompoundLiterals())
+	assert.Equal(t, map[string]string{"file": "REPORT"}, result.Params)
+}
+
+func TestNewValidator_PathParamOperationServerOverride(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /fries/{friesId}:
+    get:
+      servers:
+        - url: https://things.com/legacy/api
+      parameters:
+        - name: friesId
+          in: path
+          required: true
+          schema:
+            type: integer
+      operationId: getFries`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/legacy/api/fries/12", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/legacy/api/fries/large", nil)
+	valid, errors = v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'friesId' is not a valid number", errors[0].Message)
+}
+
+func TestNewValidator_PathAndQueryParamSameName(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{id}:
+    parameters:
+      - name: id
+        in: query
+        required: true
+        schema:
+          type: string
+          enum: [cheese, bacon]
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      operationId: getBurger`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	// the path 'id' does not override the query 'id', they are in different locations.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/12?id=cheese", nil)
+	valid, errors := v.ValidatePathParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = v.ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// each is validated against its own schema.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/cheese?id=12", nil)
+	valid, errors = v.ValidatePathParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'id' is not a valid number", errors[0].Message)
+	assert.Equal(t, "path", errors[0].ParameterDefinition.In)
+
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Query parameter 'id' does not match allowed values", errors[0].Message)
+	assert.Equal(t, "query", errors[0].ParameterDefinition.In)
+
+	// the query 'id' is still required, even though the path 'id' is present.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/12", nil)
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Query parameter 'id' is missing", errors[0].Message)
+}
+
+func TestNewValidator_PathParamUnsupportedPattern(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{name}:
+    get:
+      parameters:
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string
+            pattern: '^(?!pickle).*$'
+      operationId: getBurger`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	// the pattern can never be evaluated, so the value is neither silently passed, nor failed.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/cheese", nil)
+	valid, errors := v.ValidatePathParams(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "pattern uses unsupported regex feature", errors[0].Message)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "/pattern", errors[0].SchemaValidationErrors[0].Location)
+}
+
+func TestNewValidator_ReservedPathParameters(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /files/{path}/versions/{version}:
+    get:
+      parameters:
+        - name: path
+          in: path
+          required: true
+          allowReserved: true
+          schema:
+            type: string
+            pattern: '^[a-z/.]+$'
+        - name: version
+          in: path
+          required: true
+          schema:
+            type: integer
+      operationId: getFileVersion`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model, config.WithReservedPathParameters())
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/files/docs/guide/readme.md/versions/3", nil)
+	valid, errors := v.ValidatePathParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// the whole value is validated, slashes included.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/files/docs/README.md/versions/3", nil)
+	valid, errors = v.ValidatePathParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "path", errors[0].ParameterDefinition.Name)
+
+	// parameters that don't allow reserved characters are still a single segment.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/files/docs/versions/three", nil)
+	valid, errors = v.ValidatePathParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'version' is not a valid number", errors[0].Message)
+
+	// the standalone validator captures the same values.
+	result := paths.MatchPath(request, &m.Model, config.WithReservedPathParameters())
+	errors = ValidatePathParams(result.PathItem, result.Operation, request, result.FoundPath,
+		config.WithReservedPathParameters())
+	assert.Len(t, errors, 1)
+}
+
+func TestNewValidator_PathParamNullable(t *testing.T) {
+
+	spec := `openapi: 3.0.3
+paths:
+  /burgers/{size}:
+    get:
+      parameters:
+        - name: size
+          in: path
+          required: true
+          schema:
+            type: integer
+            nullable: true
+      operationId: getBurger
+  /fries/{size}:
+    get:
+      parameters:
+        - name: size
+          in: path
+          required: true
+          schema:
+            type: integer
+      operationId: getFries`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/null", nil)
+	valid, errors := v.ValidatePathParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/fries/null", nil)
+	valid, errors = v.ValidatePathParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+}
+
+func TestFindPathAndValidate(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      operationId: getBurger
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: sauce
+          in: query
+          required: true
+          schema:
+            type: string
+        - name: X-Chef
+          in: header
+          required: true
+          schema:
+            type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/cheese", nil)
+
+	// by default, only path parameters are validated.
+	pathItem, errs, foundPath := FindPathAndValidate(request, &m.Model)
+	assert.NotNil(t, pathItem)
+	assert.Equal(t, "/burgers/{burgerId}", foundPath)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "Path parameter 'burgerId' is not a valid number", errs[0].Message)
+
+	_, errs, _ = FindPathAndValidate(request, &m.Model,
+		config.WithValidateLocations(config.PathLocation|config.QueryLocation))
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "Query parameter 'sauce' is missing", errs[1].Message)
+
+	_, errs, _ = FindPathAndValidate(request, &m.Model, config.WithValidateLocations(config.AllLocations))
+	assert.Len(t, errs, 3)
+	assert.Equal(t, "Header parameter 'X-Chef' is missing", errs[2].Message)
+
+	_, errs, _ = FindPathAndValidate(request, &m.Model, config.WithValidateLocations(config.HeaderLocation))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "Header parameter 'X-Chef' is missing", errs[0].Message)
+
+	// if no path is found, the errors explain why.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/pizza/1", nil)
+	pathItem, errs, _ = FindPathAndValidate(request, &m.Model, config.WithValidateLocations(config.AllLocations))
+	assert.Nil(t, pathItem)
+	assert.Len(t, errs, 1)
+	assert.True(t, errs[0].IsPathMissingError())
+}
+
+func TestNewValidator_PathParamLengthCodePoints(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /cafes/{name}:
+    get:
+      parameters:
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string
+            minLength: 4
+            maxLength: 4
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	// 'café' is four code points, but five bytes once it is decoded, and ten while it is still escaped.
+	for _, name := range []string{"caf%C3%A9", "%E6%97%A5%E6%9C%AC%E8%AA%9E%E5%AD%97", "%F0%9F%8D%94%F0%9F%8D%94%F0%9F%8D%94%F0%9F%8D%94"} {
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com/cafes/"+name, nil)
+		valid, errors := v.ValidatePathParams(request)
+		assert.True(t, valid, name)
+		assert.Empty(t, errors, name)
+	}
+
+	for _, name := range []string{"caf%C3%A9s", "%F0%9F%8D%94%F0%9F%8D%94%F0%9F%8D%94"} {
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com/cafes/"+name, nil)
+		valid, errors := v.ValidatePathParams(request)
+		assert.False(t, valid, name)
+		assert.Len(t, errors, 1, name)
+		assert.Equal(t, "Path parameter 'name' failed to validate", errors[0].Message)
+	}
+}
+
+func TestNewValidator_PathParamEncodedSlash(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /files/{dir}/{name}:
+    get:
+      parameters:
+        - name: dir
+          in: path
+          required: true
+          schema:
+            type: string
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string
+  /files/{name}:
+    get:
+      parameters:
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string
+  /docs/{path}:
+    get:
+      parameters:
+        - name: path
+          in: path
+          required: true
+          allowReserved: true
+          schema:
+            type: string
+            pattern: '^[a-z]+/[a-z]+$'
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	// the encoded slash does not split the segment, so '/files/{name}' is matched, and the value is rejected.
+	for _, path := range []string{"/files/a%2Fb", "/files/a%2fb"} {
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com"+path, nil)
+		valid, errors := v.ValidatePathParams(request)
+		assert.False(t, valid, path)
+		require.Len(t, errors, 1, path)
+		assert.Equal(t, "Path parameter 'name' cannot contain a '/'", errors[0].Message)
+		assert.Equal(t, "/files/{name}", errors[0].SpecPath)
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/files/a%20b", nil)
+	valid, errors := v.ValidatePathParams(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	// an encoded '%' followed by '2F' is not an encoded slash, the value is 'a%2Fb'.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/files/a%252Fb", nil)
+	valid, errors = v.ValidatePathParams(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	// only the encoded slash is rejected, when both are sent in the same path.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/files/a%252Fb/c%2Fd", nil)
+	valid, errors = v.ValidatePathParams(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'name' cannot contain a '/'", errors[0].Message)
+	assert.Equal(t, "/files/{dir}/{name}", errors[0].SpecPath)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/files/a/b", nil)
+	valid, errors = v.ValidatePathParams(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	// a parameter that allows reserved characters can contain an encoded slash, which is decoded for validation.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/docs/guides%2Fintro", nil)
+	valid, errors = v.ValidatePathParams(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/docs/guides%2F1", nil)
+	valid, errors = v.ValidatePathParams(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'path' failed to validate", errors[0].Message)
+
+	// the value is decoded to 'guides/%2F', which does not match the pattern.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/docs/guides%2F%252F", nil)
+	valid, errors = v.ValidatePathParams(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'path' failed to validate", errors[0].Message)
+}
+
+func TestValidatePathTemplates(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /a/{b}/c/{d}:
+    parameters:
+      - name: b
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      parameters:
+        - name: d
+          in: path
+          required: true
+          schema:
+            type: string
+    post:
+      parameters:
+        - name: e
+          in: path
+          required: true
+          schema:
+            type: string
+    put:
+      parameters:
+        - name: e
+          in: query
+          schema:
+            type: string
+  /z:
+    parameters:
+      - name: z
+        in: path
+        required: true
+        schema:
+          type: string`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+
+	// 'd' is undeclared for post and put, but it is only reported once.
+	errors := ValidatePathTemplates(&m.Model)
+	require.Len(t, errors, 3)
+	assert.Equal(t, "Path parameter 'd' is not declared", errors[0].Message)
+	assert.Equal(t, "/a/{b}/c/{d}", errors[0].SpecPath)
+	assert.Equal(t, 3, errors[0].SpecLine)
+	assert.Equal(t, "Path parameter 'e' is not part of the path template", errors[1].Message)
+	assert.Equal(t, "/a/{b}/c/{d}", errors[1].SpecPath)
+	assert.Equal(t, 19, errors[1].SpecLine)
+	assert.Equal(t, "Path parameter 'z' is not part of the path template", errors[2].Message)
+	assert.Equal(t, "/z", errors[2].SpecPath)
+	assert.Equal(t, 32, errors[2].SpecLine)
+
+	assert.Nil(t, ValidatePathTemplates(nil))
+}
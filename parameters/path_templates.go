@@ -0,0 +1,72 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package parameters
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// ValidatePathTemplates will check the path templates of a document against the path parameters that are declared
+// for them, without a request. An error is returned for every template variable that an operation has no path
+// parameter for (merged with those of the path item), and for every path parameter that names a variable the
+// template does not contain. A variable that is undeclared for several operations of a path is only reported once.
+func ValidatePathTemplates(document *v3.Document) []*errors.ValidationError {
+	if document == nil || document.Paths == nil {
+		return nil
+	}
+	var validationErrors []*errors.ValidationError
+	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
+		path, pathItem := pair.Key(), pair.Value()
+		if pathItem == nil {
+			continue
+		}
+		paramSets := [][]*v3.Parameter{pathItem.Parameters}
+		if ops := pathItem.GetOperations(); ops.Len() > 0 {
+			paramSets = paramSets[:0]
+			for op := orderedmap.First(ops); op != nil; op = op.Next() {
+				paramSets = append(paramSets, helpers.MergeParams(pathItem.Parameters, op.Value().Parameters))
+			}
+		}
+
+		names := pathTemplateParamNames(path)
+		var reported []string
+		var checked []*v3.Parameter
+		for _, params := range paramSets {
+			for _, undeclared := range undeclaredPathParams(pathItem, path, params) {
+				if !slices.Contains(reported, undeclared.Message) {
+					reported = append(reported, undeclared.Message)
+					undeclared.SpecPath = path
+					validationErrors = append(validationErrors, undeclared)
+				}
+			}
+			for _, p := range params {
+				if p == nil || p.In != helpers.Path || slices.Contains(checked, p) {
+					continue
+				}
+				checked = append(checked, p)
+				if !slices.Contains(names, p.Name) {
+					validationErrors = append(validationErrors, errors.PathParameterNotInTemplate(p, path))
+				}
+			}
+		}
+	}
+	return validationErrors
+}
+
+// pathTemplateParamNames returns the names of every template variable in a path.
+func pathTemplateParamNames(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, helpers.Slash) {
+		if strings.Contains(segment, "{") && helpers.IsValidPathSegmentTemplate(segment) {
+			names = append(names, helpers.ExtractPathSegmentParamNames(segment)...)
+		}
+	}
+	return names
+}
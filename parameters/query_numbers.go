@@ -0,0 +1,36 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package parameters
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// a lenient query number is an optional sign, digits (which may have leading zeros), an optional fraction, and an
+// optional exponent. Hexadecimal values, underscores, 'Inf' and 'NaN' are never numbers, even though
+// strconv.ParseFloat accepts them.
+var lenientQueryNumberRegex = regexp.MustCompile(`^[+-]?(?:\d+\.?\d*|\.\d+)(?:[eE][+-]?\d+)?$`)
+
+// a strict query number is written as a JSON number, so a '-' sign is the only sign allowed, and the integer part
+// has no leading zeros (other than a single '0').
+var strictQueryNumberRegex = regexp.MustCompile(`^-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?$`)
+
+// parseQueryNumber parses the value of a numeric query parameter. By default, the value is lenient: '+5' is 5, and
+// '007' is 7, so the value that is validated against the integer or number schema is the number that was written.
+// When strict, the value must be written as a JSON number, so '+5' and '007' are rejected (but '-3', '0' and '0.5'
+// are not). The second return value is false if the value is a number, that is not written strictly.
+func parseQueryNumber(value string, strict bool) (float64, bool, error) {
+	if !lenientQueryNumberRegex.MatchString(value) {
+		return 0, true, strconv.ErrSyntax
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, true, err
+	}
+	if strict && !strictQueryNumberRegex.MatchString(value) {
+		return number, false, nil
+	}
+	return number, true, nil
+}
@@ -7,10 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
@@ -19,13 +21,18 @@ import (
 	"github.com/pb33f/libopenapi/orderedmap"
 )
 
+// the characters that must be percent-encoded in a query parameter value, unless the parameter allows reserved values.
+const reservedCharacters = ":/?#[]@!$&'()*+,;="
+
+var reservedCharactersRegex = regexp.MustCompile(`[:\/\?#\[\]\@!\$&'\(\)\*\+,;=]`)
+
 func (v *paramValidator) ValidateQueryParams(request *http.Request) (bool, []*errors.ValidationError) {
 	// find path
 	var pathItem *v3.PathItem
 	var foundPath string
 	var errs []*errors.ValidationError
 	if v.pathItem == nil {
-		pathItem, errs, foundPath = paths.FindPath(request, v.document)
+		pathItem, errs, foundPath = paths.FindPath(request, v.document, config.WithExistingOpts(v.options))
 		if pathItem == nil || errs != nil {
 			v.errors = errs
 			return false, errs
@@ -35,33 +42,48 @@ func (v *paramValidator) ValidateQueryParams(request *http.Request) (bool, []*er
 		foundPath = v.pathValue
 	}
 
-	// extract params for the operation
-	params := helpers.ExtractParamsForOperation(request, pathItem)
+	// extract params for the operation, any that are missing because of an unresolved reference are rebuilt first
+	// (unresolved references are reported by ValidatePathParams).
+	v.operations.ResolveParameterRefs(request, pathItem)
+	params := v.operations.ExtractParamsForOperation(request, pathItem)
 	queryParams := make(map[string][]*helpers.QueryParam)
 	var validationErrors []*errors.ValidationError
 
-	for qKey, qVal := range request.URL.Query() {
+	// parameters that allow reserved values are validated with their reserved characters exactly as they were sent.
+	allowReserved := make(map[string]bool)
+	for _, p := range params {
+		if p.In == helpers.Query && p.AllowReserved {
+			allowReserved[p.Name] = true
+		}
+	}
+
+	decodedQuery, rawQuery := parseQueryValues(request.URL.RawQuery)
+	for qKey, qVal := range decodedQuery {
 		// check if the param is encoded as a property / deepObject
 		if strings.IndexRune(qKey, '[') > 0 && strings.IndexRune(qKey, ']') > 0 {
 			stripped := qKey[:strings.IndexRune(qKey, '[')]
-			value := qKey[strings.IndexRune(qKey, '[')+1 : strings.IndexRune(qKey, ']')]
+			properties := deepObjectKeys(qKey[strings.IndexRune(qKey, '['):])
 			queryParams[stripped] = append(queryParams[stripped], &helpers.QueryParam{
-				Key:      stripped,
-				Values:   qVal,
-				Property: value,
+				Key:              stripped,
+				Values:           queryParamValues(qVal, rawQuery[qKey], allowReserved[stripped]),
+				Property:         properties[0],
+				NestedProperties: properties[1:],
+				RawValues:        rawQuery[qKey],
 			})
 		} else {
 			queryParams[qKey] = append(queryParams[qKey], &helpers.QueryParam{
-				Key:    qKey,
-				Values: qVal,
+				Key:       qKey,
+				Values:    queryParamValues(qVal, rawQuery[qKey], allowReserved[qKey]),
+				RawValues: rawQuery[qKey],
 			})
 		}
 	}
 
 	// look through the params for the query key
-doneLooking:
+	var applied appliedParameter
 	for p := range params {
 		if params[p].In == helpers.Query {
+			applied.apply(validationErrors, params[p])
 
 			contentWrapped := false
 			var contentType string
@@ -92,16 +114,24 @@ doneLooking:
 					pType := sch.Type
 
 					// for each param, check each type
-					for _, ef := range fp.Values {
+					for i, ef := range fp.Values {
+
+						// a nullable parameter accepts an empty value (e.g. ?foo=) as null.
+						if ef == "" && isNullableSchema(sch) {
+							continue
+						}
 
 						// check allowReserved values. If this is set to true, then we can allow the
 						// following characters
 						//  :/?#[]@!$&'()*+,;=
-						// to be present as they are, without being URLEncoded.
+						// to be present as they are, without being URLEncoded. Otherwise, only the value as it
+						// was sent is checked, as reserved characters that were encoded are allowed.
 						if !params[p].AllowReserved {
-							rx := `[:\/\?#\[\]\@!\$&'\(\)\*\+,;=]`
-							regexp.MustCompile(rx)
-							if regexp.MustCompile(rx).MatchString(ef) && params[p].IsExploded() {
+							raw := ef
+							if i < len(fp.RawValues) {
+								raw = fp.RawValues[i]
+							}
+							if reservedCharactersRegex.MatchString(raw) && params[p].IsExploded() {
 								validationErrors = append(validationErrors,
 									errors.IncorrectReservedValues(params[p], ef, sch))
 							}
@@ -110,15 +140,20 @@ doneLooking:
 							switch ty {
 
 							case helpers.String:
-								validationErrors = v.validateSimpleParam(sch, ef, ef, params[p])
+								validationErrors = append(validationErrors, v.validateSimpleParam(sch, ef, ef, params[p])...)
 							case helpers.Integer, helpers.Number:
-								efF, err := strconv.ParseFloat(ef, 64)
+								efF, strict, err := parseQueryNumber(ef, v.options.StrictQueryNumbers)
 								if err != nil {
 									validationErrors = append(validationErrors,
 										errors.InvalidQueryParamNumber(params[p], ef, sch))
 									break
 								}
-								validationErrors = v.validateSimpleParam(sch, ef, efF, params[p])
+								if !strict {
+									validationErrors = append(validationErrors,
+										errors.IncorrectQueryParamNumberFormat(params[p], ef, sch))
+									break
+								}
+								validationErrors = append(validationErrors, v.validateSimpleParam(sch, ef, efF, params[p])...)
 							case helpers.Boolean:
 								if _, err := strconv.ParseBool(ef); err != nil {
 									validationErrors = append(validationErrors,
@@ -190,19 +225,23 @@ doneLooking:
 					sch := params[p].Schema.Schema()
 
 					if len(sch.Type) > 0 && sch.Type[0] == helpers.Object && params[p].IsDefaultFormEncoding() {
-						// if the param is an object, and we're using default encoding, then we need to
-						// validate the schema.
-						decoded := helpers.ConstructParamMapFromQueryParamInput(queryParams)
-						validationErrors = append(validationErrors,
-							ValidateParameterSchema(sch,
-								decoded,
-								"",
-								"Query array parameter",
-								"The query parameter (which is an array)",
-								params[p].Name,
-								helpers.ParameterValidation,
-								helpers.ParameterValidationQuery)...)
-						break doneLooking
+						// if the param is an object, and we're using default encoding, then the properties
+						// of the object are exploded into their own query keys, so re-assemble the object
+						// and validate the schema.
+						decoded := helpers.ConstructParamMapFromExplodedFormEncoding(queryParams, sch,
+							reservedQueryKeys(params, params[p]))
+						if len(decoded) > 0 {
+							validationErrors = append(validationErrors,
+								ValidateParameterSchema(sch,
+									decoded,
+									"",
+									"Query parameter",
+									"The query parameter",
+									params[p].Name,
+									helpers.ParameterValidation,
+									helpers.ParameterValidationQuery)...)
+							continue
+						}
 					}
 				}
 				// if there is no match, check if the param is required or not.
@@ -213,7 +252,11 @@ doneLooking:
 		}
 	}
 
+	applied.apply(validationErrors, nil)
+
 	errors.PopulateValidationErrors(validationErrors, request, foundPath)
+	errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+	errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
 
 	v.errors = validationErrors
 	if len(validationErrors) > 0 {
@@ -247,3 +290,93 @@ func (v *paramValidator) validateSimpleParam(sch *base.Schema, rawParam string,
 		helpers.ParameterValidationQuery,
 	)
 }
+
+// reservedQueryKeys returns the names of every query parameter other than param, these keys are never treated as
+// exploded properties of param.
+func reservedQueryKeys(params []*v3.Parameter, param *v3.Parameter) []string {
+	var reserved []string
+	for _, p := range params {
+		if p != param && p.In == helpers.Query {
+			reserved = append(reserved, p.Name)
+		}
+	}
+	return reserved
+}
+
+// parseQueryValues parses a raw query string in the same way as url.ParseQuery, however the values are returned
+// both decoded, and exactly as they were sent. Pairs that url.ParseQuery would skip are skipped.
+func parseQueryValues(query string) (url.Values, url.Values) {
+	decoded, raw := make(url.Values), make(url.Values)
+	for query != "" {
+		var pair string
+		pair, query, _ = strings.Cut(query, "&")
+		if pair == "" || strings.Contains(pair, ";") {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			continue
+		}
+		decodedValue, err := url.QueryUnescape(value)
+		if err != nil {
+			continue
+		}
+		decoded[key] = append(decoded[key], decodedValue)
+		raw[key] = append(raw[key], value)
+	}
+	return decoded, raw
+}
+
+// deepObjectKeys returns the bracketed keys of a deepObject query parameter name (such as [address][city]), in
+// order. There is always at least one key.
+func deepObjectKeys(brackets string) []string {
+	var keys []string
+	for strings.HasPrefix(brackets, "[") {
+		end := strings.IndexRune(brackets, ']')
+		if end < 0 {
+			break
+		}
+		keys = append(keys, brackets[1:end])
+		brackets = brackets[end+1:]
+	}
+	if len(keys) == 0 {
+		keys = append(keys, "")
+	}
+	return keys
+}
+
+// queryParamValues returns the values of a query parameter to validate. If the parameter allows reserved values,
+// then the raw values are used, with every percent-encoded character decoded, except for reserved characters (and
+// a '+' is not a space).
+func queryParamValues(decoded, raw []string, allowReserved bool) []string {
+	if !allowReserved || len(raw) != len(decoded) {
+		return decoded
+	}
+	values := make([]string, len(raw))
+	for i, r := range raw {
+		values[i] = unescapeUnreserved(r)
+	}
+	return values
+}
+
+// unescapeUnreserved decodes every percent-encoded character of a value, except for reserved characters, which are
+// left encoded.
+func unescapeUnreserved(value string) string {
+	if !strings.Contains(value, "%") {
+		return value
+	}
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '%' && i+2 < len(value) {
+			if b, err := strconv.ParseUint(value[i+1:i+3], 16, 8); err == nil &&
+				!strings.ContainsRune(reservedCharacters, rune(b)) {
+				sb.WriteByte(byte(b))
+				i += 2
+				continue
+			}
+		}
+		sb.WriteByte(value[i])
+	}
+	return sb.String()
+}
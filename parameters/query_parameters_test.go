@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -2670,3 +2673,536 @@ components:
 	assert.Equal(t, "The query parameter 'objParam' is defined as an object,"+
 		" however it failed to pass a schema validation", errors[0].Reason)
 }
+
+func TestNewValidator_QueryParamArrayItemMaxLength_PipeDelimited(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /a/fishy/on/a/dishy:
+    get:
+      parameters:
+        - name: fishy
+          in: query
+          required: true
+          style: pipeDelimited
+          explode: false
+          schema:
+            type: array
+            items:
+              type: string
+              maxLength: 4
+      operationId: locateFishy
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/a/fishy/on/a/dishy?fishy=cod|halibut", nil)
+
+	valid, errors := v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Query array parameter 'fishy' failed to validate", errors[0].Message)
+	assert.Contains(t, errors[0].SchemaValidationErrors[0].Reason, "length must be <= 4")
+}
+
+func TestNewValidator_QueryParamArrayItemMinimum_FormExploded(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /a/fishy/on/a/dishy:
+    get:
+      parameters:
+        - name: fishy
+          in: query
+          required: true
+          schema:
+            type: array
+            items:
+              type: integer
+              minimum: 10
+      operationId: locateFishy
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/a/fishy/on/a/dishy?fishy=12&fishy=3&fishy=99", nil)
+
+	valid, errors := v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Query array parameter 'fishy' failed to validate", errors[0].Message)
+}
+
+func TestNewValidator_QueryParamArrayItemPattern_SpaceDelimited(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /a/fishy/on/a/dishy:
+    get:
+      parameters:
+        - name: fishy
+          in: query
+          required: true
+          style: spaceDelimited
+          explode: false
+          schema:
+            type: array
+            items:
+              type: string
+              pattern: ^[a-z]+$
+      operationId: locateFishy
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/a/fishy/on/a/dishy?fishy=cod%20halibut", nil)
+
+	valid, errors := v.ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/a/fishy/on/a/dishy?fishy=cod%20HALIBUT", nil)
+
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+}
+
+func TestNewValidator_QueryParamFormObjectExploded(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /locations:
+    get:
+      parameters:
+        - name: coords
+          in: query
+          required: true
+          style: form
+          explode: true
+          schema:
+            type: object
+            properties:
+              lat:
+                type: number
+              lon:
+                type: number
+            required: [lat, lon]
+        - name: radius
+          in: query
+          schema:
+            type: integer
+      operationId: findLocations`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/locations?lat=1&lon=2.5&radius=10", nil)
+
+	valid, errors := v.ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/locations?lat=1&lon=west&radius=ten", nil)
+
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 2)
+	assert.Equal(t, "Query parameter 'coords' failed to validate", errors[0].Message)
+	assert.Equal(t, "expected number, but got string", errors[0].SchemaValidationErrors[0].Reason)
+	assert.Equal(t, "Query parameter 'radius' is not a valid number", errors[1].Message)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/locations?lat=1", nil)
+
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "missing properties: 'lon'", errors[0].SchemaValidationErrors[0].Reason)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/locations?radius=10", nil)
+
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Query parameter 'coords' is missing", errors[0].Message)
+}
+
+func TestNewValidator_QueryParamFormObjectNotExploded(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /locations:
+    get:
+      parameters:
+        - name: coords
+          in: query
+          style: form
+          explode: false
+          schema:
+            type: object
+            properties:
+              lat:
+                type: number
+              lon:
+                type: number
+            required: [lat, lon]
+      operationId: findLocations`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/locations?coords=lat,1,lon,2", nil)
+
+	valid, errors := v.ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/locations?coords=lat,1,lon", nil)
+
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "expected number, but got string", errors[0].SchemaValidationErrors[0].Reason)
+}
+
+func TestNewValidator_QueryParamOperationOverridesPathParam(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /a/fishy/on/a/dishy:
+    parameters:
+      - name: fishy
+        in: query
+        required: true
+        schema:
+          type: string
+      - name: dishy
+        in: query
+        schema:
+          type: integer
+    get:
+      parameters:
+        - name: fishy
+          in: query
+          schema:
+            type: integer
+      operationId: locateFishy`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/a/fishy/on/a/dishy?fishy=cod&dishy=plate", nil)
+
+	valid, errors := v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 2)
+
+	pathItem := m.Model.Paths.PathItems.GetOrZero("/a/fishy/on/a/dishy")
+	assert.Equal(t, "Query parameter 'dishy' is not a valid number", errors[0].Message)
+	assert.Same(t, pathItem.Parameters[1], errors[0].ParameterDefinition)
+	assert.Equal(t, "Query parameter 'fishy' is not a valid number", errors[1].Message)
+	assert.Same(t, pathItem.Get.Parameters[0], errors[1].ParameterDefinition)
+
+	// the operation level param is not required, so the path level param does not apply.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/a/fishy/on/a/dishy", nil)
+
+	valid, errors = v.ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestNewValidator_QueryParamMessageFormatter(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /a/fishy/on/a/dishy:
+    get:
+      parameters:
+        - name: fishy
+          in: query
+          required: true
+          schema:
+            type: integer
+      operationId: locateFishy`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model,
+		config.WithMessageFormatter(helpers.ParameterValidation, helpers.ParameterValidationQuery,
+			func(validationError *errors.ValidationError) (string, string, string) {
+				return "Paramètre de requête invalide", "La valeur n'est pas un nombre", "Envoyez un nombre"
+			}),
+		config.WithMessageFormatter("", "", func(validationError *errors.ValidationError) (string, string, string) {
+			return "formatted", validationError.Reason, validationError.HowToFix
+		}))
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/a/fishy/on/a/dishy?fishy=cod", nil)
+
+	valid, errs := v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "Paramètre de requête invalide", errs[0].Message)
+	assert.Equal(t, "La valeur n'est pas un nombre", errs[0].Reason)
+	assert.Equal(t, "Envoyez un nombre", errs[0].HowToFix)
+
+	// the structured fields are untouched.
+	assert.Equal(t, helpers.ParameterValidation, errs[0].ValidationType)
+	assert.Equal(t, helpers.ParameterValidationQuery, errs[0].ValidationSubType)
+	assert.Equal(t, "/a/fishy/on/a/dishy", errs[0].SpecPath)
+
+	// errors without a specific formatter use the fallback.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/a/fishy/on/a/dishy/and/chips", nil)
+
+	valid, errs = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "formatted", errs[0].Message)
+	assert.True(t, errs[0].IsPathMissingError())
+}
+
+func TestNewValidator_QueryParamAllowReservedDecoding(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /files:
+    get:
+      parameters:
+        - name: path
+          in: query
+          allowReserved: true
+          schema:
+            type: string
+            pattern: '^/[a-z /]+$'
+        - name: encodedPath
+          in: query
+          explode: true
+          schema:
+            type: string
+            pattern: '^/[a-z /]+$'
+      operationId: getFile`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	// reserved characters are allowed as they are, and other characters are still decoded.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/files?path=/a/b%20c", nil)
+	valid, errors := v.ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// encoded reserved characters are not decoded, so '%2F' is not a '/'.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/files?path=%2Fa%2Fb", nil)
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Query parameter 'path' failed to validate", errors[0].Message)
+
+	// without allowReserved, reserved characters must be encoded, and are decoded before validation.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/files?encodedPath=%2Fa%2Fb", nil)
+	valid, errors = v.ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/files?encodedPath=/a/b", nil)
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Query parameter 'encodedPath' value contains reserved values", errors[0].Message)
+}
+
+func TestNewValidator_QueryParamNullable(t *testing.T) {
+	spec := `openapi: 3.0.3
+paths:
+  /burgers:
+    get:
+      parameters:
+        - name: patties
+          in: query
+          schema:
+            type: integer
+            nullable: true
+        - name: cheese
+          in: query
+          schema:
+            type: integer
+      operationId: getBurgers`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	// an empty value is null.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers?patties=", nil)
+	valid, errors := v.ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers?patties=2", nil)
+	valid, errors = v.ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers?patties=two", nil)
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+
+	// without nullable, an empty value is not an integer.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers?cheese=", nil)
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Query parameter 'cheese' is not a valid number", errors[0].Message)
+}
+
+func TestNewValidator_QueryParamNullType(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      parameters:
+        - name: patties
+          in: query
+          schema:
+            type: [integer, "null"]
+      operationId: getBurgers`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers?patties=", nil)
+	valid, errors := v.ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestNewValidator_QueryParamValidateStyle_DeepObjectNested(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      parameters:
+        - name: filter
+          in: query
+          style: deepObject
+          schema:
+            type: object
+            additionalProperties: false
+            properties:
+              status:
+                type: string
+                enum: [active, retired]
+              toppings:
+                type: array
+                items:
+                  type: string
+              restaurant:
+                type: object
+                properties:
+                  city:
+                    type: string
+                  rating:
+                    type: integer
+                    minimum: 1`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers?filter[status]=active"+
+		"&filter[toppings]=onion&filter[toppings]=pickle&filter[restaurant][city]=Paris&filter[restaurant][rating]=5", nil)
+	valid, errors := v.ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// the nested rating is not an integer.
+	request, _ = http.NewRequest(http.MethodGet,
+		"https://things.com/burgers?filter[status]=active&filter[restaurant][rating]=great", nil)
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "/properties/restaurant/properties/rating/type",
+		errors[0].SchemaValidationErrors[0].Location)
+
+	// nested values are validated against their schemas too.
+	request, _ = http.NewRequest(http.MethodGet,
+		"https://things.com/burgers?filter[restaurant][rating]=0", nil)
+	valid, errors = v.ValidateQueryParams(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+}
+
+func TestNewValidator_QueryParamSignsAndLeadingZeros(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /fish:
+    get:
+      parameters:
+        - name: qty
+          in: query
+          required: true
+          schema:
+            type: integer
+            minimum: -5
+            maximum: 7
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	lenient := NewParameterValidator(&m.Model)
+	strict := NewParameterValidator(&m.Model, config.WithStrictQueryNumbers())
+
+	for _, value := range []string{"%2B5", "-3", "007", "0", "-0"} {
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com/fish?qty="+value, nil)
+		valid, errs := lenient.ValidateQueryParams(request)
+		assert.True(t, valid, value)
+		assert.Empty(t, errs, value)
+	}
+
+	// the value is validated as the number it represents, so leading zeros do not hide a violation.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/fish?qty=008", nil)
+	valid, errs := lenient.ValidateQueryParams(request)
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Query parameter 'qty' failed to validate", errs[0].Message)
+
+	for _, value := range []string{"-3", "0", "7"} {
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/fish?qty="+value, nil)
+		valid, errs = strict.ValidateQueryParams(request)
+		assert.True(t, valid, value)
+		assert.Empty(t, errs, value)
+	}
+	for _, value := range []string{"%2B5", "007", "-03"} {
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/fish?qty="+value, nil)
+		valid, errs = strict.ValidateQueryParams(request)
+		assert.False(t, valid, value)
+		require.Len(t, errs, 1, value)
+		assert.Equal(t, "Query parameter 'qty' is not a strictly formatted number", errs[0].Message)
+		assert.Contains(t, errs[0].HowToFix, "any leading zeros")
+	}
+
+	// values that are not numbers are never valid, even though strconv.ParseFloat accepts them.
+	for _, value := range []string{"Inf", "NaN", "0x10"} {
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/fish?qty="+value, nil)
+		valid, errs = lenient.ValidateQueryParams(request)
+		assert.False(t, valid, value)
+		require.Len(t, errs, 1, value)
+		assert.Equal(t, "Query parameter 'qty' is not a valid number", errs[0].Message)
+	}
+}
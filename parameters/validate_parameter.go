@@ -9,10 +9,16 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/schema_validation"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/utils"
 	"github.com/santhosh-tekuri/jsonschema/v5"
 )
@@ -26,7 +32,14 @@ func ValidateSingleParameterSchema(
 	validationType string,
 	subValType string,
 ) (validationErrors []*errors.ValidationError) {
-	jsch := compileSchema(name, buildJsonRender(schema))
+	jsonSchema := buildJsonRender(schema)
+	jsch := compileSchema(name, jsonSchema)
+	if jsch == nil {
+		if patternError := schema_validation.CheckUnsupportedPatterns(jsonSchema); patternError != nil {
+			validationErrors = append(validationErrors, patternError)
+		}
+		return validationErrors
+	}
 
 	scErrs := jsch.Validate(rawObject)
 	var werras *jsonschema.ValidationError
@@ -36,6 +49,100 @@ func ValidateSingleParameterSchema(
 	return validationErrors
 }
 
+// ValidateParameterValue will validate a single candidate value against the schema of a parameter, independently of
+// an HTTP request. The value is cast into the type defined by the schema (arrays are split using the delimiter of the
+// parameter style, and objects are decoded from CSV) before being validated, so type, enum, pattern, format and
+// bounds are all checked. Parameters without a schema have nothing to validate against and always pass.
+//
+// The options that change how parameter values are read apply here too: WithStrictQueryNumbers only accepts numeric
+// query values written as JSON numbers, and WithDecimalFormatValidation validates numeric path values with a
+// 'decimal' format exactly, as they are written.
+func ValidateParameterValue(param *v3.Parameter, value string, opts ...config.Option) []*errors.ValidationError {
+	if param == nil || param.Schema == nil {
+		return nil
+	}
+	sch := param.Schema.Schema()
+	if sch == nil {
+		return nil
+	}
+	options := config.NewValidationOptions(opts...)
+	isNumber := slices.Contains(sch.Type, helpers.Integer) || slices.Contains(sch.Type, helpers.Number)
+	isInteger := slices.Contains(sch.Type, helpers.Integer) && !slices.Contains(sch.Type, helpers.Number)
+	switch {
+	case isNumber && param.In == helpers.Query && options.StrictQueryNumbers:
+		if _, strict, err := parseQueryNumber(value, true); err != nil {
+			return []*errors.ValidationError{errors.InvalidQueryParamNumber(param, value, sch)}
+		} else if !strict {
+			return []*errors.ValidationError{errors.IncorrectQueryParamNumberFormat(param, value, sch)}
+		}
+	case isNumber && param.In == helpers.Path && options.ValidateDecimalFormat && isDecimalFormat(sch):
+		// decimals are validated as they are written, so no precision is lost.
+		if isInteger && strings.Contains(value, helpers.Period) {
+			return []*errors.ValidationError{errors.IncorrectPathParamInteger(param, value, sch)}
+		}
+		return validateDecimalPathParam(sch, param, value)
+	}
+	location := param.In
+	if location == "" {
+		location = helpers.ParameterValidation
+	}
+	validationErrors := ValidateSingleParameterSchema(
+		sch,
+		castParameterValue(sch, param, value),
+		fmt.Sprintf("%s%s parameter", strings.ToUpper(location[:1]), location[1:]),
+		fmt.Sprintf("The %s parameter", location),
+		param.Name,
+		helpers.ParameterValidation,
+		location,
+	)
+	for _, validationError := range validationErrors {
+		validationError.HowToFix = pathParamHowToFix(sch, value, validationError.SchemaValidationErrors)
+	}
+	return validationErrors
+}
+
+// castParameterValue will convert a raw parameter value into the type defined by the schema. Values that cannot
+// be converted are returned as strings, so the schema validation reports the type mismatch.
+func castParameterValue(sch *base.Schema, param *v3.Parameter, value string) any {
+	switch {
+	case slices.Contains(sch.Type, helpers.Integer), slices.Contains(sch.Type, helpers.Number):
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case slices.Contains(sch.Type, helpers.Boolean):
+		// "0" and "1" are not considered to be valid booleans.
+		if value == "true" || value == "false" {
+			return value == "true"
+		}
+	case slices.Contains(sch.Type, helpers.Array):
+		var items []string
+		if param.Style == helpers.LabelStyle {
+			items = strings.Split(value, helpers.Period)
+		} else {
+			items = helpers.ExplodeQueryValue(value, param.Style)
+		}
+		var itemsSchema *base.Schema
+		if sch.Items != nil && sch.Items.IsA() {
+			itemsSchema = sch.Items.A.Schema()
+		}
+		array := make([]any, len(items))
+		for i, item := range items {
+			if itemsSchema != nil {
+				array[i] = castParameterValue(itemsSchema, param, item)
+			} else {
+				array[i] = item
+			}
+		}
+		return array
+	case slices.Contains(sch.Type, helpers.Object):
+		if param.IsExploded() {
+			return helpers.ConstructKVFromCSV(value)
+		}
+		return helpers.ConstructMapFromCSV(value)
+	}
+	return value
+}
+
 // compileSchema create a new json schema compiler and add the schema to it.
 func compileSchema(name string, jsonSchema []byte) *jsonschema.Schema {
 	compiler := jsonschema.NewCompiler()
@@ -104,6 +211,12 @@ func ValidateParameterSchema(
 	compiler := jsonschema.NewCompiler()
 	_ = compiler.AddResource(fmt.Sprintf("%s.json", name), strings.NewReader(string(jsonSchema)))
 	jsch, _ := compiler.Compile(fmt.Sprintf("%s.json", name))
+	if jsch == nil {
+		if patternError := schema_validation.CheckUnsupportedPatterns(renderedSchema); patternError != nil {
+			validationErrors = append(validationErrors, patternError)
+		}
+		return validationErrors
+	}
 
 	// 4. validate the object against the schema
 	var scErrs error
@@ -172,18 +285,16 @@ func ValidateParameterSchema(
 
 func formatJsonSchemaValidationError(schema *base.Schema, scErrs *jsonschema.ValidationError, entity string, reasonEntity string, name string, validationType string, subValType string) (validationErrors []*errors.ValidationError) {
 	// flatten the validationErrors
-	schFlatErrs := scErrs.BasicOutput().Errors
+	schFlatErrs := schema_validation.FlattenValidationErrors(scErrs, false)
 	var schemaValidationErrors []*errors.SchemaValidationFailure
 	for q := range schFlatErrs {
 		er := schFlatErrs[q]
-		if er.KeywordLocation == "" || strings.HasPrefix(er.Error, "doesn't validate with") {
-			continue // ignore this error, it's not useful
-		}
-
 		fail := &errors.SchemaValidationFailure{
-			Reason:        er.Error,
-			Location:      er.KeywordLocation,
-			OriginalError: scErrs,
+			Reason:           schema_validation.GetFailureReason(er),
+			Location:         er.KeywordLocation,
+			InstanceLocation: er.InstanceLocation,
+			OriginalError:    scErrs,
+			LeafError:        schema_validation.LocateValidationErrorCause(scErrs, er),
 		}
 		if schema != nil {
 			rendered, err := schema.RenderInline()
@@ -197,14 +308,18 @@ func formatJsonSchemaValidationError(schema *base.Schema, scErrs *jsonschema.Val
 	if len(schema.Type) > 0 {
 		schemaType = schema.Type[0]
 	}
+	specLine, specCol := -1, -1
+	if keyNode := schema.GoLow().Type.KeyNode; keyNode != nil {
+		specLine, specCol = keyNode.Line, keyNode.Column
+	}
 	validationErrors = append(validationErrors, &errors.ValidationError{
 		ValidationType:    validationType,
 		ValidationSubType: subValType,
 		Message:           fmt.Sprintf("%s '%s' failed to validate", entity, name),
 		Reason: fmt.Sprintf("%s '%s' is defined as an %s, "+
 			"however it failed to pass a schema validation", reasonEntity, name, schemaType),
-		SpecLine:               schema.GoLow().Type.KeyNode.Line,
-		SpecCol:                schema.GoLow().Type.KeyNode.Column,
+		SpecLine:               specLine,
+		SpecCol:                specCol,
 		SchemaValidationErrors: schemaValidationErrors,
 		HowToFix:               errors.HowToFixInvalidSchema,
 	})
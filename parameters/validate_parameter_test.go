@@ -0,0 +1,262 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package parameters
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildParameterValueParams(t *testing.T) []*v3.Parameter {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: integer
+            minimum: 1
+        - name: sauce
+          in: query
+          schema:
+            type: string
+            enum: [ketchup, mustard]
+        - name: X-Order-Code
+          in: header
+          schema:
+            type: string
+            pattern: ^[A-Z]{3}-[0-9]+$
+        - name: toppings
+          in: query
+          style: pipeDelimited
+          schema:
+            type: array
+            items:
+              type: string
+              maxLength: 6
+        - name: hungry
+          in: cookie
+          schema:
+            type: boolean`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	assert.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+	return m.Model.Paths.PathItems.GetOrZero("/burgers/{burgerId}").Get.Parameters
+}
+
+func TestValidateParameterValue(t *testing.T) {
+	params := buildParameterValueParams(t)
+
+	assert.Empty(t, ValidateParameterValue(params[0], "12"))
+	assert.Empty(t, ValidateParameterValue(params[1], "mustard"))
+	assert.Empty(t, ValidateParameterValue(params[2], "ABC-123"))
+	assert.Empty(t, ValidateParameterValue(params[3], "onion|pickle"))
+	assert.Empty(t, ValidateParameterValue(params[4], "true"))
+}
+
+func TestValidateParameterValue_Invalid(t *testing.T) {
+	params := buildParameterValueParams(t)
+
+	errs := ValidateParameterValue(params[0], "0")
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "Path parameter 'burgerId' failed to validate", errs[0].Message)
+	assert.Equal(t, "path", errs[0].ValidationSubType)
+	assert.Equal(t, "Change the value '0' so it is greater than or equal to 1", errs[0].HowToFix)
+
+	errs = ValidateParameterValue(params[0], "burger")
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/type", errs[0].SchemaValidationErrors[0].Location)
+
+	errs = ValidateParameterValue(params[1], "mayo")
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "Query parameter 'sauce' failed to validate", errs[0].Message)
+
+	errs = ValidateParameterValue(params[2], "abc-123")
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "Header parameter 'X-Order-Code' failed to validate", errs[0].Message)
+
+	errs = ValidateParameterValue(params[3], "onion|jalapeno")
+	assert.Len(t, errs, 1)
+
+	errs = ValidateParameterValue(params[4], "1")
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "Cookie parameter 'hungry' failed to validate", errs[0].Message)
+}
+
+func TestValidateParameterValue_NoSchema(t *testing.T) {
+	assert.Empty(t, ValidateParameterValue(&v3.Parameter{Name: "empty", In: "query"}, "anything"))
+	assert.Empty(t, ValidateParameterValue(nil, "anything"))
+}
+
+func TestParameterExamples(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          example: 12
+          examples:
+            tooSmall:
+              value: 0
+            notANumber:
+              value: cheeseburger
+            remote:
+              externalValue: https://things.com/burgerId.txt
+          schema:
+            type: integer
+            minimum: 1
+        - name: toppings
+          in: query
+          style: pipeDelimited
+          examples:
+            classic:
+              value: [onion, pickle]
+            fancy:
+              value: [onion, truffles]
+          schema:
+            type: array
+            items:
+              type: string
+              maxLength: 6`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	assert.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+	params := m.Model.Paths.PathItems.GetOrZero("/burgers/{burgerId}").Get.Parameters
+
+	examples := ParameterExamples(params[0])
+	assert.Len(t, examples, 3)
+	assert.Equal(t, "", examples[0].Name)
+	assert.Equal(t, "12", examples[0].Value)
+	assert.True(t, examples[0].Valid)
+	assert.Empty(t, examples[0].Errors)
+	assert.Equal(t, "tooSmall", examples[1].Name)
+	assert.False(t, examples[1].Valid)
+	assert.NotEmpty(t, examples[1].Errors)
+	assert.Equal(t, "notANumber", examples[2].Name)
+	assert.False(t, examples[2].Valid)
+
+	examples = ParameterExamples(params[1])
+	assert.Len(t, examples, 2)
+	assert.Equal(t, "onion|pickle", examples[0].Value)
+	assert.True(t, examples[0].Valid)
+	assert.Equal(t, "onion|truffles", examples[1].Value)
+	assert.False(t, examples[1].Valid)
+
+	assert.Nil(t, ParameterExamples(nil))
+}
+
+func TestBindParameters(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    parameters:
+      - name: burgerId
+        in: path
+        required: true
+        schema:
+          type: integer
+          minimum: 1
+    get:
+      parameters:
+        - name: toppings
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: X-Request-Id
+          in: header
+          required: true
+          schema:
+            type: string
+        - name: session
+          in: cookie
+          schema:
+            type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	pathItem := m.Model.Paths.PathItems.GetOrZero("/burgers/{burgerId}")
+	operation := pathItem.Get
+
+	type burgerParams struct {
+		BurgerID  int64    `openapi:"burgerId,in=path"`
+		Toppings  []string `openapi:"toppings,in=query"`
+		Limit     *int     `openapi:"limit,in=query"`
+		RequestID string   `openapi:"x-request-id,in=header"`
+		Session   string   `openapi:"session"`
+		Ignored   string
+	}
+
+	request, _ := http.NewRequest(http.MethodGet,
+		"https://things.com/burgers/42?toppings=cheese&toppings=pickles", nil)
+	request.Header.Set("X-Request-Id", "abc")
+	request.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+
+	var params burgerParams
+	// the path parameter is defined by the path item, and is bound along with the parameters of the operation.
+	errs := BindParameters(request, pathItem, operation, map[string]string{"burgerId": "42"}, &params)
+	assert.Empty(t, errs)
+	assert.Equal(t, burgerParams{BurgerID: 42, Toppings: []string{"cheese", "pickles"}, RequestID: "abc",
+		Session: "s1"}, params)
+
+	// invalid and missing values are reported, and their fields are not bound.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/0?limit=10", nil)
+	params = burgerParams{}
+	errs = BindParameters(request, pathItem, operation, map[string]string{"burgerId": "0"}, &params)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "Path parameter 'burgerId' failed to validate", errs[0].Message)
+	assert.Equal(t, "Header parameter 'X-Request-Id' is missing", errs[1].Message)
+	assert.Equal(t, "/burgers/0", errs[1].RequestPath)
+	assert.Equal(t, int64(0), params.BurgerID)
+	require.NotNil(t, params.Limit)
+	assert.Equal(t, 10, *params.Limit)
+
+	// the options are applied to the values, so a signed query number is rejected with strict query numbers.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/42?limit=%2B5", nil)
+	request.Header.Set("X-Request-Id", "abc")
+	params = burgerParams{}
+	assert.Empty(t, BindParameters(request, pathItem, operation, map[string]string{"burgerId": "42"}, &params))
+	assert.Equal(t, 5, *params.Limit)
+	params = burgerParams{}
+	errs = BindParameters(request, pathItem, operation, map[string]string{"burgerId": "42"}, &params,
+		config.WithStrictQueryNumbers())
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Query parameter 'limit' is not a strictly formatted number", errs[0].Message)
+	assert.Nil(t, params.Limit)
+
+	type badParams struct {
+		Limit   bool   `openapi:"limit,in=query"`
+		Unknown string `openapi:"unknown,in=query"`
+	}
+	var bad badParams
+	errs = BindParameters(request, pathItem, operation, nil, &bad)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "Parameter 'limit' cannot be bound to the field 'Limit'", errs[0].Message)
+	assert.Equal(t, helpers.ParameterBinding, errs[0].ValidationSubType)
+	assert.Equal(t, "Parameter 'unknown' of the field 'Unknown' is not defined", errs[1].Message)
+
+	errs = BindParameters(request, pathItem, operation, nil, bad)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Parameters cannot be bound to 'parameters.badParams'", errs[0].Message)
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
@@ -21,7 +22,7 @@ func (v *paramValidator) ValidateSecurity(request *http.Request) (bool, []*error
 	var pathFound string
 	var errs []*errors.ValidationError
 	if v.pathItem == nil {
-		pathItem, errs, pathFound = paths.FindPath(request, v.document)
+		pathItem, errs, pathFound = paths.FindPath(request, v.document, config.WithExistingOpts(v.options))
 		if pathItem == nil || errs != nil {
 			v.errors = errs
 			return false, errs
@@ -32,7 +33,7 @@ func (v *paramValidator) ValidateSecurity(request *http.Request) (bool, []*error
 	}
 
 	// extract security for the operation
-	security := helpers.ExtractSecurityForOperation(request, pathItem)
+	security := v.operations.ExtractSecurityForOperation(request, pathItem)
 
 	if security == nil {
 		return true, nil
@@ -56,6 +57,8 @@ func (v *paramValidator) ValidateSecurity(request *http.Request) (bool, []*error
 					},
 				}
 				errors.PopulateValidationErrors(validationErrors, request, pathFound)
+				errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+				errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
 
 				return false, validationErrors
 			}
@@ -79,6 +82,8 @@ func (v *paramValidator) ValidateSecurity(request *http.Request) (bool, []*error
 						}
 
 						errors.PopulateValidationErrors(validationErrors, request, pathFound)
+						errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+						errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
 
 						return false, validationErrors
 					}
@@ -101,6 +106,8 @@ func (v *paramValidator) ValidateSecurity(request *http.Request) (bool, []*error
 						}
 
 						errors.PopulateValidationErrors(validationErrors, request, pathFound)
+						errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+						errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
 
 						return false, validationErrors
 					}
@@ -127,6 +134,8 @@ func (v *paramValidator) ValidateSecurity(request *http.Request) (bool, []*error
 						}
 
 						errors.PopulateValidationErrors(validationErrors, request, pathFound)
+						errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+						errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
 
 						return false, validationErrors
 					}
@@ -154,6 +163,8 @@ func (v *paramValidator) ValidateSecurity(request *http.Request) (bool, []*error
 						}
 
 						errors.PopulateValidationErrors(validationErrors, request, pathFound)
+						errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+						errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
 
 						return false, validationErrors
 					}
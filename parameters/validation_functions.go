@@ -9,7 +9,6 @@ import (
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/datamodel/high/v3"
-	"slices"
 	"strconv"
 	"strings"
 )
@@ -21,8 +20,11 @@ func ValidateCookieArray(
 	var validationErrors []*errors.ValidationError
 	itemsSchema := sch.Items.A.Schema()
 
-	// header arrays can only be encoded as CSV
-	items := helpers.ExplodeQueryValue(value, helpers.DefaultDelimited)
+	// unexploded cookie arrays are encoded as CSV, exploded arrays send each value as a separate cookie.
+	items := []string{value}
+	if !param.IsExploded() {
+		items = helpers.ExplodeQueryValue(value, helpers.DefaultDelimited)
+	}
 
 	// now check each item in the array
 	for _, item := range items {
@@ -33,7 +35,12 @@ func ValidateCookieArray(
 				if _, err := strconv.ParseFloat(item, 64); err != nil {
 					validationErrors = append(validationErrors,
 						errors.IncorrectCookieParamArrayNumber(param, item, sch, itemsSchema))
+					break
 				}
+				validationErrors = append(validationErrors,
+					validateArrayItemSchema(itemsSchema, param, itemType, item,
+						"Cookie array parameter", "The cookie parameter (which is an array)",
+						helpers.ParameterValidationCookie)...)
 			case helpers.Boolean:
 				if _, err := strconv.ParseBool(item); err != nil {
 					validationErrors = append(validationErrors,
@@ -46,8 +53,10 @@ func ValidateCookieArray(
 						errors.IncorrectCookieParamArrayBoolean(param, item, sch, itemsSchema))
 				}
 			case helpers.String:
-				// do nothing for now.
-				continue
+				validationErrors = append(validationErrors,
+					validateArrayItemSchema(itemsSchema, param, itemType, item,
+						"Cookie array parameter", "The cookie parameter (which is an array)",
+						helpers.ParameterValidationCookie)...)
 			}
 		}
 	}
@@ -61,8 +70,11 @@ func ValidateHeaderArray(
 	var validationErrors []*errors.ValidationError
 	itemsSchema := sch.Items.A.Schema()
 
-	// header arrays can only be encoded as CSV
+	// header arrays can only be encoded as CSV, any whitespace around the commas is not part of the items.
 	items := helpers.ExplodeQueryValue(value, helpers.DefaultDelimited)
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
 
 	// now check each item in the array
 	for _, item := range items {
@@ -73,7 +85,12 @@ func ValidateHeaderArray(
 				if _, err := strconv.ParseFloat(item, 64); err != nil {
 					validationErrors = append(validationErrors,
 						errors.IncorrectHeaderParamArrayNumber(param, item, sch, itemsSchema))
+					break
 				}
+				validationErrors = append(validationErrors,
+					validateArrayItemSchema(itemsSchema, param, itemType, item,
+						"Header array parameter", "The header parameter (which is an array)",
+						helpers.ParameterValidationHeader)...)
 			case helpers.Boolean:
 				if _, err := strconv.ParseBool(item); err != nil {
 					validationErrors = append(validationErrors,
@@ -86,8 +103,10 @@ func ValidateHeaderArray(
 						errors.IncorrectHeaderParamArrayBoolean(param, item, sch, itemsSchema))
 				}
 			case helpers.String:
-				// do nothing for now.
-				continue
+				validationErrors = append(validationErrors,
+					validateArrayItemSchema(itemsSchema, param, itemType, item,
+						"Header array parameter", "The header parameter (which is an array)",
+						helpers.ParameterValidationHeader)...)
 			}
 		}
 	}
@@ -123,25 +142,20 @@ func ValidateQueryArray(
 		}
 	}
 
-	// check if the param is within an enum
-	checkEnum := func(enumCheck, item string) {
+	// check if the param is within an enum, returns false if an error was added.
+	checkEnum := func(item string) bool {
 		// check if the array param is within an enum
-		if sch.Items.IsA() {
-			itemsSch := sch.Items.A.Schema()
-			if itemsSch.Enum != nil {
-				matchFound := false
-				for _, enumVal := range itemsSch.Enum {
-					if strings.TrimSpace(enumCheck) == fmt.Sprint(enumVal.Value) {
-						matchFound = true
-						break
-					}
-				}
-				if !matchFound {
-					validationErrors = append(validationErrors,
-						errors.IncorrectQueryParamEnumArray(param, item, sch))
+		if itemsSchema.Enum != nil {
+			for _, enumVal := range itemsSchema.Enum {
+				if strings.TrimSpace(item) == fmt.Sprint(enumVal.Value) {
+					return true
 				}
 			}
+			validationErrors = append(validationErrors,
+				errors.IncorrectQueryParamEnumArray(param, item, sch))
+			return false
 		}
+		return true
 	}
 
 	// now check each item in the array
@@ -156,7 +170,12 @@ func ValidateQueryArray(
 					break
 				}
 				// will it blend?
-				checkEnum(ef, item)
+				if checkEnum(item) {
+					validationErrors = append(validationErrors,
+						validateArrayItemSchema(itemsSchema, param, itemType, item,
+							"Query array parameter", "The query parameter (which is an array)",
+							helpers.ParameterValidationQuery)...)
+				}
 
 			case helpers.Boolean:
 				if _, err := strconv.ParseBool(item); err != nil {
@@ -177,13 +196,33 @@ func ValidateQueryArray(
 			case helpers.String:
 
 				// will it float?
-				checkEnum(ef, item)
+				if checkEnum(item) {
+					validationErrors = append(validationErrors,
+						validateArrayItemSchema(itemsSchema, param, itemType, item,
+							"Query array parameter", "The query parameter (which is an array)",
+							helpers.ParameterValidationQuery)...)
+				}
 			}
 		}
 	}
 	return validationErrors
 }
 
+// validateArrayItemSchema will validate a single (already type checked) array item against the items schema,
+// so constraints such as pattern, minLength or minimum are applied to every element of the array.
+func validateArrayItemSchema(itemsSchema *base.Schema, param *v3.Parameter, itemType, item,
+	entity, reasonEntity, subValType string,
+) []*errors.ValidationError {
+	var value any = item
+	switch itemType {
+	case helpers.Integer, helpers.Number:
+		f, _ := strconv.ParseFloat(item, 64)
+		value = f
+	}
+	return ValidateSingleParameterSchema(itemsSchema, value, entity, reasonEntity, param.Name,
+		helpers.ParameterValidation, subValType)
+}
+
 // ValidateQueryParamStyle will validate a query parameter by style
 func ValidateQueryParamStyle(param *v3.Parameter, as []*helpers.QueryParam) []*errors.ValidationError {
 
@@ -193,21 +232,10 @@ stopValidation:
 		for i := range qp.Values {
 			switch param.Style {
 			case helpers.DeepObject:
-				// check if the object has additional properties defined that treat this as an array
-				if param.Schema != nil {
-					pSchema := param.Schema.Schema()
-					if slices.Contains(pSchema.Type, helpers.Array) {
-						continue
-					}
-					if pSchema.AdditionalProperties != nil && pSchema.AdditionalProperties.IsA() {
-						addPropSchema := pSchema.AdditionalProperties.A.Schema()
-						if addPropSchema.Type != nil && len(addPropSchema.Type) > 0 {
-							if slices.Contains(addPropSchema.Type, helpers.Array) {
-								// an array can have more than one value.
-								continue
-							}
-						}
-					}
+				// check if the property (or additional properties) is defined as an array, which can have more
+				// than one value.
+				if param.Schema != nil && helpers.DeepObjectAllowsMultipleValues(param.Schema.Schema(), qp) {
+					continue
 				}
 				if len(qp.Values) > 1 {
 					validationErrors = append(validationErrors, errors.InvalidDeepObject(param, qp))
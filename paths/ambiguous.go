@@ -0,0 +1,197 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package paths
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// PathTemplate is a path template from a specification, and its location.
+type PathTemplate struct {
+	// Path is the path template, as it is defined in the specification.
+	Path string `json:"path" yaml:"path"`
+
+	// Line is the line number of the path in the specification.
+	Line int `json:"line" yaml:"line"`
+
+	// Column is the column number of the path in the specification.
+	Column int `json:"column" yaml:"column"`
+}
+
+// AmbiguousPaths is a pair of path templates that match exactly the same request paths. Only the First path can
+// ever be matched, as paths are matched in the order they are defined.
+type AmbiguousPaths struct {
+	First  PathTemplate `json:"first" yaml:"first"`
+	Second PathTemplate `json:"second" yaml:"second"`
+}
+
+// DetectAmbiguousPaths will return every pair of path templates in a document that match the same request paths,
+// for example '/users/{id}' and '/users/{userId}'. Path parameters match any value, so templates are ambiguous when
+// their literal segments are the same, regardless of how their parameters are named. Each ambiguous path is paired
+// with the first path (in document order) that it is ambiguous with.
+func DetectAmbiguousPaths(document *v3.Document) []*AmbiguousPaths {
+	if document == nil || document.Paths == nil {
+		return nil
+	}
+	var ambiguous []*AmbiguousPaths
+	first := make(map[string]PathTemplate)
+	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
+		key, ok := effectivePathTemplate(pair.Key())
+		if !ok {
+			continue
+		}
+		template := newPathTemplate(pair.Key(), pair.Value())
+		if existing, found := first[key]; found {
+			ambiguous = append(ambiguous, &AmbiguousPaths{First: existing, Second: template})
+			continue
+		}
+		first[key] = template
+	}
+	return ambiguous
+}
+
+// SegmentOverlap is a segment of two path templates, where a literal segment of one is matched by a template
+// segment of the other, for example 'latest' and '{version}'.
+type SegmentOverlap struct {
+	// Position is the index of the segment in the paths, starting at zero for the first segment.
+	Position int `json:"position" yaml:"position"`
+
+	// First is the segment of the first path.
+	First string `json:"first" yaml:"first"`
+
+	// Second is the segment of the second path.
+	Second string `json:"second" yaml:"second"`
+}
+
+// OverlappingPaths is a pair of path templates that both match some of the same request paths, because a literal
+// segment of one is at the same position as a template segment of the other, for example '/files/latest' and
+// '/files/{version}'. Paths are matched in the order they are defined, so a request that matches both paths (such as
+// '/files/latest') is matched by the First path.
+type OverlappingPaths struct {
+	First  PathTemplate `json:"first" yaml:"first"`
+	Second PathTemplate `json:"second" yaml:"second"`
+
+	// Segments are the positions where a literal segment of one path overlaps a template segment of the other.
+	Segments []SegmentOverlap `json:"segments" yaml:"segments"`
+}
+
+// DetectOverlappingPaths will return every pair of path templates in a document where a literal segment of one path
+// and a template segment of the other are at the same position, and every other segment of the paths can match the
+// same values, so some request paths are matched by both. Overlaps are often intended (a static '/files/latest'
+// beside a dynamic '/files/{version}'), but only the path defined first is matched, so they are reported with the
+// location of both paths for authors to confirm the order. A literal segment only overlaps a compound template
+// segment (such as '{name}.json') if the template can match it. Paths that are ambiguous, and match exactly the
+// same request paths, are reported by DetectAmbiguousPaths instead.
+func DetectOverlappingPaths(document *v3.Document) []*OverlappingPaths {
+	if document == nil || document.Paths == nil {
+		return nil
+	}
+	type splitPath struct {
+		template PathTemplate
+		segments []string
+	}
+	var paths []splitPath
+	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
+		if _, ok := effectivePathTemplate(pair.Key()); !ok {
+			continue
+		}
+		paths = append(paths, splitPath{
+			template: newPathTemplate(pair.Key(), pair.Value()),
+			segments: strings.Split(strings.TrimPrefix(pair.Key(), helpers.Slash), helpers.Slash),
+		})
+	}
+
+	var overlapping []*OverlappingPaths
+	for i := range paths {
+		for j := i + 1; j < len(paths); j++ {
+			if segments, ok := overlappingSegments(paths[i].segments, paths[j].segments); ok && len(segments) > 0 {
+				overlapping = append(overlapping, &OverlappingPaths{
+					First:    paths[i].template,
+					Second:   paths[j].template,
+					Segments: segments,
+				})
+			}
+		}
+	}
+	return overlapping
+}
+
+// overlappingSegments compares the segments of two paths, and returns the positions where a literal segment of one
+// is matched by a template segment of the other. If any segment of one path cannot match the segment of the other at
+// the same position, the paths do not overlap.
+func overlappingSegments(first, second []string) ([]SegmentOverlap, bool) {
+	if len(first) != len(second) {
+		return nil, false
+	}
+	var overlaps []SegmentOverlap
+	for i := range first {
+		firstTemplate, secondTemplate := strings.Contains(first[i], "{"), strings.Contains(second[i], "{")
+		switch {
+		case firstTemplate && secondTemplate:
+			continue
+		case !firstTemplate && !secondTemplate:
+			if unescapeSegment(first[i]) != unescapeSegment(second[i]) {
+				return nil, false
+			}
+			continue
+		}
+		literal, template := first[i], second[i]
+		if firstTemplate {
+			literal, template = second[i], first[i]
+		}
+		if !templateMatchesLiteral(template, unescapeSegment(literal)) {
+			return nil, false
+		}
+		overlaps = append(overlaps, SegmentOverlap{Position: i, First: first[i], Second: second[i]})
+	}
+	return overlaps, true
+}
+
+// templateMatchesLiteral returns true if a template segment can match a literal segment. Parameter values cannot be
+// empty, so an empty segment is never matched.
+func templateMatchesLiteral(template, literal string) bool {
+	if literal == "" {
+		return false
+	}
+	if helpers.IsCompoundPathSegment(template) {
+		return len(helpers.MatchCompoundPathSegment(template, literal)) > 0
+	}
+	return true
+}
+
+// newPathTemplate returns a path template, located by the key of its path item.
+func newPathTemplate(path string, pathItem *v3.PathItem) PathTemplate {
+	template := PathTemplate{Path: path, Line: -1, Column: -1}
+	if low := pathItem.GoLow(); low != nil && low.KeyNode != nil {
+		template.Line, template.Column = low.KeyNode.Line, low.KeyNode.Column
+	}
+	return template
+}
+
+// templateParamRegex matches each parameter of a template segment, such as the '{name}' of '{name}.json'.
+var templateParamRegex = regexp.MustCompile(`{[^{}]+}`)
+
+// effectivePathTemplate returns the form of a path template that is used to compare it with other templates. Every
+// template parameter is replaced with '{}' (as it matches any value), keeping the literal text of compound segments,
+// so '{name}.json' and '{name}.xml' stay distinct. Literal segments are unescaped. Paths with malformed template
+// segments can never be matched, so they are not ambiguous with anything.
+func effectivePathTemplate(path string) (string, bool) {
+	segments := strings.Split(strings.TrimPrefix(path, helpers.Slash), helpers.Slash)
+	for i, segment := range segments {
+		if strings.ContainsAny(segment, "{}") {
+			if !helpers.IsValidPathSegmentTemplate(segment) {
+				return "", false
+			}
+			segments[i] = templateParamRegex.ReplaceAllString(segment, "{}")
+			continue
+		}
+		segments[i] = unescapeSegment(segment)
+	}
+	return strings.Join(segments, helpers.Slash), true
+}
@@ -0,0 +1,246 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package paths
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// PathMatcher is a precompiled matcher for the paths of a document. Paths are indexed by their segment count and
+// their literal value, so most paths are eliminated before any comparison work is performed. A PathMatcher returns
+// the same results as MatchPath, and is safe for concurrent use, as long as the document is not modified.
+//
+// The index assumes a path shares the document servers. If a path item or its operations override the servers, then
+// the base path to strip depends on the path, so the path is not indexed, and is compared on its own, with its own
+// base paths. The same is true of a path with a parameter that is matched across segments (see
+// config.WithReservedPathParameters). Every other path of the document is still matched using the index.
+type PathMatcher struct {
+	document      *v3.Document
+	options       *config.ValidationOptions
+	operations    *helpers.OperationCache
+	basePaths     []string
+	serverIndexes []int
+	paths         []indexedPath
+
+	// overriding are the paths that are not indexed, in the order they are defined.
+	overriding []int
+
+	// paths are indexed both with and without their fragments, as fragments are only compared when the
+	// request has one.
+	bySegmentCount         map[int][]int
+	bySegmentCountFragment map[int][]int
+	literals               map[string][]int
+	literalsFragment       map[string][]int
+}
+
+type indexedPath struct {
+	path         string // the path, with any fragment removed.
+	pathFragment string // the path, as it is defined in the document.
+	pathItem     *v3.PathItem
+}
+
+// NewPathMatcher will create a new PathMatcher for the paths of a document. Options can be supplied to change how
+// request paths are matched, for example config.WithDuplicateSlashNormalization.
+func NewPathMatcher(document *v3.Document, opts ...config.Option) *PathMatcher {
+	options := config.NewValidationOptions(opts...)
+	m := &PathMatcher{
+		document:               document,
+		options:                options,
+		operations:             options.OperationCacheFor(document),
+		bySegmentCount:         make(map[int][]int),
+		bySegmentCountFragment: make(map[int][]int),
+		literals:               make(map[string][]int),
+		literalsFragment:       make(map[string][]int),
+	}
+	if !m.options.DisableServerStripping {
+		m.basePaths, m.serverIndexes = getServerBasePaths(document)
+	}
+	if document.Paths == nil {
+		return m
+	}
+	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
+		path, _, _ := strings.Cut(pair.Key(), "#")
+		i := len(m.paths)
+		m.paths = append(m.paths, indexedPath{path: path, pathFragment: pair.Key(), pathItem: pair.Value()})
+		if (!m.options.DisableServerStripping && hasServerOverrides(pair.Value())) ||
+			(m.options.AllowReservedPathParameters && hasReservedPathParams(pair.Value())) {
+			m.overriding = append(m.overriding, i)
+			continue
+		}
+
+		count, countFragment := segmentCount(m.templatePath(path)), segmentCount(m.templatePath(pair.Key()))
+		m.bySegmentCount[count] = append(m.bySegmentCount[count], i)
+		m.bySegmentCountFragment[countFragment] = append(m.bySegmentCountFragment[countFragment], i)
+		m.indexLiteral(m.literals, path, i)
+		m.indexLiteral(m.literalsFragment, pair.Key(), i)
+	}
+	return m
+}
+
+// hasServerOverrides returns true if a path item, or any of its operations, defines servers.
+func hasServerOverrides(pathItem *v3.PathItem) bool {
+	if len(pathItem.Servers) > 0 {
+		return true
+	}
+	for pair := orderedmap.First(pathItem.GetOperations()); pair != nil; pair = pair.Next() {
+		if len(pair.Value().Servers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasReservedPathParams returns true if a path item, or any of its operations, defines a path parameter that allows
+// reserved characters.
+func hasReservedPathParams(pathItem *v3.PathItem) bool {
+	if len(helpers.ReservedPathParamNames(pathItem.Parameters)) > 0 {
+		return true
+	}
+	for pair := orderedmap.First(pathItem.GetOperations()); pair != nil; pair = pair.Next() {
+		if len(helpers.ReservedPathParamNames(pair.Value().Parameters)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// indexLiteral indexes every request path that is a literal match for a path, with and without each base path.
+func (m *PathMatcher) indexLiteral(literals map[string][]int, path string, i int) {
+	path = m.templatePath(path)
+	literals[m.literalKey(path)] = append(literals[m.literalKey(path)], i)
+	for _, basePath := range m.basePaths {
+		merged := strings.TrimSuffix(basePath, "/") + path
+		if merged != path {
+			literals[m.literalKey(merged)] = append(literals[m.literalKey(merged)], i)
+		}
+	}
+}
+
+// templatePath returns a path as it is compared against request paths, without any trailing slash if they are
+// ignored.
+func (m *PathMatcher) templatePath(path string) string {
+	path = withLeadingSlash(path)
+	if m.options.IgnoreTrailingSlash {
+		return trimTrailingSlash(path)
+	}
+	return path
+}
+
+// literalKey returns the key a literal path is indexed by, which is lower case if paths are case-insensitive.
+func (m *PathMatcher) literalKey(path string) string {
+	if m.options.CaseInsensitivePaths {
+		return strings.ToLower(path)
+	}
+	return path
+}
+
+// Candidates will return the paths (with any fragments removed) that have the given number of segments, in the
+// order they are defined in the document. Only these paths can be a template match for a request path with the
+// same number of segments.
+func (m *PathMatcher) Candidates(segments int) []string {
+	var candidates []string
+	for _, i := range m.bySegmentCount[segments] {
+		candidates = append(candidates, m.paths[i].path)
+	}
+	return candidates
+}
+
+// Match will find the path that matches the request path, and return everything that was learned along the way as
+// a PathMatchResult. If no path matches, then PathItem is nil and Errors explains why.
+func (m *PathMatcher) Match(request *http.Request) *PathMatchResult {
+	result, _ := m.MatchCounted(request)
+	return result
+}
+
+// MatchCounted is the same as Match, it also returns the number of paths that were compared against the request
+// path, which shows how much work the match took (for example, to report as a metric).
+func (m *PathMatcher) MatchCounted(request *http.Request) (*PathMatchResult, int) {
+	req := newPreparedPath(request, m.options, m.basePaths)
+
+	bySegmentCount, literals := m.bySegmentCount, m.literals
+	if req.hasFragment {
+		bySegmentCount, literals = m.bySegmentCountFragment, m.literalsFragment
+	}
+
+	// the first path (in document order) wins, so find the first literal match, and then only check the
+	// template candidates that come before it.
+	found, candidates := -1, 0
+	literalMatches := literals[m.literalKey(req.path)]
+	if len(req.segments) == 0 {
+		literalMatches = m.literals[helpers.Slash] // the root can only be matched by the root.
+	}
+	for _, i := range literalMatches {
+		// literals are indexed in lower case, with their base paths, which must still match exactly.
+		if m.options.CaseInsensitivePaths && len(req.segments) > 0 &&
+			!checkPathAgainstBase(req.path, m.templatePath(m.pathOf(i, req.hasFragment)), m.basePaths, true) {
+			continue
+		}
+		if hasOperation(m.operations, m.paths[i].pathItem, request) {
+			candidates++
+			found = i
+			break
+		}
+	}
+	for _, i := range bySegmentCount[len(req.segments)] {
+		if found >= 0 && i >= found {
+			break
+		}
+		if !hasOperation(m.operations, m.paths[i].pathItem, request) {
+			continue
+		}
+		candidates++
+		if comparePathSegments(m.templatePath(m.pathOf(i, req.hasFragment)), req, m.basePaths) {
+			found = i
+			break
+		}
+	}
+
+	// the paths that are not indexed are compared one at a time, up to the indexed path that matched.
+	for _, i := range m.overriding {
+		if found >= 0 && i >= found {
+			break
+		}
+		if !hasOperation(m.operations, m.paths[i].pathItem, request) {
+			continue
+		}
+		candidates++
+		if match := matchPathItem(request, m.paths[i].pathFragment, m.paths[i].pathItem, req, m.basePaths,
+			m.serverIndexes, m.options, m.operations); match != nil {
+			return newPathMatchResult(request, m.paths[i].pathItem, match.path, match.req, match.basePaths,
+				match.serverIndexes, m.operations), candidates
+		}
+	}
+
+	if found < 0 {
+		result := newPathMatchResult(request, nil, "", req, m.basePaths, m.serverIndexes, m.operations)
+		attachDefinedMethods(result, request,
+			definedMethods(request, m.document, m.options, m.basePaths, m.operations))
+		if m.options.PathSuggestions > 0 {
+			attachPathSuggestions(result, suggestPaths(m.document, req.segments, m.options.PathSuggestions))
+		}
+		errors.FormatValidationErrors(result.Errors, m.options.MessageFormatterFor)
+		return result, candidates
+	}
+	return newPathMatchResult(request, m.paths[found].pathItem, m.pathOf(found, req.hasFragment), req,
+		m.basePaths, m.serverIndexes, m.operations), candidates
+}
+
+func (m *PathMatcher) pathOf(i int, withFragment bool) string {
+	if withFragment {
+		return m.paths[i].pathFragment
+	}
+	return m.paths[i].path
+}
+
+// segmentCount returns the number of segments in a path, counted the same way as a request path.
+func segmentCount(path string) int {
+	return strings.Count(strings.TrimPrefix(path, "/"), "/") + 1
+}
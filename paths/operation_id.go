@@ -0,0 +1,31 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package paths
+
+import (
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// FindOperationById will find the operation of a document with an operationId, and return it along with the path
+// item that defines it and the path template of that path item, so a request can be validated against a known
+// operation without matching its path first. Operation IDs are case-sensitive, and should be unique within a
+// document, if they are not then the first operation (in document order) with the ID is returned. If no operation
+// has the ID, the path item and operation are nil, and the path template is empty.
+func FindOperationById(document *v3.Document, operationId string) (*v3.PathItem, *v3.Operation, string) {
+	if document == nil || document.Paths == nil || operationId == "" {
+		return nil, nil, ""
+	}
+	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
+		if pair.Value() == nil {
+			continue
+		}
+		for op := orderedmap.First(pair.Value().GetOperations()); op != nil; op = op.Next() {
+			if op.Value() != nil && op.Value().OperationId == operationId {
+				return pair.Value(), op.Value(), pair.Key()
+			}
+		}
+	}
+	return nil, nil, ""
+}
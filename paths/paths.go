@@ -8,175 +8,389 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
 )
 
+var duplicateSlashRegex = regexp.MustCompile(`/{2,}`)
+
 // FindPath will find the path in the document that matches the request path. If a successful match was found, then
 // the first return value will be a pointer to the PathItem. The second return value will contain any validation errors
-// that were picked up when locating the path. Number/Integer validation is performed in any path parameters in the request.
-// The third return value will be the path that was found in the document, as it pertains to the contract, so all path
+// that were picked up when locating the path. No parameters are validated, see parameters.FindPathAndValidate to
+// validate them as part of the lookup. The third return value will be the path that was found in the document, as it pertains to the contract, so all path
 // parameters will not have been replaced with their values from the request - allowing model lookups.
-func FindPath(request *http.Request, document *v3.Document) (*v3.PathItem, []*errors.ValidationError, string) {
-	var validationErrors []*errors.ValidationError
+//
+// Options can be supplied to change how the request path is matched, for example config.WithDuplicateSlashNormalization.
+//
+// FindPath is a thin wrapper around MatchPath, which returns a PathMatchResult with more detail about the match.
+func FindPath(request *http.Request, document *v3.Document, opts ...config.Option) (*v3.PathItem, []*errors.ValidationError, string) {
+	result := MatchPath(request, document, opts...)
+	return result.PathItem, result.Errors, result.FoundPath
+}
+
+// FindPathInOrder is the same as FindPath, except the path templates of the document are compared in the order
+// they are supplied (see MatchPathInOrder), rather than the order they are defined in the document.
+func FindPathInOrder(request *http.Request, document *v3.Document, templates []string,
+	opts ...config.Option) (*v3.PathItem, []*errors.ValidationError, string) {
+	result := MatchPathInOrder(request, document, templates, opts...)
+	return result.PathItem, result.Errors, result.FoundPath
+}
+
+// PathMatchResult is the result of matching a request against the paths of a document.
+type PathMatchResult struct {
+	// PathItem is the path item that was matched, or nil if no path matched.
+	PathItem *v3.PathItem
+
+	// Operation is the operation of the matched path item for the request method.
+	Operation *v3.Operation
+
+	// FoundPath is the path that was found in the document, as it pertains to the contract, so all path parameters
+	// will not have been replaced with their values from the request - allowing model lookups.
+	FoundPath string
+
+	// Literal is true if the found path is a literal path, without any path parameters (such as /burgers), and false
+	// if it is a template that the request path matched (such as /burgers/{id}).
+	Literal bool
 
-	basePaths := getBasePaths(document)
-	stripped := StripRequestPath(request, document)
+	// Params holds the raw value supplied by the request for each parameter in the path template.
+	Params map[string]string
+
+	// ServerIndex is the index of the server whose base path prefixed the request path, or -1 if no server base path
+	// was used. The index is within the most specific servers of the operation (the operation, path item or document
+	// servers, see MatchPathInOrder).
+	ServerIndex int
+
+	// strippedPath is the request path, with the base path of the matched server removed.
+	strippedPath string
+
+	// Errors holds any validation errors that were picked up when locating the path.
+	Errors []*errors.ValidationError
+
+	// Warnings holds anything worth reporting about the matched operation that does not fail validation, such as
+	// the operation being deprecated.
+	Warnings []*errors.ValidationWarning
+}
+
+// MatchPath will find the path in the document that matches the request path, and return everything that was
+// learned along the way as a PathMatchResult. If no path matches, then PathItem is nil and Errors explains why.
+//
+// Options can be supplied to change how the request path is matched, for example config.WithDuplicateSlashNormalization.
+func MatchPath(request *http.Request, document *v3.Document, opts ...config.Option) *PathMatchResult {
+	return MatchPathInOrder(request, document, PathTemplates(document), opts...)
+}
 
-	reqPathSegments := strings.Split(stripped, "/")
-	if reqPathSegments[0] == "" {
-		reqPathSegments = reqPathSegments[1:]
+// PathTemplates returns the path templates of a document, in the order they are defined. The slice can be reordered
+// (for example, most specific first) and passed to MatchPathInOrder, to control which path wins when more than one
+// path matches a request.
+func PathTemplates(document *v3.Document) []string {
+	if document == nil || document.Paths == nil {
+		return nil
 	}
+	templates := make([]string, 0, orderedmap.Len(document.Paths.PathItems))
+	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
+		templates = append(templates, pair.Key())
+	}
+	return templates
+}
+
+// MatchPathInOrder is the same as MatchPath, except the path templates of the document are compared in the order
+// they are supplied, and the first template that matches wins. Templates that are not defined by the document are
+// ignored, as are templates of the document that are not supplied. The templates can be derived once using
+// PathTemplates, and reused for every request.
+//
+// The base paths stripped from the request path come from the most specific servers that apply to each operation:
+// the servers of the operation, then the servers of the path item, and then the servers of the document.
+func MatchPathInOrder(request *http.Request, document *v3.Document, templates []string,
+	opts ...config.Option) *PathMatchResult {
+	options := config.NewValidationOptions(opts...)
+	result, _ := matchPathInOrder(request, document, templates, options,
+		options.OperationCacheFor(document))
+	return result
+}
+
+// matchPathInOrder is MatchPathInOrder, it also returns the number of paths that were compared against the request
+// path. Paths that do not define the request method are not compared. The operations that are not part of the model
+// of the document are built by (and kept in) the operation cache.
+func matchPathInOrder(request *http.Request, document *v3.Document, templates []string,
+	options *config.ValidationOptions, operations *helpers.OperationCache) (*PathMatchResult, int) {
+	var basePaths []string
+	var serverIndexes []int
+	if !options.DisableServerStripping {
+		basePaths, serverIndexes = getServerBasePaths(document)
+	}
+	req := newPreparedPath(request, options, basePaths)
 
 	var pItem *v3.PathItem
 	var foundPath string
-pathFound:
+	candidates := 0
+	for _, template := range templates {
+		var pathItem *v3.PathItem
+		if document.Paths != nil {
+			pathItem = document.Paths.PathItems.GetOrZero(template)
+		}
+		if pathItem == nil {
+			continue
+		}
+
+		// skip any path that does not define the request method, before doing any comparison work.
+		if !hasOperation(operations, pathItem, request) {
+			continue
+		}
+
+		candidates++
+		if match := matchPathItem(request, template, pathItem, req, basePaths, serverIndexes, options,
+			operations); match != nil {
+			pItem = pathItem
+			foundPath = match.path
+			req, basePaths, serverIndexes = match.req, match.basePaths, match.serverIndexes
+			break
+		}
+	}
+	result := newPathMatchResult(request, pItem, foundPath, req, basePaths, serverIndexes, operations)
+	if pItem == nil {
+		attachDefinedMethods(result, request, definedMethods(request, document, options, basePaths, operations))
+	}
+	if pItem == nil && options.PathSuggestions > 0 {
+		attachPathSuggestions(result, suggestPaths(document, req.segments, options.PathSuggestions))
+	}
+	errors.FormatValidationErrors(result.Errors, options.MessageFormatterFor)
+	return result, candidates
+}
+
+// itemMatch is how a request path matched a path item: the path (without its fragment, unless the request path has
+// one), and the request path prepared with the base paths of the servers that apply to the operation.
+type itemMatch struct {
+	path          string
+	req           preparedPath
+	basePaths     []string
+	serverIndexes []int
+}
+
+// matchPathItem compares a request path against the path template of a path item. The request path is prepared with
+// the base paths of the document servers, unless the servers of the path item (or of the operation for the request
+// method) override them. If the path does not match, nil is returned.
+func matchPathItem(request *http.Request, template string, pathItem *v3.PathItem, req preparedPath,
+	basePaths []string, serverIndexes []int, options *config.ValidationOptions,
+	operations *helpers.OperationCache) *itemMatch {
+	// operation and path item servers override the document servers, and so do their base paths.
+	match := &itemMatch{req: req, basePaths: basePaths, serverIndexes: serverIndexes}
+	if servers := overridingServers(operations, request, pathItem); servers != nil &&
+		!options.DisableServerStripping {
+		match.basePaths, match.serverIndexes = serverBasePaths(servers)
+		match.req = newPreparedPath(request, options, match.basePaths)
+	}
+
+	// if the stripped path has a fragment, then use that as part of the lookup
+	// if not, then strip off any fragments from the pathItem
+	match.path = template
+	if !match.req.hasFragment {
+		match.path, _, _ = strings.Cut(match.path, "#")
+	}
+
+	if matchesTemplate(match.path, match.req, match.basePaths) ||
+		matchesReservedTemplate(match.path, match.req, request, pathItem, operations) {
+		return match
+	}
+	return nil
+}
+
+// definedMethods returns the methods defined by the first path of a document that matches the request path,
+// whatever the request method is. It is used when no path defines the request method, to explain that the path
+// exists for other methods. If no path matches, nil is returned.
+func definedMethods(request *http.Request, document *v3.Document, options *config.ValidationOptions,
+	basePaths []string, operations *helpers.OperationCache) []string {
+	if document.Paths == nil {
+		return nil
+	}
+	req := newPreparedPath(request, options, basePaths)
 	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
-		path := pair.Key()
 		pathItem := pair.Value()
-
-		// if the stripped path has a fragment, then use that as part of the lookup
-		// if not, then strip off any fragments from the pathItem
-		if !strings.Contains(stripped, "#") {
-			if strings.Contains(path, "#") {
-				path = strings.Split(path, "#")[0]
-			}
+		if pathItem == nil {
+			continue
+		}
+		itemReq, itemBasePaths := req, basePaths
+		if servers := overridingServers(operations, request, pathItem); servers != nil &&
+			!options.DisableServerStripping {
+			itemBasePaths, _ = serverBasePaths(servers)
+			itemReq = newPreparedPath(request, options, itemBasePaths)
+		}
+		path := pair.Key()
+		if !itemReq.hasFragment {
+			path, _, _ = strings.Cut(path, "#")
 		}
+		if !matchesTemplate(path, itemReq, itemBasePaths) {
+			continue
+		}
+		var methods []string
+		for op := orderedmap.First(pathItem.GetOperations()); op != nil; op = op.Next() {
+			methods = append(methods, strings.ToUpper(op.Key()))
+		}
+		if len(methods) > 0 {
+			return methods
+		}
+	}
+	return nil
+}
+
+// attachDefinedMethods will explain in the error of a path that was not found, that the path does exist, however
+// it only defines other methods.
+func attachDefinedMethods(result *PathMatchResult, request *http.Request, methods []string) {
+	if len(methods) == 0 || len(result.Errors) == 0 {
+		return
+	}
+	defined := "is"
+	if len(methods) > 1 {
+		defined = "are"
+	}
+	result.Errors[0].Message = fmt.Sprintf("%s Path '%s' not found, but %s %s defined", request.Method,
+		request.URL.Path, strings.Join(methods, ", "), defined)
+	result.Errors[0].Reason = fmt.Sprintf("The %s request contains a path of '%s', that path exists in the "+
+		"specification, however it does not define the %s method, only %s", request.Method, request.URL.Path,
+		request.Method, strings.Join(methods, ", "))
+	result.Errors[0].HowToFix = fmt.Sprintf(errors.HowToFixPathMethodDefined, strings.Join(methods, ", "))
+}
+
+// preparedPath is a request path, prepared for comparison against the paths of a document.
+type preparedPath struct {
+	path     string
+	stripped string
+
+	// segments are the segments of the stripped path. There are no segments for a request to the root path '/'
+	// (or the root of a server base path), which only matches a path of '/' in the document.
+	segments    []string
+	simple      bool
+	hasFragment bool
 
-		segs := strings.Split(path, "/")
-		if segs[0] == "" {
-			segs = segs[1:]
+	// foldCompoundLiterals matches the literal text of compound segments case-insensitively.
+	foldCompoundLiterals bool
+
+	// foldCase matches literal segments case-insensitively.
+	foldCase bool
+
+	// ignoreTrailingSlash removes the trailing slash of path templates before they are compared, the trailing
+	// slash of the request path has already been removed.
+	ignoreTrailingSlash bool
+
+	// allowReserved matches path parameters that allow reserved characters greedily, across segments.
+	allowReserved bool
+}
+
+// newPreparedPath prepares the path of a request for comparison, by normalizing it (if configured), stripping any
+// base paths and splitting it into segments.
+func newPreparedPath(request *http.Request, options *config.ValidationOptions, basePaths []string) preparedPath {
+	req := preparePath(normalizeRequestPath(requestPath(request), options), request.URL.Fragment, basePaths)
+	req.foldCompoundLiterals = options.CaseInsensitiveCompoundLiterals
+	req.foldCase = options.CaseInsensitivePaths
+	req.ignoreTrailingSlash = options.IgnoreTrailingSlash
+	req.allowReserved = options.AllowReservedPathParameters
+	return req
+}
+
+// requestPath returns the decoded path of a request, except that encoded slashes ('%2F') are left encoded. An encoded
+// slash is part of the segment it was sent in, so it must not split that segment in two when the path is matched, and
+// path parameter values that contain one can be rejected (unless they allow reserved characters). Any '%' of such a
+// path is left encoded too (as '%25'), so an encoded slash cannot be confused with an encoded '%2F' (e.g. '%252F').
+func requestPath(request *http.Request) string {
+	if !helpers.HasEncodedSlashes(request) {
+		return request.URL.Path
+	}
+	segments := strings.Split(request.URL.EscapedPath(), helpers.Slash)
+	for i, segment := range segments {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			unescaped = strings.ReplaceAll(unescaped, "%", helpers.EncodedPercent)
+			segments[i] = strings.ReplaceAll(unescaped, helpers.Slash, helpers.EncodedSlash)
 		}
+	}
+	return strings.Join(segments, helpers.Slash)
+}
 
-		// collect path level params
-		var errs []*errors.ValidationError
-		var ok bool
-		switch request.Method {
-		case http.MethodGet:
-			if pathItem.Get != nil {
-				if checkPathAgainstBase(request.URL.Path, path, basePaths) {
-					pItem = pathItem
-					foundPath = path
-					break pathFound
-				}
-				if ok = comparePaths(segs, reqPathSegments, basePaths); ok {
-					pItem = pathItem
-					foundPath = path
-					validationErrors = errs
-					break pathFound
-				}
-			}
-		case http.MethodPost:
-			if pathItem.Post != nil {
-				if checkPathAgainstBase(request.URL.Path, path, basePaths) {
-					pItem = pathItem
-					foundPath = path
-					break pathFound
-				}
-				if ok = comparePaths(segs, reqPathSegments, basePaths); ok {
-					pItem = pathItem
-					foundPath = path
-					validationErrors = errs
-					break pathFound
-				}
-			}
-		case http.MethodPut:
-			if pathItem.Put != nil {
-				// check for a literal match
-				if checkPathAgainstBase(request.URL.Path, path, basePaths) {
-					pItem = pathItem
-					foundPath = path
-					validationErrors = errs
-					break pathFound
-				}
-				if ok = comparePaths(segs, reqPathSegments, basePaths); ok {
-					pItem = pathItem
-					foundPath = path
-					validationErrors = errs
-					break pathFound
-				}
-			}
-		case http.MethodDelete:
-			if pathItem.Delete != nil {
-				// check for a literal match
-				if checkPathAgainstBase(request.URL.Path, path, basePaths) {
-					pItem = pathItem
-					foundPath = path
-					break pathFound
-				}
-				if ok = comparePaths(segs, reqPathSegments, basePaths); ok {
-					pItem = pathItem
-					foundPath = path
-					validationErrors = errs
-					break pathFound
-				}
-			}
-		case http.MethodOptions:
-			if pathItem.Options != nil {
-				// check for a literal match
-				if checkPathAgainstBase(request.URL.Path, path, basePaths) {
-					pItem = pathItem
-					foundPath = path
-					break pathFound
-				}
-				if ok = comparePaths(segs, reqPathSegments, basePaths); ok {
-					pItem = pathItem
-					foundPath = path
-					validationErrors = errs
-					break pathFound
-				}
-			}
-		case http.MethodHead:
-			if pathItem.Head != nil {
-				if checkPathAgainstBase(request.URL.Path, path, basePaths) {
-					pItem = pathItem
-					foundPath = path
-					break pathFound
-				}
-				if ok = comparePaths(segs, reqPathSegments, basePaths); ok {
-					pItem = pathItem
-					foundPath = path
-					validationErrors = errs
-					break pathFound
-				}
-			}
-		case http.MethodPatch:
-			if pathItem.Patch != nil {
-				// check for a literal match
-				if checkPathAgainstBase(request.URL.Path, path, basePaths) {
-					pItem = pathItem
-					foundPath = path
-					break pathFound
-				}
-				if ok = comparePaths(segs, reqPathSegments, basePaths); ok {
-					pItem = pathItem
-					foundPath = path
-					validationErrors = errs
-					break pathFound
-				}
-			}
-		case http.MethodTrace:
-			if pathItem.Trace != nil {
-				if checkPathAgainstBase(request.URL.Path, path, basePaths) {
-					pItem = pathItem
-					foundPath = path
-					break pathFound
-				}
-				if ok = comparePaths(segs, reqPathSegments, basePaths); ok {
-					pItem = pathItem
-					foundPath = path
-					validationErrors = errs
-					break pathFound
-				}
-			}
+// normalizeRequestPath will add the request path prefix, collapse duplicate slashes, and remove any trailing slash of
+// a request path, if configured to do so.
+func normalizeRequestPath(path string, options *config.ValidationOptions) string {
+	if prefix := strings.TrimSuffix(options.RequestPathPrefix, helpers.Slash); prefix != "" {
+		if path == helpers.Slash {
+			path = ""
 		}
+		path = withLeadingSlash(prefix) + withLeadingSlash(path)
+	}
+	if options.NormalizeDuplicateSlashes {
+		path = normalizeDuplicateSlashes(path)
+	}
+	if options.IgnoreTrailingSlash {
+		path = trimTrailingSlash(path)
+	}
+	return path
+}
+
+// trimTrailingSlash removes the trailing slash of a path, unless the path is the root.
+func trimTrailingSlash(path string) string {
+	if len(path) > 1 {
+		return strings.TrimSuffix(path, helpers.Slash)
+	}
+	return path
+}
+
+// withLeadingSlash adds a leading slash to a path template that does not have one (e.g. 'users/{id}'), so it lines
+// up with the request path when both are split into segments.
+func withLeadingSlash(template string) string {
+	if template != "" && !strings.HasPrefix(template, helpers.Slash) {
+		return helpers.Slash + template
+	}
+	return template
+}
+
+// templatePath returns a path template as it is compared against the request path.
+func (req preparedPath) templatePath(template string) string {
+	template = withLeadingSlash(template)
+	if req.ignoreTrailingSlash {
+		return trimTrailingSlash(template)
+	}
+	return template
+}
+
+// preparePath prepares a path (and fragment) for comparison, by stripping any base paths and splitting it into segments.
+func preparePath(path, fragment string, basePaths []string) preparedPath {
+	stripped := stripRequestPath(path, fragment, basePaths)
+	if stripped == "" {
+		stripped = helpers.Slash // an empty path, or a server base path with nothing after it, is the root.
+	}
+
+	segments := strings.Split(stripped, "/")
+	if segments[0] == "" {
+		segments = segments[1:]
+	}
+	if len(segments) == 1 && segments[0] == "" {
+		segments = nil
 	}
-	if pItem == nil && len(validationErrors) == 0 {
-		validationErrors = append(validationErrors, &errors.ValidationError{
+	return preparedPath{
+		path:        path,
+		stripped:    stripped,
+		segments:    segments,
+		simple:      !slices.ContainsFunc(segments, isDotOrEmptySegment),
+		hasFragment: strings.Contains(stripped, "#"),
+	}
+}
+
+// newPathMatchResult builds the result of matching a request path against a path item. If no path item was found,
+// then the result holds a 'not found' validation error.
+func newPathMatchResult(request *http.Request, pItem *v3.PathItem, foundPath string, req preparedPath,
+	basePaths []string, serverIndexes []int, operations *helpers.OperationCache) *PathMatchResult {
+
+	result := &PathMatchResult{
+		PathItem:     pItem,
+		FoundPath:    foundPath,
+		ServerIndex:  -1,
+		strippedPath: req.stripped,
+	}
+	if pItem == nil {
+		result.Errors = []*errors.ValidationError{{
 			ValidationType:    helpers.ParameterValidationPath,
 			ValidationSubType: "missing",
 			Message:           fmt.Sprintf("%s Path '%s' not found", request.Method, request.URL.Path),
@@ -186,52 +400,289 @@ pathFound:
 			SpecLine: -1,
 			SpecCol:  -1,
 			HowToFix: errors.HowToFixPath,
-		})
+		}}
+		errors.PopulateValidationErrors(result.Errors, request, foundPath)
+		errors.SetValidationDirection(result.Errors, helpers.RequestDirection)
+		return result
+	}
 
-		errors.PopulateValidationErrors(validationErrors, request, foundPath)
-		return pItem, validationErrors, foundPath
-	} else {
-		errors.PopulateValidationErrors(validationErrors, request, foundPath)
-		return pItem, validationErrors, foundPath
+	result.Literal = !strings.Contains(foundPath, "{")
+	result.Operation = operations.ExtractOperation(request, pItem)
+	if result.Operation != nil && result.Operation.Deprecated != nil && *result.Operation.Deprecated {
+		result.Warnings = append(result.Warnings, errors.OperationDeprecated(request, result.Operation, foundPath))
+	}
+	result.Params, _ = extractPathParamValues(foundPath, req.stripped, req.foldCompoundLiterals)
+	if req.allowReserved {
+		names := helpers.ReservedPathParamNames(operations.ExtractParamsForOperation(request, pItem))
+		if segments, ok := helpers.MatchReservedPathSegments(req.templatePath(foundPath), req.stripped, names); ok &&
+			len(names) > 0 {
+			result.Params, _ = extractPathParamSegmentValues(foundPath, segments, req.foldCompoundLiterals)
+		}
 	}
+	for i, basePath := range basePaths {
+		if strings.HasPrefix(req.path, basePath) {
+			result.ServerIndex = serverIndexes[i]
+			break
+		}
+	}
+	return result
+}
+
+// PathTemplateMatches will determine if a single path template (e.g. /burgers/{burgerId}) matches a request path
+// (e.g. /burgers/123), and if so, return the raw value of each template parameter. The same matching rules as
+// FindPath are used, without any document, server or operation involvement.
+func PathTemplateMatches(template string, requestPath string) (bool, map[string]string) {
+	path, fragment, _ := strings.Cut(requestPath, "#")
+	req := preparePath(path, fragment, nil)
+	if !req.hasFragment {
+		template, _, _ = strings.Cut(template, "#")
+	}
+	if !matchesTemplate(template, req, nil) {
+		return false, nil
+	}
+	values, _ := extractPathParamValues(template, req.stripped, false)
+	return true, values
+}
+
+// matchesTemplate is the core of path matching, it checks for a literal match of a path template against a request
+// path (with or without a base path), and then compares each segment against the template.
+func matchesTemplate(template string, req preparedPath, basePaths []string) bool {
+	// the root can only be matched by the root.
+	if len(req.segments) == 0 {
+		return template == helpers.Slash
+	}
+	template = req.templatePath(template)
+	return checkPathAgainstBase(req.path, template, basePaths, req.foldCase) ||
+		comparePathSegments(template, req, basePaths)
+}
+
+// matchesReservedTemplate checks if a path template matches a request path when the path parameters of the operation
+// that allow reserved characters are matched across segments. It only applies if reserved path parameters are allowed.
+func matchesReservedTemplate(template string, req preparedPath, request *http.Request, pathItem *v3.PathItem,
+	operations *helpers.OperationCache) bool {
+	if !req.allowReserved || len(req.segments) == 0 {
+		return false
+	}
+	names := helpers.ReservedPathParamNames(operations.ExtractParamsForOperation(request, pathItem))
+	if len(names) == 0 {
+		return false
+	}
+	_, ok := helpers.MatchReservedPathSegments(req.templatePath(template), req.stripped, names)
+	return ok
+}
+
+// PathParameterMatch is the result of matching the path parameters of a request against the path template
+// found in the document. It can be used to report inconsistencies between a template and its parameter definitions.
+type PathParameterMatch struct {
+	// PathItem is the path item that was matched.
+	PathItem *v3.PathItem
+
+	// Path is the path template that was matched, as it pertains to the contract.
+	Path string
+
+	// Values holds the raw value supplied by the request for each template parameter.
+	Values map[string]string
+
+	// Unfilled holds the names of path parameters declared by the operation that were not supplied a value,
+	// either because they are missing from the template, or because the request left them empty.
+	Unfilled []string
+
+	// Undeclared holds the names of template parameters that were supplied a value by the request, but
+	// have no matching path parameter declared by the operation.
+	Undeclared []string
+}
+
+// FindPathParameters will find the path in the document that matches the request path (using FindPath), and then
+// extract the value of each parameter in the path template from the request. Declared path parameters that were
+// not filled, and filled template parameters that were not declared are also reported.
+func FindPathParameters(request *http.Request, document *v3.Document, opts ...config.Option) (*PathParameterMatch, []*errors.ValidationError) {
+	options := config.NewValidationOptions(opts...)
+	operations := options.OperationCacheFor(document)
+	result, _ := matchPathInOrder(request, document, PathTemplates(document), options, operations)
+	if result.PathItem == nil || result.Errors != nil {
+		return nil, result.Errors
+	}
+	pathItem := result.PathItem
+
+	_, templateParams := extractPathParamValues(result.FoundPath, result.strippedPath,
+		options.CaseInsensitiveCompoundLiterals)
+	match := &PathParameterMatch{
+		PathItem: pathItem,
+		Path:     result.FoundPath,
+		Values:   result.Params,
+	}
+
+	var declared []string
+	for _, p := range operations.ExtractParamsForOperation(request, pathItem) {
+		if p.In == helpers.Path && !slices.Contains(declared, p.Name) {
+			declared = append(declared, p.Name)
+			if _, ok := match.Values[p.Name]; !ok {
+				match.Unfilled = append(match.Unfilled, p.Name)
+			}
+		}
+	}
+	for _, name := range templateParams {
+		if _, ok := match.Values[name]; ok && !slices.Contains(declared, name) {
+			match.Undeclared = append(match.Undeclared, name)
+		}
+	}
+	return match, nil
+}
+
+// extractPathParamValues will extract the value of each parameter in a path template from a (stripped) request
+// path. The names of all the template parameters are also returned, in the order they appear. If foldCase is true,
+// the literal text of compound segments is matched case-insensitively.
+func extractPathParamValues(foundPath, requestPath string, foldCase bool) (map[string]string, []string) {
+	return extractPathParamSegmentValues(foundPath, strings.Split(requestPath, helpers.Slash), foldCase)
+}
+
+// extractPathParamSegmentValues is the same as extractPathParamValues, except the request path has already been
+// split into one value for each segment of the path template.
+func extractPathParamSegmentValues(foundPath string, submittedSegments []string,
+	foldCase bool) (map[string]string, []string) {
+	values := make(map[string]string)
+	var templateParams []string
+	for x, segment := range strings.Split(withLeadingSlash(foundPath), helpers.Slash) {
+		if !strings.Contains(segment, "{") || !helpers.IsValidPathSegmentTemplate(segment) {
+			continue
+		}
+		names := helpers.ExtractPathSegmentParamNames(segment)
+		templateParams = append(templateParams, names...)
+		if x >= len(submittedSegments) || submittedSegments[x] == "" {
+			continue
+		}
+		if helpers.IsCompoundPathSegment(segment) {
+			match := helpers.MatchCompoundPathSegment
+			if foldCase {
+				match = helpers.MatchCompoundPathSegmentFold
+			}
+			for name, value := range match(segment, submittedSegments[x]) {
+				if value != "" {
+					values[name] = value
+				}
+			}
+			continue
+		}
+		for _, name := range names {
+			values[name] = submittedSegments[x]
+		}
+	}
+	return values, templateParams
 }
 
 func getBasePaths(document *v3.Document) []string {
-	// extract base path from document to check against paths.
+	basePaths, _ := getServerBasePaths(document)
+	return basePaths
+}
+
+// getServerBasePaths extracts the base path of every server (and server variable expansion) in the document,
+// along with the index of the server that each base path belongs to.
+func getServerBasePaths(document *v3.Document) ([]string, []int) {
+	return serverBasePaths(document.Servers)
+}
+
+// overridingServers returns the servers that override the document servers for the operation of a request: the
+// servers of the operation, or if it has none, the servers of the path item. If neither has servers, nil is returned.
+func overridingServers(operations *helpers.OperationCache, request *http.Request, pathItem *v3.PathItem) []*v3.Server {
+	if op := operations.ExtractOperation(request, pathItem); op != nil && len(op.Servers) > 0 {
+		return op.Servers
+	}
+	if len(pathItem.Servers) > 0 {
+		return pathItem.Servers
+	}
+	return nil
+}
+
+// serverBasePaths extracts the base path of every server (and server variable expansion), along with the index of
+// the server that each base path belongs to.
+func serverBasePaths(servers []*v3.Server) ([]string, []int) {
+	// extract base path from servers to check against paths.
 	var basePaths []string
-	for _, s := range document.Servers {
-		var u *url.URL = nil
-		u, err := url.Parse(s.URL)
+	var serverIndexes []int
+	for i, s := range servers {
+		for _, serverURL := range expandServerVariables(s) {
+			var u *url.URL = nil
+			u, err := url.Parse(serverURL)
 
-		// if the host contains special characters, we should attempt to split and parse only the relative path
-		if err != nil {
-			// split at first occurrence
-			_, serverPath, _ := strings.Cut(strings.Replace(s.URL, "//", "", 1), "/")
+			// if the host contains special characters, we should attempt to split and parse only the relative path
+			if err != nil {
+				// split at first occurrence
+				_, serverPath, _ := strings.Cut(strings.Replace(serverURL, "//", "", 1), "/")
+
+				if !strings.HasPrefix(serverPath, "/") {
+					serverPath = "/" + serverPath
+				}
 
-			if !strings.HasPrefix(serverPath, "/") {
-				serverPath = "/" + serverPath
+				u, _ = url.Parse(serverPath)
 			}
 
-			u, _ = url.Parse(serverPath)
+			if u != nil && u.Path != "" {
+				basePaths = append(basePaths, u.Path)
+				serverIndexes = append(serverIndexes, i)
+			}
 		}
+	}
+
+	return basePaths, serverIndexes
+}
 
-		if u != nil && u.Path != "" {
-			basePaths = append(basePaths, u.Path)
+// expandServerVariables will expand any templated variables in a server URL (e.g. https://{host}/api/{version})
+// into every combination of the default and enum values defined for those variables. Variables that are not
+// defined by the server, or have no values, are left in place.
+func expandServerVariables(server *v3.Server) []string {
+	expanded := []string{server.URL}
+	if server.Variables == nil || orderedmap.Len(server.Variables) == 0 {
+		return expanded
+	}
+	for pair := orderedmap.First(server.Variables); pair != nil; pair = pair.Next() {
+		variable := pair.Value()
+		if variable == nil {
+			continue
+		}
+		token := fmt.Sprintf("{%s}", pair.Key())
+		var values []string
+		if variable.Default != "" {
+			values = append(values, variable.Default)
+		}
+		for _, e := range variable.Enum {
+			if !slices.Contains(values, e) {
+				values = append(values, e)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		var next []string
+		for _, u := range expanded {
+			if !strings.Contains(u, token) {
+				next = append(next, u)
+				continue
+			}
+			for _, val := range values {
+				next = append(next, strings.ReplaceAll(u, token, val))
+			}
 		}
+		expanded = next
 	}
-
-	return basePaths
+	return expanded
 }
 
 // StripRequestPath strips the base path from the request path, based on the server paths provided in the specification
-func StripRequestPath(request *http.Request, document *v3.Document) string {
+func StripRequestPath(request *http.Request, document *v3.Document, opts ...config.Option) string {
 
-	basePaths := getBasePaths(document)
+	options := config.NewValidationOptions(opts...)
+	var basePaths []string
+	if !options.DisableServerStripping {
+		basePaths = getBasePaths(document)
+	}
+	return stripRequestPath(normalizeRequestPath(requestPath(request), options), request.URL.Fragment, basePaths)
+}
 
-	// strip any base path
-	stripped := stripBaseFromPath(request.URL.Path, basePaths)
-	if request.URL.Fragment != "" {
-		stripped = fmt.Sprintf("%s#%s", stripped, request.URL.Fragment)
+// stripRequestPath strips any base path from a request path, and appends the fragment (if there is one).
+func stripRequestPath(requestPath, fragment string, basePaths []string) string {
+	stripped := stripBaseFromPath(requestPath, basePaths)
+	if fragment != "" {
+		stripped = fmt.Sprintf("%s#%s", stripped, fragment)
 	}
 	if len(stripped) > 0 && !strings.HasPrefix(stripped, "/") {
 		stripped = "/" + stripped
@@ -239,17 +690,25 @@ func StripRequestPath(request *http.Request, document *v3.Document) string {
 	return stripped
 }
 
-func checkPathAgainstBase(docPath, urlPath string, basePaths []string) bool {
-	if docPath == urlPath {
+// normalizeDuplicateSlashes will collapse any repeated slashes in a path into a single slash.
+func normalizeDuplicateSlashes(path string) string {
+	return duplicateSlashRegex.ReplaceAllString(path, helpers.Slash)
+}
+
+// checkPathAgainstBase checks for a literal match of a path, with or without a base path. If foldCase is true, the
+// path (but not the base path) is compared case-insensitively.
+func checkPathAgainstBase(docPath, urlPath string, basePaths []string, foldCase bool) bool {
+	if docPath == urlPath || (foldCase && strings.EqualFold(docPath, urlPath)) {
 		return true
 	}
 	for _, basePath := range basePaths {
-		if basePath[len(basePath)-1] == '/' {
-			basePath = basePath[:len(basePath)-1]
-		}
-		merged := fmt.Sprintf("%s%s", basePath, urlPath)
-		if docPath == merged {
-			return true
+		basePath = strings.TrimSuffix(basePath, "/")
+
+		// equivalent to docPath == basePath + urlPath, without building the merged string.
+		if len(docPath) == len(basePath)+len(urlPath) && strings.HasPrefix(docPath, basePath) {
+			if suffix := docPath[len(basePath):]; suffix == urlPath || (foldCase && strings.EqualFold(suffix, urlPath)) {
+				return true
+			}
 		}
 	}
 	return false
@@ -264,19 +723,110 @@ func stripBaseFromPath(path string, basePaths []string) string {
 	return path
 }
 
-func comparePaths(mapped, requested, basePaths []string) bool {
+// hasOperation returns true if the path item defines an operation for the method of a request, either as a standard
+// method field, as a CONNECT operation, or within the method extension (if there is one, see
+// config.WithMethodExtension).
+func hasOperation(operations *helpers.OperationCache, pathItem *v3.PathItem, request *http.Request) bool {
+	return operations.ExtractOperation(request, pathItem) != nil
+}
+
+// isDotOrEmptySegment returns true for segments that are changed when a path is cleaned.
+func isDotOrEmptySegment(segment string) bool {
+	return segment == "" || segment == "." || segment == ".."
+}
+
+// comparePathSegments compares a path template against the segments of a request path, one segment at a time and
+// without allocating (unless a segment is compound). If either side contains empty or dot segments, the comparison
+// falls back to comparePaths, which cleans both paths before comparing them. Literal segments are compared
+// case-insensitively if the request path is prepared to fold case.
+func comparePathSegments(path string, req preparedPath, basePaths []string) bool {
+	requested := req.segments
+	path = strings.TrimPrefix(path, "/")
+	if strings.Count(path, "/")+1 != len(requested) {
+		return false // short circuit out
+	}
+	if !req.simple {
+		return comparePaths(strings.Split(path, "/"), req, basePaths)
+	}
+	remaining := path
+	for i := range requested {
+		seg, rest, _ := strings.Cut(remaining, "/")
+		remaining = rest
+		if isDotOrEmptySegment(seg) {
+			return comparePaths(strings.Split(path, "/"), req, basePaths)
+		}
+		if strings.ContainsAny(seg, "{}") {
+			if !matchesTemplateSegment(seg, requested[i], req.foldCompoundLiterals) {
+				return false
+			}
+			continue
+		}
+		if seg != requested[i] && !equalLiteralSegments(seg, requested[i], req.foldCase) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalUnescapedSegments compares two literal path segments after unescaping them both, so a segment that has been
+// escaped (e.g. 'a%20b') matches one that has not (e.g. 'a b'). Segments that cannot be unescaped are compared as-is.
+func equalUnescapedSegments(a, b string) bool {
+	if !strings.Contains(a, "%") && !strings.Contains(b, "%") {
+		return false
+	}
+	return unescapeSegment(a) == unescapeSegment(b)
+}
+
+// equalLiteralSegments compares two literal path segments that are not identical, in the same way as
+// equalUnescapedSegments. If foldCase is true, the segments are compared case-insensitively.
+func equalLiteralSegments(a, b string, foldCase bool) bool {
+	if !foldCase {
+		return equalUnescapedSegments(a, b)
+	}
+	return strings.EqualFold(a, b) || strings.EqualFold(unescapeSegment(a), unescapeSegment(b))
+}
+
+func unescapeSegment(segment string) string {
+	if unescaped, err := url.PathUnescape(segment); err == nil {
+		return unescaped
+	}
+	return segment
+}
+
+func comparePaths(mapped []string, req preparedPath, basePaths []string) bool {
+	requested := req.segments
 	if len(mapped) != len(requested) {
 		return false // short circuit out
 	}
 	var imploded []string
 	for i, seg := range mapped {
 		s := seg
-		if strings.Contains(seg, "{") {
+		if strings.ContainsAny(seg, "{}") {
+			if !matchesTemplateSegment(seg, requested[i], req.foldCompoundLiterals) {
+				return false
+			}
 			s = requested[i]
 		}
 		imploded = append(imploded, s)
 	}
 	l := filepath.Join(imploded...)
 	r := filepath.Join(requested...)
-	return checkPathAgainstBase(l, r, basePaths)
+	return checkPathAgainstBase(l, r, basePaths, req.foldCase)
+}
+
+// matchesTemplateSegment checks a template segment of a path against a segment of a request path. Malformed template
+// segments can never match a request. A segment with a single parameter matches any value, but a compound segment
+// (such as '{name}.json') only matches a value that has its literal text, so it does not claim requests meant for
+// another path. If foldCompound is true, the literal text of compound segments is matched case-insensitively.
+func matchesTemplateSegment(segment, value string, foldCompound bool) bool {
+	if !helpers.IsValidPathSegmentTemplate(segment) {
+		return false
+	}
+	if !helpers.IsCompoundPathSegment(segment) {
+		return true
+	}
+	if foldCompound {
+		return len(helpers.MatchCompoundPathSegmentFold(segment, value)) > 0
+	}
+	return len(helpers.MatchCompoundPathSegment(segment, value)) > 0
 }
@@ -4,11 +4,17 @@
 package paths
 
 import (
+	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -387,7 +393,7 @@ paths:
 	_, errs, _ := FindPath(request, &m.Model)
 
 	assert.Len(t, errs, 1)
-	assert.Equal(t, "POST Path '/pizza/1234' not found", errs[0].Message)
+	assert.Equal(t, "POST Path '/pizza/1234' not found, but PUT is defined", errs[0].Message)
 }
 
 func TestNewValidator_OptionsMatch_Error(t *testing.T) {
@@ -412,7 +418,7 @@ paths:
 	_, errs, _ := FindPath(request, &m.Model)
 
 	assert.Len(t, errs, 1)
-	assert.Equal(t, "POST Path '/pizza/1234' not found", errs[0].Message)
+	assert.Equal(t, "POST Path '/pizza/1234' not found, but OPTIONS is defined", errs[0].Message)
 }
 
 func TestNewValidator_PatchLiteralMatch(t *testing.T) {
@@ -455,7 +461,7 @@ paths:
 	_, errs, _ := FindPath(request, &m.Model)
 
 	assert.Len(t, errs, 1)
-	assert.Equal(t, "POST Path '/pizza/1234' not found", errs[0].Message)
+	assert.Equal(t, "POST Path '/pizza/1234' not found, but PATCH is defined", errs[0].Message)
 }
 
 func TestNewValidator_DeleteLiteralMatch(t *testing.T) {
@@ -552,7 +558,7 @@ paths:
 	_, errs, _ := FindPath(request, &m.Model)
 
 	assert.Len(t, errs, 1)
-	assert.Equal(t, "POST Path '/pizza/1234' not found", errs[0].Message)
+	assert.Equal(t, "POST Path '/pizza/1234' not found, but TRACE is defined", errs[0].Message)
 }
 
 func TestNewValidator_DeleteMatch_Error(t *testing.T) {
@@ -577,7 +583,7 @@ paths:
 	_, errs, _ := FindPath(request, &m.Model)
 
 	assert.Len(t, errs, 1)
-	assert.Equal(t, "POST Path '/pizza/1234' not found", errs[0].Message)
+	assert.Equal(t, "POST Path '/pizza/1234' not found, but DELETE is defined", errs[0].Message)
 }
 
 func TestNewValidator_PostMatch_Error(t *testing.T) {
@@ -602,7 +608,7 @@ paths:
 	_, errs, _ := FindPath(request, &m.Model)
 
 	assert.Len(t, errs, 1)
-	assert.Equal(t, "PUT Path '/pizza/1234' not found", errs[0].Message)
+	assert.Equal(t, "PUT Path '/pizza/1234' not found, but POST is defined", errs[0].Message)
 }
 
 func TestNewValidator_FindPathWithFragment(t *testing.T) {
@@ -697,3 +703,1402 @@ paths:
 	assert.Equal(t, expectedPaths, basePaths)
 
 }
+
+func TestNewValidator_FindPathWithServerVariables(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://{host}/api/{version}
+    variables:
+      host:
+        default: things.com
+      version:
+        default: v1
+        enum:
+          - v1
+          - v2
+          - v3
+paths:
+  /user/{userId}:
+    get:
+      operationId: getUser
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/v3/user/1234", nil)
+	pathItem, errs, _ := FindPath(request, &m.Model)
+	assert.Len(t, errs, 0)
+	assert.NotNil(t, pathItem)
+	assert.Equal(t, "getUser", pathItem.Get.OperationId)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/v1/user/1234", nil)
+	pathItem, errs, _ = FindPath(request, &m.Model)
+	assert.Len(t, errs, 0)
+	assert.NotNil(t, pathItem)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/v4/user/1234", nil)
+	pathItem, errs, _ = FindPath(request, &m.Model)
+	assert.Len(t, errs, 1)
+	assert.Nil(t, pathItem)
+}
+
+func TestGetBasePaths_ServerVariables(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: 'https://{host}/api/{version}'
+    variables:
+      host:
+        default: things.com
+      version:
+        default: v2
+        enum:
+          - v1
+          - v2
+  - url: 'https://things.com/{undefined}/path'
+paths:
+  /dishy:
+    get:
+      operationId: one
+`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _ := doc.BuildV3Model()
+
+	basePaths := getBasePaths(&m.Model)
+
+	expectedPaths := []string{
+		"/api/v2",
+		"/api/v1",
+		"/{undefined}/path",
+	}
+
+	assert.Equal(t, expectedPaths, basePaths)
+}
+
+func TestNewValidator_FindPathDuplicateSlashes(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /users/all:
+    get:
+      operationId: getUsers
+  /users/{userId}:
+    get:
+      operationId: getUser
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	// strict by default
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/users//42", nil)
+	pathItem, errs, _ := FindPath(request, &m.Model)
+	assert.Nil(t, pathItem)
+	assert.Len(t, errs, 1)
+
+	// opt in to normalization
+	pathItem, errs, foundPath := FindPath(request, &m.Model, config.WithDuplicateSlashNormalization())
+	assert.Len(t, errs, 0)
+	assert.NotNil(t, pathItem)
+	assert.Equal(t, "/users/{userId}", foundPath)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com//api///users/all", nil)
+	pathItem, errs, _ = FindPath(request, &m.Model, config.WithDuplicateSlashNormalization())
+	assert.Len(t, errs, 0)
+	assert.NotNil(t, pathItem)
+	assert.Equal(t, "getUsers", pathItem.Get.OperationId)
+	assert.Equal(t, "/users/all", StripRequestPath(request, &m.Model, config.WithDuplicateSlashNormalization()))
+}
+
+func TestFindPathParameters(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}/{size}-{sauce}:
+    parameters:
+      - name: burgerId
+        in: path
+        required: true
+      - name: size
+        in: path
+        required: true
+    get:
+      parameters:
+        - name: cheese
+          in: path
+        - name: pickles
+          in: query
+      operationId: locateBurger`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/123/large-ketchup", nil)
+
+	match, errs := FindPathParameters(request, &m.Model)
+
+	assert.Nil(t, errs)
+	assert.NotNil(t, match)
+	assert.Equal(t, "/burgers/{burgerId}/{size}-{sauce}", match.Path)
+	assert.Equal(t, map[string]string{"burgerId": "123", "size": "large", "sauce": "ketchup"}, match.Values)
+	assert.Equal(t, []string{"cheese"}, match.Unfilled)
+	assert.Equal(t, []string{"sauce"}, match.Undeclared)
+}
+
+func TestFindPathParameters_NotFound(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      operationId: locateBurger`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/pizza/123/slices", nil)
+
+	match, errs := FindPathParameters(request, &m.Model)
+
+	assert.Nil(t, match)
+	assert.Len(t, errs, 1)
+}
+
+func TestMatchPath(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/v1
+  - url: https://things.com/v2
+paths:
+  /burgers/{burgerId}/{size}-{sauce}:
+    get:
+      operationId: locateBurger
+    post:
+      operationId: createBurger`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/v2/burgers/123/large-ketchup", nil)
+
+	result := MatchPath(request, &m.Model)
+
+	assert.Nil(t, result.Errors)
+	assert.NotNil(t, result.PathItem)
+	assert.Equal(t, "/burgers/{burgerId}/{size}-{sauce}", result.FoundPath)
+	assert.Equal(t, "createBurger", result.Operation.OperationId)
+	assert.Equal(t, map[string]string{"burgerId": "123", "size": "large", "sauce": "ketchup"}, result.Params)
+	assert.Equal(t, 1, result.ServerIndex)
+}
+
+func TestMatchPath_NoServer(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      operationId: locateBurger`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/123", nil)
+
+	result := MatchPath(request, &m.Model)
+
+	assert.Nil(t, result.Errors)
+	assert.Equal(t, "locateBurger", result.Operation.OperationId)
+	assert.Equal(t, map[string]string{"burgerId": "123"}, result.Params)
+	assert.Equal(t, -1, result.ServerIndex)
+}
+
+func TestMatchPath_NotFound(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      operationId: locateBurger`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/pizza/123", nil)
+
+	result := MatchPath(request, &m.Model)
+
+	assert.Nil(t, result.PathItem)
+	assert.Nil(t, result.Operation)
+	assert.Nil(t, result.Params)
+	assert.Equal(t, -1, result.ServerIndex)
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, "GET Path '/pizza/123' not found", result.Errors[0].Message)
+}
+
+func TestNewValidator_FindPathMalformedTemplates(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{:
+    get:
+      operationId: openBrace
+  /burgers/}:
+    get:
+      operationId: closeBrace
+  /burgers/{}:
+    get:
+      operationId: emptyBraces
+  /burgers/{a{b}}:
+    get:
+      operationId: nestedBraces
+  /burgers/{burgerId}/{:
+    get:
+      parameters:
+        - name: burgerId
+          in: path
+          schema:
+            type: string
+      operationId: trailingBrace
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	for _, p := range []string{"/burgers/x", "/burgers/", "/burgers/123/x"} {
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com"+p, nil)
+		assert.NotPanics(t, func() {
+			pathItem, errs, _ := FindPath(request, &m.Model)
+			assert.Nil(t, pathItem, p)
+			assert.Len(t, errs, 1, p)
+		})
+	}
+}
+
+func buildBenchmarkDocument(b *testing.B, pathCount int) *v3.Document {
+	var sb strings.Builder
+	sb.WriteString("openapi: 3.1.0\nservers:\n  - url: https://api.pb33f.io/v1\npaths:\n")
+	for i := 0; i < pathCount; i++ {
+		sb.WriteString(fmt.Sprintf("  /resources%d/{resourceId}/items/{itemId}:\n    get:\n      operationId: getItem%d\n", i, i))
+		sb.WriteString(fmt.Sprintf("  /resources%d/list:\n    post:\n      operationId: listResources%d\n", i, i))
+	}
+	doc, err := libopenapi.NewDocument([]byte(sb.String()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	m, errs := doc.BuildV3Model()
+	if len(errs) > 0 {
+		b.Fatal(errs)
+	}
+	return &m.Model
+}
+
+func BenchmarkFindPath_Templated(b *testing.B) {
+	document := buildBenchmarkDocument(b, 250)
+	request, _ := http.NewRequest(http.MethodGet, "https://api.pb33f.io/v1/resources249/1234/items/5678", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pathItem, _, _ := FindPath(request, document)
+		if pathItem == nil {
+			b.Fatal("path not found")
+		}
+	}
+}
+
+func BenchmarkFindPath_Literal(b *testing.B) {
+	document := buildBenchmarkDocument(b, 250)
+	request, _ := http.NewRequest(http.MethodPost, "https://api.pb33f.io/v1/resources249/list", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pathItem, _, _ := FindPath(request, document)
+		if pathItem == nil {
+			b.Fatal("path not found")
+		}
+	}
+}
+
+func BenchmarkFindPath_NotFound(b *testing.B) {
+	document := buildBenchmarkDocument(b, 250)
+	request, _ := http.NewRequest(http.MethodGet, "https://api.pb33f.io/v1/missing/1234/items/5678", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = FindPath(request, document)
+	}
+}
+
+func TestNewValidator_FindPathEscapedLiterals(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /files/a b/{fileId}:
+    get:
+      operationId: getSpacedFile
+  /files/caf%C3%A9/{fileId}:
+    get:
+      operationId: getEscapedFile
+  /files/100%25/{fileId}:
+    get:
+      operationId: getPercentFile
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	for requestPath, expected := range map[string]string{
+		"/files/a%20b/123":     "/files/a b/{fileId}",
+		"/files/a b/123":       "/files/a b/{fileId}",
+		"/files/caf%C3%A9/123": "/files/caf%C3%A9/{fileId}",
+		"/files/café/123":      "/files/caf%C3%A9/{fileId}",
+		"/files/100%25/123":    "/files/100%25/{fileId}",
+	} {
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com"+requestPath, nil)
+		pathItem, errs, foundPath := FindPath(request, &m.Model)
+		assert.NotNil(t, pathItem, requestPath)
+		assert.Nil(t, errs, requestPath)
+		assert.Equal(t, expected, foundPath, requestPath)
+	}
+}
+
+func TestPathMatcher_Candidates(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      operationId: listBurgers
+  /burgers/{burgerId}:
+    get:
+      operationId: getBurger
+  /burgers/{burgerId}/locate:
+    get:
+      operationId: locateBurger
+  /fries/{friesId}#large:
+    get:
+      operationId: getLargeFries
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	matcher := NewPathMatcher(&m.Model)
+
+	assert.Equal(t, []string{"/burgers"}, matcher.Candidates(1))
+	assert.Equal(t, []string{"/burgers/{burgerId}", "/fries/{friesId}"}, matcher.Candidates(2))
+	assert.Equal(t, []string{"/burgers/{burgerId}/locate"}, matcher.Candidates(3))
+	assert.Empty(t, matcher.Candidates(4))
+}
+
+func TestPathMatcher_MatchesMatchPath(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /api/burgers:
+    get:
+      operationId: listApiBurgers
+  /burgers/{burgerId}:
+    get:
+      operationId: getBurger
+    post:
+      operationId: updateBurger
+  /burgers/special:
+    get:
+      operationId: getSpecialBurger
+  /burgers/{burgerId}/locate:
+    patch:
+      operationId: locateBurger
+  /fries/{friesId}#large:
+    get:
+      operationId: getLargeFries
+  /fries/{friesId}#small:
+    get:
+      operationId: getSmallFries
+  /drinks:
+    get:
+      operationId: listDrinks
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	matcher := NewPathMatcher(&m.Model)
+
+	for _, tc := range []struct {
+		method string
+		url    string
+	}{
+		{http.MethodGet, "https://things.com/api/burgers"},
+		{http.MethodGet, "https://things.com/api/burgers/123"},
+		{http.MethodPost, "https://things.com/burgers/123"},
+		{http.MethodGet, "https://things.com/burgers/special"},
+		{http.MethodPatch, "https://things.com/burgers/123/locate"},
+		{http.MethodGet, "https://things.com/burgers/123/locate"},
+		{http.MethodGet, "https://things.com/fries/1#small"},
+		{http.MethodGet, "https://things.com/fries/1"},
+		{http.MethodGet, "https://things.com/api/drinks/"},
+		{http.MethodGet, "https://things.com/drinks"},
+		{http.MethodGet, "https://things.com/burgers/./123"},
+		{http.MethodGet, "https://things.com/nothing/here"},
+	} {
+		request, _ := http.NewRequest(tc.method, tc.url, nil)
+		assert.Equal(t, MatchPath(request, &m.Model), matcher.Match(request), "%s %s", tc.method, tc.url)
+	}
+}
+
+func BenchmarkPathMatcher_Templated(b *testing.B) {
+	document := buildBenchmarkDocument(b, 250)
+	matcher := NewPathMatcher(document)
+	request, _ := http.NewRequest(http.MethodGet, "https://api.pb33f.io/v1/resources249/1234/items/5678", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if result := matcher.Match(request); result.PathItem == nil {
+			b.Fatal("path not found")
+		}
+	}
+}
+
+func BenchmarkPathMatcher_Literal(b *testing.B) {
+	document := buildBenchmarkDocument(b, 250)
+	matcher := NewPathMatcher(document)
+	request, _ := http.NewRequest(http.MethodPost, "https://api.pb33f.io/v1/resources249/list", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if result := matcher.Match(request); result.PathItem == nil {
+			b.Fatal("path not found")
+		}
+	}
+}
+
+func TestMatchPath_Suggestions(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      operationId: getBurger
+  /burgers/{burgerId}/locate:
+    get:
+      operationId: locateBurger
+  /fries:
+    get:
+      operationId: listFries
+  /drinks/{drinkId}/refill:
+    get:
+      operationId: refillDrink
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/123/locat", nil)
+
+	// no suggestions by default.
+	result := MatchPath(request, &m.Model)
+	assert.Len(t, result.Errors, 1)
+	assert.Nil(t, result.Errors[0].PathSuggestions)
+	assert.Equal(t, errors.HowToFixPath, result.Errors[0].HowToFix)
+
+	result = MatchPath(request, &m.Model, config.WithPathSuggestions(2))
+	assert.Len(t, result.Errors, 1)
+	suggestions := result.Errors[0].PathSuggestions
+	assert.Len(t, suggestions, 2)
+	assert.Equal(t, "/burgers/{burgerId}/locate", suggestions[0].Path)
+	assert.Equal(t, 6, suggestions[0].Line)
+	assert.Equal(t, 3, suggestions[0].Column)
+	assert.Equal(t, 2, suggestions[0].MatchingSegments)
+	assert.Equal(t, "/burgers/{burgerId}", suggestions[1].Path)
+	assert.Equal(t, 3, suggestions[1].Line)
+	assert.Equal(t, "Check the path is correct, the most similar path in the specification is "+
+		"'/burgers/{burgerId}/locate' (line 6)", result.Errors[0].HowToFix)
+
+	// the same suggestions are made by a matcher.
+	assert.Equal(t, result, NewPathMatcher(&m.Model, config.WithPathSuggestions(2)).Match(request))
+
+	// nothing in common, nothing to suggest.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/pizza", nil)
+	result = MatchPath(request, &m.Model, config.WithPathSuggestions(2))
+	assert.Len(t, result.Errors, 1)
+	assert.Nil(t, result.Errors[0].PathSuggestions)
+}
+
+func TestMatchPath_Root(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /:
+    get:
+      operationId: getRoot
+  /{burgerId}:
+    get:
+      operationId: getBurger
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	matcher := NewPathMatcher(&m.Model)
+	for _, requestURL := range []string{
+		"https://things.com/",
+		"https://things.com",
+		"https://things.com/api",
+		"https://things.com/api/",
+	} {
+		request, _ := http.NewRequest(http.MethodGet, requestURL, nil)
+		result := MatchPath(request, &m.Model)
+		assert.Nil(t, result.Errors, requestURL)
+		assert.Equal(t, "/", result.FoundPath, requestURL)
+		assert.Equal(t, "getRoot", result.Operation.OperationId, requestURL)
+		assert.Equal(t, result, matcher.Match(request), requestURL)
+	}
+}
+
+func TestMatchPath_RootNotDefined(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /{burgerId}:
+    get:
+      operationId: getBurger
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	matcher := NewPathMatcher(&m.Model)
+	for _, requestURL := range []string{"https://things.com/", "https://things.com"} {
+		request, _ := http.NewRequest(http.MethodGet, requestURL, nil)
+		result := MatchPath(request, &m.Model)
+		assert.Nil(t, result.PathItem, requestURL)
+		assert.Len(t, result.Errors, 1, requestURL)
+		assert.True(t, result.Errors[0].IsPathMissingError(), requestURL)
+		assert.Equal(t, result, matcher.Match(request), requestURL)
+	}
+
+	// a template still matches a single segment.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/123", nil)
+	assert.Equal(t, "/{burgerId}", MatchPath(request, &m.Model).FoundPath)
+}
+
+func TestPathTemplateMatches(t *testing.T) {
+	matches, params := PathTemplateMatches("/burgers/{burgerId}/{size}-{sauce}", "/burgers/123/large-ketchup")
+	assert.True(t, matches)
+	assert.Equal(t, map[string]string{"burgerId": "123", "size": "large", "sauce": "ketchup"}, params)
+
+	matches, params = PathTemplateMatches("/burgers/{burgerId}", "/burgers/123/locate")
+	assert.False(t, matches)
+	assert.Nil(t, params)
+
+	matches, params = PathTemplateMatches("/burgers/special", "/burgers/special")
+	assert.True(t, matches)
+	assert.Empty(t, params)
+
+	matches, _ = PathTemplateMatches("/files/a b/{fileId}", "/files/a%20b/1")
+	assert.True(t, matches)
+
+	matches, _ = PathTemplateMatches("/", "/")
+	assert.True(t, matches)
+
+	matches, _ = PathTemplateMatches("/{burgerId}", "/")
+	assert.False(t, matches)
+
+	matches, params = PathTemplateMatches("/fries/{friesId}#large", "/fries/1#large")
+	assert.True(t, matches)
+	assert.Equal(t, map[string]string{"friesId": "1"}, params)
+
+	matches, _ = PathTemplateMatches("/hashy#one", "/hashy#two")
+	assert.False(t, matches)
+
+	matches, _ = PathTemplateMatches("/burgers/{burgerId", "/burgers/123")
+	assert.False(t, matches)
+}
+
+func TestDetectAmbiguousPaths(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+  /users/me:
+    get:
+      operationId: getMe
+  /users/{userId}:
+    delete:
+      operationId: deleteUser
+  /users/{id}/posts/{postId}:
+    get:
+      operationId: getPost
+  /users/{name}/posts/{slug}:
+    get:
+      operationId: getPostBySlug
+  /files/a%20b:
+    get:
+      operationId: getFile
+  /files/a b:
+    get:
+      operationId: getOtherFile
+  /reports/{name}.json:
+    get:
+      operationId: getJSONReport
+  /reports/{name}.xml:
+    get:
+      operationId: getXMLReport
+  /reports/{title}.json:
+    get:
+      operationId: getReportByTitle`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	ambiguous := DetectAmbiguousPaths(&m.Model)
+	assert.Len(t, ambiguous, 4)
+
+	assert.Equal(t, PathTemplate{Path: "/users/{id}", Line: 3, Column: 3}, ambiguous[0].First)
+	assert.Equal(t, PathTemplate{Path: "/users/{userId}", Line: 9, Column: 3}, ambiguous[0].Second)
+	assert.Equal(t, "/users/{id}/posts/{postId}", ambiguous[1].First.Path)
+	assert.Equal(t, "/users/{name}/posts/{slug}", ambiguous[1].Second.Path)
+	assert.Equal(t, "/files/a%20b", ambiguous[2].First.Path)
+	assert.Equal(t, "/files/a b", ambiguous[2].Second.Path)
+
+	// the literal text of compound segments is kept, so only templates with the same extension are ambiguous.
+	assert.Equal(t, "/reports/{name}.json", ambiguous[3].First.Path)
+	assert.Equal(t, "/reports/{title}.json", ambiguous[3].Second.Path)
+
+	assert.Nil(t, DetectAmbiguousPaths(&v3.Document{}))
+}
+
+func TestDetectOverlappingPaths(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /files/latest:
+    get:
+      operationId: getLatest
+  /files/{version}:
+    get:
+      operationId: getVersion
+  /files/{version}/raw:
+    get:
+      operationId: getRaw
+  /files/v1/{format}:
+    get:
+      operationId: getFormat
+  /reports/{name}.json:
+    get:
+      operationId: getReport
+  /reports/summary.json:
+    get:
+      operationId: getSummary
+  /reports/summary.xml:
+    get:
+      operationId: getSummaryXML
+  /users/{id}:
+    get:
+      operationId: getUser
+  /users/{userId}:
+    get:
+      operationId: getUserById`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	overlapping := DetectOverlappingPaths(&m.Model)
+	assert.Len(t, overlapping, 3)
+
+	assert.Equal(t, PathTemplate{Path: "/files/latest", Line: 3, Column: 3}, overlapping[0].First)
+	assert.Equal(t, PathTemplate{Path: "/files/{version}", Line: 6, Column: 3}, overlapping[0].Second)
+	assert.Equal(t, []SegmentOverlap{{Position: 1, First: "latest", Second: "{version}"}}, overlapping[0].Segments)
+
+	// each path has a literal where the other has a template.
+	assert.Equal(t, "/files/{version}/raw", overlapping[1].First.Path)
+	assert.Equal(t, "/files/v1/{format}", overlapping[1].Second.Path)
+	assert.Equal(t, []SegmentOverlap{
+		{Position: 1, First: "{version}", Second: "v1"},
+		{Position: 2, First: "raw", Second: "{format}"},
+	}, overlapping[1].Segments)
+
+	// only the literal that the compound template can match overlaps it.
+	assert.Equal(t, "/reports/{name}.json", overlapping[2].First.Path)
+	assert.Equal(t, "/reports/summary.json", overlapping[2].Second.Path)
+
+	// the path defined first is matched, whichever of the paths is static.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/reports/summary.json", nil)
+	assert.Equal(t, "/reports/{name}.json", MatchPath(request, &m.Model).FoundPath)
+
+	assert.Nil(t, DetectOverlappingPaths(&v3.Document{}))
+}
+
+func TestMatchPathInOrder(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+  /users/me:
+    get:
+      operationId: getMe`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	templates := PathTemplates(&m.Model)
+	assert.Equal(t, []string{"/users/{id}", "/users/me"}, templates)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/users/me", nil)
+
+	// document order, the template defined first wins.
+	result := MatchPath(request, &m.Model)
+	assert.Equal(t, "/users/{id}", result.FoundPath)
+
+	// most specific first.
+	result = MatchPathInOrder(request, &m.Model, []string{"/users/me", "/users/{id}", "/not/in/the/document"})
+	assert.Equal(t, "/users/me", result.FoundPath)
+	assert.Equal(t, "getMe", result.Operation.OperationId)
+
+	pathItem, errs, foundPath := FindPathInOrder(request, &m.Model, []string{"/users/{id}"})
+	assert.NotNil(t, pathItem)
+	assert.Len(t, errs, 0)
+	assert.Equal(t, "/users/{id}", foundPath)
+
+	// templates that are not supplied are never matched.
+	pathItem, errs, _ = FindPathInOrder(request, &m.Model, nil)
+	assert.Nil(t, pathItem)
+	assert.Len(t, errs, 1)
+}
+
+func TestMatchPath_ServerOverrides(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/v1
+paths:
+  /burgers:
+    get:
+      operationId: getBurgers
+    post:
+      operationId: createBurger
+      servers:
+        - url: https://things.com/v2
+  /fries/{friesId}:
+    servers:
+      - url: https://things.com/legacy
+    get:
+      operationId: getFries`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	matcher := NewPathMatcher(&m.Model)
+
+	for _, match := range []func(*http.Request) *PathMatchResult{
+		func(request *http.Request) *PathMatchResult { return MatchPath(request, &m.Model) },
+		matcher.Match,
+	} {
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com/v1/burgers", nil)
+		result := match(request)
+		assert.Equal(t, "/burgers", result.FoundPath)
+		assert.Equal(t, 0, result.ServerIndex)
+
+		// the operation servers override the document servers.
+		request, _ = http.NewRequest(http.MethodPost, "https://things.com/v2/burgers", nil)
+		result = match(request)
+		assert.Equal(t, "/burgers", result.FoundPath)
+		assert.Equal(t, "createBurger", result.Operation.OperationId)
+		assert.Equal(t, 0, result.ServerIndex)
+
+		request, _ = http.NewRequest(http.MethodPost, "https://things.com/v1/burgers", nil)
+		result = match(request)
+		assert.Nil(t, result.PathItem)
+
+		// the path item servers override the document servers.
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/legacy/fries/12", nil)
+		result = match(request)
+		assert.Equal(t, "/fries/{friesId}", result.FoundPath)
+		assert.Equal(t, map[string]string{"friesId": "12"}, result.Params)
+	}
+}
+
+func TestMatchPath_TrailingSlash(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /burgers:
+    get:
+      operationId: getBurgers
+  /fries/{friesId}/:
+    get:
+      operationId: getFries`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/burgers/", nil)
+	assert.Nil(t, MatchPath(request, &m.Model).PathItem)
+
+	matcher := NewPathMatcher(&m.Model, config.WithTrailingSlash())
+	for _, match := range []func(*http.Request) *PathMatchResult{
+		func(request *http.Request) *PathMatchResult {
+			return MatchPath(request, &m.Model, config.WithTrailingSlash())
+		},
+		matcher.Match,
+	} {
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/burgers/", nil)
+		result := match(request)
+		assert.Equal(t, "/burgers", result.FoundPath)
+
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/fries/12", nil)
+		result = match(request)
+		assert.Equal(t, "/fries/{friesId}/", result.FoundPath)
+		assert.Equal(t, map[string]string{"friesId": "12"}, result.Params)
+
+		// the root is still the root.
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/", nil)
+		assert.Nil(t, match(request).PathItem)
+	}
+}
+
+func TestMatchPath_CaseInsensitive(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /burgers/{burgerId}/toppings:
+    get:
+      operationId: getToppings
+  /menu:
+    get:
+      operationId: getMenu`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/Burgers/BigMac/TOPPINGS", nil)
+	assert.Nil(t, MatchPath(request, &m.Model).PathItem)
+
+	matcher := NewPathMatcher(&m.Model, config.WithCaseInsensitive())
+	for _, match := range []func(*http.Request) *PathMatchResult{
+		func(request *http.Request) *PathMatchResult {
+			return MatchPath(request, &m.Model, config.WithCaseInsensitive())
+		},
+		matcher.Match,
+	} {
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/Burgers/BigMac/TOPPINGS", nil)
+		result := match(request)
+		assert.Equal(t, "/burgers/{burgerId}/toppings", result.FoundPath)
+		assert.Equal(t, map[string]string{"burgerId": "BigMac"}, result.Params)
+
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/MENU", nil)
+		assert.Equal(t, "/menu", match(request).FoundPath)
+
+		// server base paths are still matched exactly.
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/API/menu", nil)
+		assert.Nil(t, match(request).PathItem)
+	}
+}
+
+func TestMatchPath_ServerStripping(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /api/burgers:
+    get:
+      operationId: getBurgers
+  /burgers:
+    get:
+      operationId: getLegacyBurgers`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/burgers", nil)
+	assert.Equal(t, "/api/burgers", MatchPath(request, &m.Model, config.WithServerStripping(true)).FoundPath)
+
+	matcher := NewPathMatcher(&m.Model, config.WithServerStripping(false))
+	for _, match := range []func(*http.Request) *PathMatchResult{
+		func(request *http.Request) *PathMatchResult {
+			return MatchPath(request, &m.Model, config.WithServerStripping(false))
+		},
+		matcher.Match,
+	} {
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/burgers", nil)
+		result := match(request)
+		assert.Equal(t, "/api/burgers", result.FoundPath)
+		assert.Equal(t, -1, result.ServerIndex)
+
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+		assert.Equal(t, "getLegacyBurgers", match(request).Operation.OperationId)
+	}
+}
+
+func TestMatchPath_ReservedPathParameters(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /files/{path}/raw:
+    get:
+      operationId: getRawFile
+      parameters:
+        - name: path
+          in: path
+          required: true
+          x-allow-reserved: true
+          schema:
+            type: string
+  /files/{path}:
+    get:
+      operationId: getFile
+      parameters:
+        - name: path
+          in: path
+          required: true
+          allowReserved: true
+          schema:
+            type: string
+  /folders/{name}:
+    get:
+      operationId: getFolder
+      parameters:
+        - name: name
+          in: path
+          required: true
+          schema:
+            type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	// without the option, slashes separate segments.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/files/docs/readme.md", nil)
+	assert.Nil(t, MatchPath(request, &m.Model).PathItem)
+
+	matcher := NewPathMatcher(&m.Model, config.WithReservedPathParameters())
+	for _, match := range []func(*http.Request) *PathMatchResult{
+		func(request *http.Request) *PathMatchResult {
+			return MatchPath(request, &m.Model, config.WithReservedPathParameters())
+		},
+		matcher.Match,
+	} {
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/files/docs/readme.md", nil)
+		result := match(request)
+		assert.Equal(t, "/files/{path}", result.FoundPath)
+		assert.Equal(t, map[string]string{"path": "docs/readme.md"}, result.Params)
+
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/files/readme.md", nil)
+		assert.Equal(t, map[string]string{"path": "readme.md"}, match(request).Params)
+
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/files/a/b/c/raw", nil)
+		result = match(request)
+		assert.Equal(t, "getRawFile", result.Operation.OperationId)
+		assert.Equal(t, map[string]string{"path": "a/b/c"}, result.Params)
+
+		// only parameters that allow reserved characters span segments.
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/folders/a/b", nil)
+		assert.Nil(t, match(request).PathItem)
+	}
+
+	match, errs := FindPathParameters(request, &m.Model, config.WithReservedPathParameters())
+	assert.Nil(t, match)
+	assert.Len(t, errs, 1)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/files/docs/readme.md", nil)
+	match, errs = FindPathParameters(request, &m.Model, config.WithReservedPathParameters())
+	assert.Nil(t, errs)
+	assert.Equal(t, map[string]string{"path": "docs/readme.md"}, match.Values)
+}
+
+func TestMatchPath_DeprecatedOperation(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      operationId: getBurgers
+      deprecated: true
+    post:
+      operationId: createBurger`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+	result := MatchPath(request, &m.Model)
+	assert.Nil(t, result.Errors)
+	assert.Len(t, result.Warnings, 1)
+	assert.Equal(t, "deprecated", result.Warnings[0].WarningType)
+	assert.Equal(t, "GET operation for path '/burgers' is deprecated", result.Warnings[0].Message)
+	assert.Equal(t, 6, result.Warnings[0].SpecLine)
+
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/burgers", nil)
+	assert.Len(t, MatchPath(request, &m.Model).Warnings, 0)
+}
+
+func TestPathMatcher_MatchCounted(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      operationId: getBurgers
+  /burgers/{id}:
+    get:
+      operationId: getBurger
+  /burgers/{id}/fries:
+    post:
+      operationId: addFries`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	matcher := NewPathMatcher(&m.Model)
+
+	// only the paths with two segments are compared.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/1", nil)
+	result, candidates := matcher.MatchCounted(request)
+	assert.Equal(t, "getBurger", result.Operation.OperationId)
+	assert.Equal(t, 1, candidates)
+
+	// a literal match is not compared again.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+	_, candidates = matcher.MatchCounted(request)
+	assert.Equal(t, 1, candidates)
+
+	// paths without the request method are never compared.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/fries/1", nil)
+	result, candidates = matcher.MatchCounted(request)
+	assert.Nil(t, result.PathItem)
+	assert.Equal(t, 0, candidates)
+}
+
+func TestPathMatcher_ServerOverrides(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /burgers/{id}:
+    get:
+      operationId: getBurger
+  /fries/{id}:
+    servers:
+      - url: https://things.com/v2
+    get:
+      operationId: getFries
+  /drinks:
+    get:
+      operationId: listDrinks
+  /drinks/{id}:
+    get:
+      servers:
+        - url: https://things.com/v3
+      operationId: getDrink`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	matcher := NewPathMatcher(&m.Model)
+
+	for _, tc := range []string{
+		"https://things.com/api/burgers/1",
+		"https://things.com/v2/fries/1",
+		"https://things.com/api/fries/1",
+		"https://things.com/api/drinks",
+		"https://things.com/v3/drinks/1",
+		"https://things.com/api/drinks/1",
+	} {
+		request, _ := http.NewRequest(http.MethodGet, tc, nil)
+		assert.Equal(t, MatchPath(request, &m.Model), matcher.Match(request), tc)
+	}
+
+	// the paths that share the document servers are still indexed, so only the overriding path defined before the
+	// literal match is compared.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/drinks", nil)
+	result, candidates := matcher.MatchCounted(request)
+	assert.Equal(t, "listDrinks", result.Operation.OperationId)
+	assert.Equal(t, 2, candidates)
+
+	// an overriding path is matched with its own base path.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/v2/fries/1", nil)
+	result, _ = matcher.MatchCounted(request)
+	assert.Equal(t, "getFries", result.Operation.OperationId)
+	assert.Equal(t, "/fries/{id}", result.FoundPath)
+}
+
+func TestMatchPath_Literal(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{id}:
+    get:
+      operationId: getBurger
+  /burgers/specials:
+    get:
+      operationId: getSpecials`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	matcher := NewPathMatcher(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/123", nil)
+	result := MatchPath(request, &m.Model)
+	assert.Equal(t, "/burgers/{id}", result.FoundPath)
+	assert.False(t, result.Literal)
+	assert.Equal(t, result, matcher.Match(request))
+
+	// the template is defined first, so it wins.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/specials", nil)
+	assert.False(t, MatchPath(request, &m.Model).Literal)
+
+	spec = `openapi: 3.1.0
+paths:
+  /burgers/specials:
+    get:
+      operationId: getSpecials`
+
+	doc, _ = libopenapi.NewDocument([]byte(spec))
+	m, _ = doc.BuildV3Model()
+
+	result = MatchPath(request, &m.Model)
+	assert.Equal(t, "/burgers/specials", result.FoundPath)
+	assert.True(t, result.Literal)
+	assert.Equal(t, result, NewPathMatcher(&m.Model).Match(request))
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/pizza", nil)
+	assert.False(t, MatchPath(request, &m.Model).Literal)
+}
+
+func TestMatchPath_NoLeadingSlash(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  burgers/{id}:
+    get:
+      operationId: getBurger
+  burgers/specials/today:
+    get:
+      operationId: getSpecials`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	matcher := NewPathMatcher(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/123", nil)
+	result := MatchPath(request, &m.Model)
+	assert.NotNil(t, result.PathItem)
+	assert.Equal(t, "burgers/{id}", result.FoundPath)
+	assert.Equal(t, map[string]string{"id": "123"}, result.Params)
+	assert.Equal(t, result, matcher.Match(request))
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/specials/today", nil)
+	result = MatchPath(request, &m.Model)
+	assert.Equal(t, "burgers/specials/today", result.FoundPath)
+	assert.True(t, result.Literal)
+	assert.Equal(t, result, matcher.Match(request))
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/pizza/123", nil)
+	assert.Nil(t, MatchPath(request, &m.Model).PathItem)
+}
+
+func TestMatchPath_RequestPathPrefix(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /v1:
+    get:
+      operationId: getRoot
+  /v1/burgers/{id}:
+    get:
+      operationId: getBurger`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	for _, prefix := range []string{"/v1", "v1", "/v1/"} {
+		opts := []config.Option{config.WithRequestPathPrefix(prefix)}
+		matcher := NewPathMatcher(&m.Model, opts...)
+
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/123", nil)
+		result := MatchPath(request, &m.Model, opts...)
+		assert.NotNil(t, result.PathItem, prefix)
+		assert.Equal(t, "/v1/burgers/{id}", result.FoundPath, prefix)
+		assert.Equal(t, map[string]string{"id": "123"}, result.Params, prefix)
+		assert.Equal(t, result, matcher.Match(request), prefix)
+		assert.Equal(t, "/v1/burgers/123", StripRequestPath(request, &m.Model, opts...), prefix)
+
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/", nil)
+		assert.Equal(t, "/v1", MatchPath(request, &m.Model, opts...).FoundPath, prefix)
+
+		// the prefix is always added, so a request that has kept it is not found.
+		request, _ = http.NewRequest(http.MethodGet, "https://things.com/v1/burgers/123", nil)
+		assert.Nil(t, MatchPath(request, &m.Model, opts...).PathItem, prefix)
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/123", nil)
+	assert.Nil(t, MatchPath(request, &m.Model).PathItem)
+}
+
+func TestMatchPath_MethodExtension(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /cache/{region}:
+    parameters:
+      - name: region
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: getCache
+    x-method:
+      PURGE:
+        operationId: purgeCache
+        parameters:
+          - name: force
+            in: query
+            schema:
+              type: boolean`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest("PURGE", "https://things.com/cache/eu", nil)
+
+	// without the option, there is no operation for the method.
+	assert.Nil(t, MatchPath(request, &m.Model).PathItem)
+
+	result := MatchPath(request, &m.Model, config.WithMethodExtension("x-method"))
+	assert.NotNil(t, result.PathItem)
+	assert.Equal(t, "/cache/{region}", result.FoundPath)
+	assert.Equal(t, "purgeCache", result.Operation.OperationId)
+	assert.Equal(t, result, NewPathMatcher(&m.Model, config.WithMethodExtension("x-method")).Match(request))
+
+	// the parameters of the path item and the selected operation are merged.
+	params := helpers.NewOperationCache(&m.Model, "x-method").ExtractParamsForOperation(request, result.PathItem)
+	assert.Len(t, params, 2)
+	assert.Equal(t, "force", params[1].Name)
+
+	// the operation is not part of the model, so it is only selected by an operation cache with the extension.
+	assert.Len(t, helpers.ExtractParamsForOperation(request, result.PathItem), 1)
+	assert.Nil(t, helpers.NewOperationCache(&m.Model, "").ExtractOperation(request, result.PathItem))
+
+	// a shared cache builds the operation once, for every matcher and validator that uses it.
+	operations := helpers.NewOperationCache(&m.Model, "x-method")
+	shared := NewPathMatcher(&m.Model, config.WithMethodExtension("x-method"), config.WithOperationCache(operations))
+	assert.Same(t, operations.ExtractOperation(request, result.PathItem), shared.Match(request).Operation)
+
+	// standard methods still use the standard fields.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/cache/eu", nil)
+	result = MatchPath(request, &m.Model, config.WithMethodExtension("x-method"))
+	assert.Equal(t, "getCache", result.Operation.OperationId)
+
+	request, _ = http.NewRequest(http.MethodDelete, "https://things.com/cache/eu", nil)
+	assert.Nil(t, MatchPath(request, &m.Model, config.WithMethodExtension("x-method")).PathItem)
+}
+
+func TestMatchPath_NotFoundDefinedMethods(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /users/{id}:
+    post:
+      operationId: updateUser
+    delete:
+      operationId: deleteUser
+  /users/list:
+    get:
+      operationId: listUsers`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/users/42", nil)
+	result := MatchPath(request, &m.Model)
+	assert.Nil(t, result.PathItem)
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, "GET Path '/users/42' not found, but POST, DELETE are defined", result.Errors[0].Message)
+	assert.Equal(t, "The GET request contains a path of '/users/42', that path exists in the specification, "+
+		"however it does not define the GET method, only POST, DELETE", result.Errors[0].Reason)
+	assert.Equal(t, "Check the correct HTTP method has been used, the path defines: POST, DELETE",
+		result.Errors[0].HowToFix)
+	assert.True(t, result.Errors[0].IsPathMissingError())
+	assert.Equal(t, result, NewPathMatcher(&m.Model).Match(request))
+
+	// a path that does not exist for any method is only reported as not found.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/pizza/42", nil)
+	result = MatchPath(request, &m.Model)
+	assert.Equal(t, "GET Path '/pizza/42' not found", result.Errors[0].Message)
+	assert.Equal(t, errors.HowToFixPath, result.Errors[0].HowToFix)
+}
+
+func TestFindOperationById(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      operationId: listBurgers
+    post:
+      operationId: createBurger
+  /burgers/{id}:
+    get:
+      operationId: getBurger
+    delete:
+      operationId: listBurgers`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	pathItem, operation, path := FindOperationById(&m.Model, "getBurger")
+	assert.Equal(t, "/burgers/{id}", path)
+	assert.Equal(t, m.Model.Paths.PathItems.GetOrZero("/burgers/{id}"), pathItem)
+	assert.Equal(t, pathItem.Get, operation)
+
+	pathItem, operation, path = FindOperationById(&m.Model, "createBurger")
+	assert.Equal(t, "/burgers", path)
+	assert.Equal(t, pathItem.Post, operation)
+
+	// a duplicated operationId finds the first operation with it.
+	pathItem, operation, path = FindOperationById(&m.Model, "listBurgers")
+	assert.Equal(t, "/burgers", path)
+	assert.Equal(t, pathItem.Get, operation)
+
+	// operation IDs are case-sensitive.
+	pathItem, operation, path = FindOperationById(&m.Model, "GetBurger")
+	assert.Nil(t, pathItem)
+	assert.Nil(t, operation)
+	assert.Empty(t, path)
+
+	_, operation, _ = FindOperationById(&m.Model, "")
+	assert.Nil(t, operation)
+	_, operation, _ = FindOperationById(nil, "getBurger")
+	assert.Nil(t, operation)
+}
+
+func TestMatchPath_CompoundSegmentLiterals(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /files/{name}.json:
+    get:
+      operationId: getJSON
+  /files/{name}.xml:
+    get:
+      operationId: getXML
+  /files/{name}.{ext}:
+    get:
+      operationId: getOther`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	matcher := NewPathMatcher(&m.Model)
+
+	for requestPath, operationId := range map[string]string{
+		"/files/foo.json":    "getJSON",
+		"/files/foo.xml":     "getXML",
+		"/files/foo.yaml":    "getOther",
+		"/files/foo.bar.xml": "getXML",
+	} {
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com"+requestPath, nil)
+		for _, result := range []*PathMatchResult{MatchPath(request, &m.Model), matcher.Match(request)} {
+			assert.Nil(t, result.Errors, requestPath)
+			if assert.NotNil(t, result.Operation, requestPath) {
+				assert.Equal(t, operationId, result.Operation.OperationId, requestPath)
+			}
+		}
+	}
+
+	// the literal text must be in the request path, even if it is the only path a parameter could match.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/files/foo", nil)
+	result := MatchPath(request, &m.Model)
+	assert.Nil(t, result.PathItem)
+	assert.Len(t, result.Errors, 1)
+
+	// the literal text is matched case-insensitively if configured.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/files/foo.XML", nil)
+	result = MatchPath(request, &m.Model, config.WithCaseInsensitiveCompoundLiterals())
+	assert.Equal(t, "getXML", result.Operation.OperationId)
+	assert.Equal(t, map[string]string{"name": "foo"}, result.Params)
+}
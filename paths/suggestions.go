@@ -0,0 +1,110 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package paths
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// attachPathSuggestions will attach path suggestions to the 'not found' error of a result, and point the author
+// at the most similar path.
+func attachPathSuggestions(result *PathMatchResult, suggestions []*errors.PathSuggestion) {
+	if len(suggestions) == 0 || len(result.Errors) == 0 {
+		return
+	}
+	result.Errors[0].PathSuggestions = suggestions
+	result.Errors[0].HowToFix = fmt.Sprintf(errors.HowToFixPathSuggestion, suggestions[0].Path, suggestions[0].Line)
+}
+
+type pathSimilarity struct {
+	suggestion    *errors.PathSuggestion
+	prefixLength  int
+	segmentsDelta int
+}
+
+// suggestPaths will return up to count of the paths in the document that are most similar to the request path
+// segments. Paths are ranked by the number of leading segments that match (template segments match anything),
+// then by the length of the common prefix of the first segment that does not match. Paths that share nothing with the
+// request path are never suggested.
+func suggestPaths(document *v3.Document, requested []string, count int) []*errors.PathSuggestion {
+	if count <= 0 || document == nil || document.Paths == nil {
+		return nil
+	}
+	var candidates []*pathSimilarity
+	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
+		path, _, _ := strings.Cut(pair.Key(), "#")
+		segments := strings.Split(strings.TrimPrefix(path, helpers.Slash), helpers.Slash)
+
+		matching, prefixLength := 0, 0
+		for matching < len(segments) && matching < len(requested) &&
+			pathSegmentMatches(segments[matching], requested[matching]) {
+			matching++
+		}
+		if matching < len(segments) && matching < len(requested) {
+			prefixLength = commonPrefixLength(segments[matching], requested[matching])
+		}
+		if matching == 0 && prefixLength == 0 {
+			continue
+		}
+
+		line, col := -1, -1
+		if low := pair.Value().GoLow(); low != nil && low.KeyNode != nil {
+			line, col = low.KeyNode.Line, low.KeyNode.Column
+		}
+		delta := len(segments) - len(requested)
+		if delta < 0 {
+			delta = -delta
+		}
+		candidates = append(candidates, &pathSimilarity{
+			suggestion: &errors.PathSuggestion{
+				Path:             pair.Key(),
+				Line:             line,
+				Column:           col,
+				MatchingSegments: matching,
+			},
+			prefixLength:  prefixLength,
+			segmentsDelta: delta,
+		})
+	}
+
+	// the most similar paths first, keeping the document order for paths that are equally similar.
+	slices.SortStableFunc(candidates, func(a, b *pathSimilarity) int {
+		if a.suggestion.MatchingSegments != b.suggestion.MatchingSegments {
+			return b.suggestion.MatchingSegments - a.suggestion.MatchingSegments
+		}
+		if a.prefixLength != b.prefixLength {
+			return b.prefixLength - a.prefixLength
+		}
+		return a.segmentsDelta - b.segmentsDelta
+	})
+
+	var suggestions []*errors.PathSuggestion
+	for i := 0; i < len(candidates) && i < count; i++ {
+		suggestions = append(suggestions, candidates[i].suggestion)
+	}
+	return suggestions
+}
+
+// pathSegmentMatches returns true if a segment of a path template matches a segment of a request path.
+func pathSegmentMatches(segment, requested string) bool {
+	if strings.ContainsAny(segment, "{}") {
+		return helpers.IsValidPathSegmentTemplate(segment) && requested != ""
+	}
+	return segment == requested || equalUnescapedSegments(segment, requested)
+}
+
+func commonPrefixLength(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
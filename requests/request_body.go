@@ -4,7 +4,9 @@
 package requests
 
 import (
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/datamodel/high/v3"
 	"net/http"
@@ -29,8 +31,14 @@ type RequestBodyValidator interface {
 }
 
 // NewRequestBodyValidator will create a new RequestBodyValidator from an OpenAPI 3+ document
-func NewRequestBodyValidator(document *v3.Document) RequestBodyValidator {
-	return &requestBodyValidator{document: document, schemaCache: &sync.Map{}}
+func NewRequestBodyValidator(document *v3.Document, opts ...config.Option) RequestBodyValidator {
+	options := config.NewValidationOptions(opts...)
+	return &requestBodyValidator{
+		document:    document,
+		options:     options,
+		operations:  options.OperationCacheFor(document),
+		schemaCache: &sync.Map{},
+	}
 }
 
 func (v *requestBodyValidator) SetPathItem(path *v3.PathItem, pathValue string) {
@@ -46,6 +54,8 @@ type schemaCache struct {
 
 type requestBodyValidator struct {
 	document    *v3.Document
+	options     *config.ValidationOptions
+	operations  *helpers.OperationCache
 	pathItem    *v3.PathItem
 	pathValue   string
 	errors      []*errors.ValidationError
@@ -0,0 +1,60 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package requests
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// ValidateAcceptHeader will check that the Accept header of a request accepts at least one of the response content
+// types defined by the operation (across every response code, and the default response). Q-values and wildcards
+// are honored, so a media range with a quality of 0 rejects the content types it matches. Requests without an Accept
+// header, and operations that define no response content, always pass.
+func ValidateAcceptHeader(request *http.Request, operation *v3.Operation, specPath string) (bool, []*errors.ValidationError) {
+	accept := request.Header.Get(helpers.AcceptHeader)
+	if accept == "" || operation == nil {
+		return true, nil
+	}
+	contentTypes := responseContentTypes(operation)
+	if len(contentTypes) == 0 {
+		return true, nil
+	}
+	ranges := helpers.ParseAcceptHeader(accept)
+	for _, contentType := range contentTypes {
+		if helpers.MediaTypeQuality(contentType, ranges) > 0 {
+			return true, nil
+		}
+	}
+	return false, []*errors.ValidationError{errors.RequestNotAcceptable(operation, request, specPath, contentTypes)}
+}
+
+// responseContentTypes returns every content type defined by the responses of an operation, in the order they are
+// defined, without duplicates.
+func responseContentTypes(operation *v3.Operation) []string {
+	if operation.Responses == nil {
+		return nil
+	}
+	var contentTypes []string
+	collect := func(response *v3.Response) {
+		if response == nil {
+			return
+		}
+		for pair := orderedmap.First(response.Content); pair != nil; pair = pair.Next() {
+			if !slices.Contains(contentTypes, pair.Key()) {
+				contentTypes = append(contentTypes, pair.Key())
+			}
+		}
+	}
+	for pair := orderedmap.First(operation.Responses.Codes); pair != nil; pair = pair.Next() {
+		collect(pair.Value())
+	}
+	collect(operation.Responses.Default)
+	return contentTypes
+}
@@ -4,23 +4,34 @@
 package requests
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 	"strings"
 
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
+	"github.com/pb33f/libopenapi-validator/schema_validation"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/utils"
 )
 
 func (v *requestBodyValidator) ValidateRequestBody(request *http.Request) (bool, []*errors.ValidationError) {
+	valid, validationErrors := v.validateRequestBody(request)
+	errors.SetValidationDirection(validationErrors, helpers.RequestDirection)
+	errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
+	return valid, validationErrors
+}
+
+func (v *requestBodyValidator) validateRequestBody(request *http.Request) (bool, []*errors.ValidationError) {
 	// find path
 	var pathItem = v.pathItem
 	var foundPath string
 	if v.pathItem == nil {
 		var validationErrors []*errors.ValidationError
-		pathItem, validationErrors, foundPath = paths.FindPath(request, v.document)
+		pathItem, validationErrors, foundPath = paths.FindPath(request, v.document, config.WithExistingOpts(v.options))
 		if pathItem == nil || validationErrors != nil {
 			v.errors = validationErrors
 			return false, validationErrors
@@ -29,11 +40,18 @@ func (v *requestBodyValidator) ValidateRequestBody(request *http.Request) (bool,
 		foundPath = v.pathValue
 	}
 
-	operation := helpers.ExtractOperation(request, pathItem)
+	operation := v.operations.ExtractOperation(request, pathItem)
 	if operation == nil {
 		return false, []*errors.ValidationError{errors.OperationNotFound(pathItem, request, request.Method, foundPath)}
 	}
 	if operation.RequestBody == nil {
+		if v.options.RejectUndeclaredRequestBody {
+			// a body that is too large has not been read in full, but it is certainly not empty.
+			if requestBody, withinLimit := readRequestBody(request, v.options.MaxRequestBodySize); !withinLimit ||
+				len(requestBody) > 0 {
+				return false, []*errors.ValidationError{errors.RequestBodyUndeclared(operation, request, foundPath)}
+			}
+		}
 		return true, nil
 	}
 
@@ -43,11 +61,19 @@ func (v *requestBodyValidator) ValidateRequestBody(request *http.Request) (bool,
 	if operation.RequestBody.Required != nil {
 		required = *operation.RequestBody.Required
 	}
+	requestBody, withinLimit := readRequestBody(request, v.options.MaxRequestBodySize)
+	if !withinLimit {
+		return false, []*errors.ValidationError{errors.RequestBodyTooLarge(request, v.options.MaxRequestBodySize)}
+	}
+	hasBody := len(requestBody) > 0
 	if contentType == "" {
 		if !required {
 			// request body is not required, the validation stop there.
 			return true, nil
 		}
+		if !hasBody {
+			return false, []*errors.ValidationError{errors.RequestBodyMissing(operation, request, foundPath)}
+		}
 		return false, []*errors.ValidationError{errors.RequestContentTypeNotFound(operation, request, foundPath)}
 	}
 
@@ -58,9 +84,23 @@ func (v *requestBodyValidator) ValidateRequestBody(request *http.Request) (bool,
 		return false, []*errors.ValidationError{errors.RequestContentTypeNotFound(operation, request, foundPath)}
 	}
 
-	// we currently only support JSON validation for request bodies
-	// this will capture *everything* that contains some form of 'json' in the content type
-	if !strings.Contains(strings.ToLower(contentType), helpers.JSONType) {
+	// no body was sent, which is only a problem if the contract says there must be one.
+	if !hasBody {
+		if !required {
+			return true, nil
+		}
+		return false, []*errors.ValidationError{errors.RequestBodyMissing(operation, request, foundPath)}
+	}
+
+	// we currently only support JSON, XML, form encoded, multipart and plain text validation for request bodies
+	// this will capture *everything* that contains some form of 'json' in the content type, and every
+	// XML media type (such as application/xml, text/xml and application/atom+xml).
+	isForm := strings.ToLower(ct) == helpers.FormURLEncodedType
+	isMultipart := strings.ToLower(ct) == helpers.MultipartFormDataType
+	isXML := strings.HasSuffix(strings.ToLower(ct), helpers.XMLType)
+	isText := strings.ToLower(ct) == helpers.TextPlainType
+	if !isForm && !isMultipart && !isXML && !isText &&
+		!strings.Contains(strings.ToLower(contentType), helpers.JSONType) {
 		return true, nil
 	}
 
@@ -87,8 +127,13 @@ func (v *requestBodyValidator) ValidateRequestBody(request *http.Request) (bool,
 
 		// render the schema inline and perform the intensive work of rendering and converting
 		// this is only performed once per schema and cached in the validator.
-		schema = mediaType.Schema.Schema()
-		renderedInline, _ = schema.RenderInline()
+		var renderError *errors.ValidationError
+		schema, renderedInline, renderError = schema_validation.RenderSchemaProxy(mediaType.Schema)
+		if renderError != nil {
+			validationErrors := []*errors.ValidationError{renderError}
+			errors.PopulateValidationErrors(validationErrors, request, foundPath)
+			return false, validationErrors
+		}
 		renderedJSON, _ = utils.ConvertYAMLtoJSON(renderedInline)
 		v.schemaCache.Store(hash, &schemaCache{
 			schema:         schema,
@@ -98,9 +143,70 @@ func (v *requestBodyValidator) ValidateRequestBody(request *http.Request) (bool,
 	}
 
 	// render the schema, to be used for validation
-	validationSucceeded, validationErrors := ValidateRequestSchema(request, schema, renderedInline, renderedJSON)
+	var validationSucceeded bool
+	var validationErrors []*errors.ValidationError
+	switch {
+	case isForm:
+		validationSucceeded, validationErrors = ValidateFormRequestSchema(request, schema, mediaType.Encoding,
+			renderedInline, renderedJSON, config.WithExistingOpts(v.options))
+	case isMultipart:
+		validationSucceeded, validationErrors = ValidateMultipartRequestSchema(request, schema, mediaType.Encoding,
+			renderedInline, renderedJSON, config.WithExistingOpts(v.options))
+	case isXML:
+		validationSucceeded, validationErrors = ValidateXMLRequestSchema(request, schema, renderedInline, renderedJSON,
+			config.WithExistingOpts(v.options))
+	case isText:
+		validationSucceeded, validationErrors = ValidateTextRequestSchema(request, schema, renderedInline, renderedJSON,
+			config.WithExistingOpts(v.options))
+	default:
+		if v.options.BodyDiscriminator != "" {
+			var discriminatorError *errors.ValidationError
+			schema, renderedInline, renderedJSON, discriminatorError = v.discriminatedRequestSchema(request, operation,
+				foundPath, schema, renderedInline, renderedJSON)
+			if discriminatorError != nil {
+				validationErrors = []*errors.ValidationError{discriminatorError}
+				errors.PopulateValidationErrors(validationErrors, request, foundPath)
+				return false, validationErrors
+			}
+		}
+		validationSucceeded, validationErrors = ValidateRequestSchema(request, schema, renderedInline, renderedJSON,
+			config.WithExistingOpts(v.options))
+	}
 
 	errors.PopulateValidationErrors(validationErrors, request, foundPath)
 
 	return validationSucceeded, validationErrors
 }
+
+// readRequestBody will read the entire request body and then replace it, so it can be re-read later by another
+// player in the chain. The body is read until it ends, so the Content-Length is not needed (a chunked body has none).
+// If limit is greater than zero, no more than limit bytes are read, and false is returned if the body is larger. The
+// body is still replaced, so it can be read in full later.
+func readRequestBody(request *http.Request, limit int64) ([]byte, bool) {
+	if request == nil || request.Body == nil || request.Body == http.NoBody {
+		return nil, true
+	}
+	if limit <= 0 {
+		requestBody, _ := io.ReadAll(request.Body)
+		_ = request.Body.Close()
+		request.Body = io.NopCloser(bytes.NewBuffer(requestBody))
+		return requestBody, true
+	}
+
+	requestBody, _ := io.ReadAll(io.LimitReader(request.Body, limit+1))
+	if int64(len(requestBody)) > limit {
+		// the rest of the body has not been read, so it still follows what has been.
+		request.Body = &replayedBody{Reader: io.MultiReader(bytes.NewReader(requestBody), request.Body),
+			Closer: request.Body}
+		return nil, false
+	}
+	_ = request.Body.Close()
+	request.Body = io.NopCloser(bytes.NewBuffer(requestBody))
+	return requestBody, true
+}
+
+// replayedBody is a request body that has been partially read, the bytes that were read are read again first.
+type replayedBody struct {
+	io.Reader
+	io.Closer
+}
@@ -7,11 +7,19 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"strings"
 	"testing"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -973,9 +981,11 @@ components:
 	assert.False(t, valid)
 	assert.Len(t, errors, 1)
 	assert.Len(t, errors[0].SchemaValidationErrors, 1)
-	assert.Equal(t, "maximum 2 items required, but found 4 items", errors[0].SchemaValidationErrors[0].Reason)
+	assert.Equal(t, "The array has 4 items, however the schema allows a maximum of 2 items (maxItems)",
+		errors[0].SchemaValidationErrors[0].Reason)
 	assert.Equal(t, 2, errors[0].SchemaValidationErrors[0].Line)
-	assert.Equal(t, "maximum 2 items required, but found 4 items", errors[0].SchemaValidationErrors[0].Reason)
+	assert.Equal(t, "The array has 4 items, however the schema allows a maximum of 2 items (maxItems)",
+		errors[0].SchemaValidationErrors[0].Reason)
 	assert.Equal(t, 11, errors[0].SchemaValidationErrors[0].Column)
 }
 
@@ -1032,6 +1042,7 @@ paths:
   /burgers/createBurger:
     post:
       requestBody:
+        required: true
         content:
           application/json:
             schema:
@@ -1067,7 +1078,68 @@ components:
 
 	assert.False(t, valid)
 	assert.Len(t, errors, 1)
+	assert.Equal(t, "POST request body is empty for '/burgers/createBurger'", errors[0].Message)
+	assert.Equal(t, helpers.RequestBodyMissing, errors[0].ValidationSubType)
+	assert.Equal(t, 6, errors[0].SpecLine)
+
+}
+
+func TestValidateBody_MissingBody_NotRequired(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        required: false
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+		http.NoBody)
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, errors := v.ValidateRequestBody(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+}
 
+func TestValidateBody_MissingBody_RequiredNoContentType(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", nil)
+
+	valid, errors := v.ValidateRequestBody(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, helpers.RequestBodyMissing, errors[0].ValidationSubType)
 }
 
 func TestValidateBody_NoBodyNoNothing(t *testing.T) {
@@ -1331,3 +1403,949 @@ func TestValidateBody_SchemaNoType_Issue75(t *testing.T) {
 	assert.Equal(t, "PUT request body is empty for '/path1'", valErrs[0].Message)
 
 }
+
+func TestValidateBody_ReadOnlyProperty(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                id:
+                  type: string
+                  readOnly: true
+                name:
+                  type: string
+                toppings:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      sku:
+                        type: string
+                        readOnly: true
+                      name:
+                        type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	body := map[string]interface{}{
+		"id":       "abc123",
+		"name":     "Big Mac",
+		"toppings": []interface{}{map[string]interface{}{"sku": "ch33s3", "name": "cheese"}},
+	}
+
+	bodyBytes, _ := json.Marshal(body)
+
+	// readOnly properties are permitted by default.
+	v := NewRequestBodyValidator(&m.Model)
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", bytes.NewBuffer(bodyBytes))
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, errors := v.ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	v = NewRequestBodyValidator(&m.Model, config.WithReadOnlyWriteOnlyValidation())
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", bytes.NewBuffer(bodyBytes))
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, errors = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 2)
+	assert.Equal(t, "The property '/id' is readOnly, it must not be sent in a request", errors[0].SchemaValidationErrors[0].Reason)
+	assert.Equal(t, "/properties/id/readOnly", errors[0].SchemaValidationErrors[0].Location)
+	assert.Equal(t, "The property '/toppings/0/sku' is readOnly, it must not be sent in a request", errors[0].SchemaValidationErrors[1].Reason)
+}
+
+func TestValidateBody_FormURLEncoded(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /login:
+    post:
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [username, password]
+              properties:
+                username:
+                  type: string
+                password:
+                  type: string
+                  minLength: 8
+                remember:
+                  type: boolean
+                attempts:
+                  type: integer
+                scopes:
+                  type: array
+                  items:
+                    type: string
+                    enum: [read, write]
+                tags:
+                  type: array
+                  items:
+                    type: integer
+            encoding:
+              tags:
+                style: form
+                explode: false`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewRequestBodyValidator(&m.Model)
+
+	body := "username=dave&password=pizza1234&remember=true&attempts=3&scopes=read&scopes=write&tags=1,2,3"
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/login", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	valid, errors := v.ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	body = "username=dave&password=pizza&attempts=three&scopes=read&scopes=eat&tags=1,b"
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/login", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	valid, errors = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 4)
+}
+
+func TestValidateBody_FormURLEncodedMissingRequired(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /login:
+    post:
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [username]
+              properties:
+                username:
+                  type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewRequestBodyValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/login", strings.NewReader("password=pizza"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	valid, errors := v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "missing properties: 'username'", errors[0].SchemaValidationErrors[0].Reason)
+}
+
+func TestValidateBody_MultipartFormData(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}/photo:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [caption, photo]
+              properties:
+                caption:
+                  type: string
+                  maxLength: 20
+                rating:
+                  type: integer
+                photo:
+                  type: string
+                  format: binary
+            encoding:
+              photo:
+                contentType: image/png, image/jpeg`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewRequestBodyValidator(&m.Model)
+
+	buildRequest := func(caption, rating, photoType string) *http.Request {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		_ = writer.WriteField("caption", caption)
+		_ = writer.WriteField("rating", rating)
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", `form-data; name="photo"; filename="burger.png"`)
+		header.Set("Content-Type", photoType)
+		part, _ := writer.CreatePart(header)
+		_, _ = part.Write([]byte("not really a png"))
+		_ = writer.Close()
+
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/123/photo", &body)
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+		return request
+	}
+
+	valid, errors := v.ValidateRequestBody(buildRequest("tasty", "5", "image/png"))
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = v.ValidateRequestBody(buildRequest("a very tasty burger indeed", "five", "image/jpeg"))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 2)
+
+	valid, errors = v.ValidateRequestBody(buildRequest("tasty", "5", "image/gif"))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "POST request body part 'photo' content type 'image/gif' is not allowed", errors[0].Message)
+	assert.Equal(t, "/burgers/{burgerId}/photo", errors[0].SpecPath)
+}
+
+func TestValidateBody_MultipartFormDataMissingBoundary(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/photo:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewRequestBodyValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/photo", strings.NewReader("caption=tasty"))
+	request.Header.Set("Content-Type", "multipart/form-data")
+
+	valid, errors := v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "The request body cannot be decoded: multipart boundary is missing from the content type", errors[0].Reason)
+}
+
+func TestValidateBody_AdditionalPropertiesFalse(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              additionalProperties: false
+              properties:
+                name:
+                  type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewRequestBodyValidator(&m.Model)
+
+	body := map[string]interface{}{
+		"name":    "Big Mac",
+		"pickles": true,
+		"cheese":  "cheddar",
+	}
+
+	bodyBytes, _ := json.Marshal(body)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", bytes.NewBuffer(bodyBytes))
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, errors := v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 2)
+	assert.Equal(t, "/cheese", errors[0].SchemaValidationErrors[0].InstanceLocation)
+	assert.Equal(t, "/additionalProperties", errors[0].SchemaValidationErrors[0].Location)
+	assert.Equal(t, "/pickles", errors[0].SchemaValidationErrors[1].InstanceLocation)
+	assert.Equal(t, 2, errors[0].SchemaValidationErrors[1].Line)
+}
+
+func TestValidateBody_PatchValidation(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    patch:
+      requestBody:
+        content:
+          application/merge-patch+json:
+            schema:
+              $ref: '#/components/schemas/Burger'
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Burger'
+components:
+  schemas:
+    Burger:
+      type: object
+      required: [name, patties]
+      properties:
+        name:
+          type: string
+        patties:
+          type: integer
+          maximum: 3
+        sauce:
+          type: object
+          required: [flavor]
+          properties:
+            flavor:
+              type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	partial, _ := json.Marshal(map[string]interface{}{"patties": 2, "sauce": map[string]interface{}{}})
+
+	buildRequest := func(method, contentType string, body []byte) *http.Request {
+		request, _ := http.NewRequest(method, "https://things.com/burgers/123", bytes.NewBuffer(body))
+		request.Header.Set("Content-Type", contentType)
+		return request
+	}
+
+	// strict mode enforces required properties.
+	v := NewRequestBodyValidator(&m.Model)
+	valid, errors := v.ValidateRequestBody(buildRequest(http.MethodPatch, "application/merge-patch+json", partial))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 2)
+
+	// patch mode accepts the partial object.
+	v = NewRequestBodyValidator(&m.Model, config.WithPatchValidation())
+	valid, errors = v.ValidateRequestBody(buildRequest(http.MethodPatch, "application/merge-patch+json", partial))
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// the properties that are present are still validated.
+	invalid, _ := json.Marshal(map[string]interface{}{"patties": 5})
+	valid, errors = v.ValidateRequestBody(buildRequest(http.MethodPatch, "application/merge-patch+json", invalid))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "must be <= 3 but found 5", errors[0].SchemaValidationErrors[0].Reason)
+
+	// other methods remain strict.
+	valid, errors = v.ValidateRequestBody(buildRequest(http.MethodPost, "application/json", partial))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+
+	// unless required is relaxed for every request.
+	v = NewRequestBodyValidator(&m.Model, config.WithRelaxedRequired())
+	valid, errors = v.ValidateRequestBody(buildRequest(http.MethodPost, "application/json", partial))
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestValidateBody_CircularReference(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /menus:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Menu'
+components:
+  schemas:
+    Menu:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        sections:
+          type: array
+          items:
+            $ref: '#/components/schemas/Menu'`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewRequestBodyValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/menus",
+		bytes.NewBufferString(`{"name": "lunch", "sections": [{"name": "burgers", "sections": [{"name": 1}]}]}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, errors := v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "expected string, but got number", errors[0].SchemaValidationErrors[0].Reason)
+	assert.Equal(t, "/sections/0/sections/0/name", errors[0].SchemaValidationErrors[0].InstanceLocation)
+}
+
+func TestValidateAcceptHeader(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                type: object
+            text/csv:
+              schema:
+                type: string
+        default:
+          content:
+            application/problem+json:
+              schema:
+                type: object
+    delete:
+      responses:
+        '204':
+          description: deleted`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	pathItem := m.Model.Paths.PathItems.GetOrZero("/burgers")
+
+	for accept, acceptable := range map[string]bool{
+		"":                                true,
+		"application/json":                true,
+		"text/*":                          true,
+		"*/*":                             true,
+		"*":                               true,
+		"text/html, application/*;q=0.5":  true,
+		"TEXT/CSV; charset=utf-8":         true,
+		"text/html":                       false,
+		"image/*, text/plain":             false,
+		"application/*;q=0, text/csv;q=0": false,
+		"*/*;q=0":                         false,
+		"*/*, application/json;q=0, text/csv;q=0": true, // application/problem+json is still acceptable.
+	} {
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+		request.Header.Set(helpers.AcceptHeader, accept)
+
+		valid, errors := ValidateAcceptHeader(request, pathItem.Get, "/burgers")
+		assert.Equal(t, acceptable, valid, accept)
+		if acceptable {
+			assert.Len(t, errors, 0, accept)
+			continue
+		}
+		assert.Len(t, errors, 1, accept)
+		assert.Equal(t, helpers.RequestNotAcceptable, errors[0].ValidationSubType)
+		assert.Equal(t, "Send an 'Accept' header that allows one of the 3 response content types for this operation: "+
+			"application/json, text/csv, application/problem+json", errors[0].HowToFix)
+		assert.Equal(t, 5, errors[0].SpecLine)
+	}
+
+	// no response content, so there is nothing to negotiate.
+	request, _ := http.NewRequest(http.MethodDelete, "https://things.com/burgers", nil)
+	request.Header.Set(helpers.AcceptHeader, "text/html")
+	valid, errors := ValidateAcceptHeader(request, pathItem.Delete, "/burgers")
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestValidateBody_XML(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Burger'
+          application/xml:
+            schema:
+              $ref: '#/components/schemas/Burger'
+components:
+  schemas:
+    Burger:
+      type: object
+      required: [name, patties]
+      xml:
+        name: burger
+      properties:
+        id:
+          type: integer
+          xml:
+            attribute: true
+        name:
+          type: string
+        patties:
+          type: integer
+          maximum: 3
+        vegetarian:
+          type: boolean
+        toppings:
+          type: array
+          xml:
+            wrapped: true
+          items:
+            type: string
+            enum: [cheese, pickles]
+            xml:
+              name: topping
+        sauces:
+          type: array
+          items:
+            type: string
+            xml:
+              name: sauce`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewRequestBodyValidator(&m.Model)
+
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<burger id="12">
+  <name>Big Mac</name>
+  <patties>2</patties>
+  <vegetarian>false</vegetarian>
+  <toppings>
+    <topping>cheese</topping>
+    <topping>pickles</topping>
+  </toppings>
+  <sauce>ketchup</sauce>
+  <sauce>mustard</sauce>
+</burger>`
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/xml")
+
+	valid, errors := v.ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	body = `<burger id="twelve"><patties>5</patties><toppings><topping>onions</topping></toppings></burger>`
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	valid, errors = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+
+	var locations []string
+	for _, failure := range errors[0].SchemaValidationErrors {
+		locations = append(locations, failure.InstanceLocation)
+	}
+	assert.ElementsMatch(t, []string{"", "/id", "/patties", "/toppings/0"}, locations)
+
+	// XML that is not well-formed cannot be decoded.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+		strings.NewReader(`<burger><name>Big Mac</burger>`))
+	request.Header.Set("Content-Type", "application/xml")
+
+	valid, errors = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Reason, "The request body cannot be decoded")
+}
+
+func TestDecodeXML(t *testing.T) {
+	// without a schema, elements with children are objects, and repeated elements are arrays.
+	decoded, err := DecodeXML([]byte(`<order xmlns="urn:orders"><item>fries</item><item>shake</item><total>12</total></order>`), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"item": []any{"fries", "shake"}, "total": "12"}, decoded)
+
+	_, err = DecodeXML([]byte(`<a/><b/>`), nil)
+	assert.EqualError(t, err, "XML document has more than one root element")
+
+	_, err = DecodeXML([]byte(`   `), nil)
+	assert.EqualError(t, err, "XML document has no root element")
+}
+
+func TestValidateBody_ArrayConstraints(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/rateBurgers:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: array
+              minItems: 2
+              maxItems: 4
+              uniqueItems: true
+              items:
+                type: integer`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model)
+
+	validate := func(body string) *liberrors.SchemaValidationFailure {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/rateBurgers",
+			strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		valid, validationErrors := v.ValidateRequestBody(request)
+		assert.False(t, valid)
+		assert.Len(t, validationErrors, 1)
+		assert.Len(t, validationErrors[0].SchemaValidationErrors, 1)
+		return validationErrors[0].SchemaValidationErrors[0]
+	}
+
+	failure := validate(`[5]`)
+	assert.Equal(t, "The array has 1 items, however the schema allows a minimum of 2 items (minItems)", failure.Reason)
+	assert.Equal(t, "/minItems", failure.Location)
+	assert.Equal(t, 2, failure.Line)
+
+	failure = validate(`[1, 2, 3, 4, 5]`)
+	assert.Equal(t, "The array has 5 items, however the schema allows a maximum of 4 items (maxItems)", failure.Reason)
+	assert.Equal(t, "/maxItems", failure.Location)
+	assert.Equal(t, 3, failure.Line)
+
+	failure = validate(`[1, 2, 1]`)
+	assert.Equal(t, "The array must only contain unique items (uniqueItems), however the items at index 0 and 2 are equal",
+		failure.Reason)
+	assert.Equal(t, "/uniqueItems", failure.Location)
+	assert.Equal(t, 4, failure.Line)
+}
+
+func TestValidateBody_Discriminator(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /graphql:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              oneOf:
+                - $ref: '#/components/schemas/GetBurger'
+                - $ref: '#/components/schemas/CreateBurger'
+              discriminator:
+                propertyName: operationName
+                mapping:
+                  makeBurger: '#/components/schemas/CreateBurger'
+components:
+  schemas:
+    GetBurger:
+      type: object
+      required: [operationName, variables]
+      properties:
+        operationName:
+          const: getBurger
+        variables:
+          type: object
+          required: [id]
+          properties:
+            id:
+              type: integer
+    CreateBurger:
+      type: object
+      required: [operationName, variables]
+      properties:
+        operationName:
+          enum: [createBurger, makeBurger]
+        variables:
+          type: object
+          required: [name]
+          properties:
+            name:
+              type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	v := NewRequestBodyValidator(&m.Model, config.WithBodyDiscriminator("/operationName"))
+
+	validate := func(body string) (bool, []*liberrors.ValidationError) {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/graphql", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		return v.ValidateRequestBody(request)
+	}
+
+	valid, errors := validate(`{"operationName": "getBurger", "variables": {"id": 1}}`)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// only the selected schema is validated against, so the errors are about that schema alone.
+	valid, errors = validate(`{"operationName": "createBurger", "variables": {"id": 1}}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "missing properties: 'name'", errors[0].SchemaValidationErrors[0].Reason)
+
+	// the discriminator mapping selects a schema too.
+	valid, errors = validate(`{"operationName": "makeBurger", "variables": {"name": "big mac"}}`)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = validate(`{"operationName": "eatBurger", "variables": {}}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "discriminator", errors[0].ValidationSubType)
+	assert.Equal(t, "The POST request body has a value of 'eatBurger' at '/operationName', however no request "+
+		"body schema is selected by that value", errors[0].Reason)
+
+	valid, errors = validate(`{"variables": {}}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "The POST request body has no value at '/operationName', so a schema cannot be selected for it",
+		errors[0].Reason)
+
+	// a selected schema that cannot be rendered is reported, rather than validated against. The body schema is
+	// rendered (and cached) first, so only the selected schema fails to render.
+	v = NewRequestBodyValidator(&m.Model, config.WithBodyDiscriminator("/operationName"))
+	valid, _ = validate(`{"operationName": "eatBurger", "variables": {}}`)
+	assert.False(t, valid)
+	body := m.Model.Paths.PathItems.GetOrZero("/graphql").Post.RequestBody.Content.GetOrZero("application/json")
+	body.Schema.Schema().OneOf[0].Schema().Properties.Set("fries", base.CreateSchemaProxyRef("#/components/schemas/Fries"))
+	valid, errors = validate(`{"operationName": "getBurger", "variables": {"id": 1}}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, helpers.SchemaRenderFailed, errors[0].ValidationSubType)
+	assert.Equal(t, "/graphql", errors[0].SpecPath)
+}
+
+func TestValidateBody_MaxRequestBodySize(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model, config.WithMaxRequestBodySize(32))
+
+	// a chunked body has no content length, so it is read until it ends.
+	body := `{"name":"Big Mac"}`
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+		io.NopCloser(strings.NewReader(body)))
+	request.Header.Set("Content-Type", "application/json")
+	request.ContentLength = -1
+	request.TransferEncoding = []string{"chunked"}
+
+	valid, errors := v.ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	large := `{"name":"` + strings.Repeat("a", 100) + `"}`
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+		io.NopCloser(strings.NewReader(large)))
+	request.Header.Set("Content-Type", "application/json")
+	request.ContentLength = -1
+
+	valid, errors = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, helpers.RequestBodyTooLarge, errors[0].ValidationSubType)
+	assert.Equal(t, "POST request body for '/burgers/createBurger' is too large", errors[0].Message)
+
+	// the body can still be read in full.
+	read, _ := io.ReadAll(request.Body)
+	assert.Equal(t, large, string(read))
+}
+
+func TestValidateBody_DefaultMaxRequestBodySize(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	large := `"` + strings.Repeat("a", int(config.DefaultMaxRequestBodySize)) + `"`
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+		strings.NewReader(large))
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, errors := NewRequestBodyValidator(&m.Model).ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, helpers.RequestBodyTooLarge, errors[0].ValidationSubType)
+
+	// the limit can be removed.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+		strings.NewReader(large))
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, errors = NewRequestBodyValidator(&m.Model, config.WithMaxRequestBodySize(0)).ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestValidateBody_TextPlain(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/review:
+    post:
+      requestBody:
+        content:
+          text/plain:
+            schema:
+              type: string
+              maxLength: 20
+              pattern: '^[A-Za-z ]+$'`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/review",
+		strings.NewReader("Tasty burger"))
+	request.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	valid, errors := v.ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/burgers/review",
+		strings.NewReader("The tastiest burger I have ever eaten"))
+	request.Header.Set("Content-Type", "text/plain")
+
+	valid, errors = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Contains(t, errors[0].SchemaValidationErrors[0].Reason, "length must be <= 20")
+}
+
+func TestValidateBody_UndeclaredRequestBody(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{id}:
+    delete:
+      operationId: deleteBurger`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodDelete, "https://things.com/burgers/123",
+		strings.NewReader(`{"reason":"cold"}`))
+
+	// bodies are tolerated by default.
+	valid, errors := NewRequestBodyValidator(&m.Model).ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	v := NewRequestBodyValidator(&m.Model, config.WithUndeclaredRequestBodyRejection())
+	valid, errors = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, helpers.RequestBodyUndeclared, errors[0].ValidationSubType)
+	assert.Equal(t, "DELETE request body for '/burgers/123' is not defined by the operation", errors[0].Message)
+	assert.Equal(t, 4, errors[0].SpecLine)
+
+	request, _ = http.NewRequest(http.MethodDelete, "https://things.com/burgers/123", nil)
+	valid, errors = v.ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestValidateBody_ConnectWithPathItem(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /tunnels:
+    connect:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [host]
+              properties:
+                host:
+                  type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	// the path is not looked up, so the CONNECT operation is built from the path item that is set.
+	v := NewRequestBodyValidator(&m.Model)
+	v.SetPathItem(m.Model.Paths.PathItems.GetOrZero("/tunnels"), "/tunnels")
+
+	request, _ := http.NewRequest(http.MethodConnect, "https://things.com/tunnels",
+		strings.NewReader(`{"host":"things.com"}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, errors := v.ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodConnect, "https://things.com/tunnels", strings.NewReader(`{}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, errors = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "CONNECT request body for '/tunnels' failed to validate schema", errors[0].Message)
+}
+
+func TestValidateBody_UnresolvedSchemaReference(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                fries:
+                  $ref: '#/components/schemas/Fries'`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+		strings.NewReader(`{"name":"chips","fries":12}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	// the fries would be left out of the rendered schema, so the body is not validated against it.
+	valid, errors := NewRequestBodyValidator(&m.Model).ValidateRequestBody(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, helpers.SchemaRenderFailed, errors[0].ValidationSubType)
+	assert.Contains(t, errors[0].Reason, "cannot find reference #/components/schemas/Fries")
+	assert.Equal(t, 9, errors[0].SpecLine)
+	assert.Equal(t, "/burgers/createBurger", errors[0].SpecPath)
+	assert.Equal(t, helpers.RequestDirection, errors[0].Direction)
+}
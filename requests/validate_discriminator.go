@@ -0,0 +1,170 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package requests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/schema_validation"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// discriminatedRequestSchema selects the schema (and its renderings) that a JSON request body is validated against,
+// when a body discriminator is configured (see config.WithBodyDiscriminator). A schema without a oneOf or anyOf, or a
+// body that cannot be decoded, is validated as it is. If the discriminator of the body does not select a schema, or
+// the selected schema cannot be rendered, an error is returned instead.
+func (v *requestBodyValidator) discriminatedRequestSchema(request *http.Request, operation *v3.Operation,
+	foundPath string, schema *base.Schema, renderedInline, renderedJSON []byte) (*base.Schema, []byte, []byte,
+	*errors.ValidationError) {
+	if len(schema.OneOf) == 0 && len(schema.AnyOf) == 0 {
+		return schema, renderedInline, renderedJSON, nil
+	}
+	var decoded any
+	requestBody, _ := readRequestBody(request, v.options.MaxRequestBodySize)
+	if helpers.UnmarshalJSON(requestBody, &decoded, v.options.UseJSONNumber) != nil {
+		return schema, renderedInline, renderedJSON, nil
+	}
+	selected, value, found := DiscriminatedSchema(schema, decoded, v.options.BodyDiscriminator)
+	if selected == nil {
+		return nil, nil, nil, errors.RequestBodyDiscriminatorMismatch(operation, request, foundPath,
+			v.options.BodyDiscriminator, value, found)
+	}
+
+	// the selected schema is rendered once, and cached in the validator like the body schema.
+	hash := selected.GoLow().Hash()
+	if cacheHit, ch := v.schemaCache.Load(hash); ch {
+		return selected, cacheHit.(*schemaCache).renderedInline, cacheHit.(*schemaCache).renderedJSON, nil
+	}
+	var renderError *errors.ValidationError
+	if renderedInline, renderError = schema_validation.RenderSchema(selected); renderError != nil {
+		return nil, nil, nil, renderError
+	}
+	renderedJSON, _ = utils.ConvertYAMLtoJSON(renderedInline)
+	v.schemaCache.Store(hash, &schemaCache{
+		schema:         selected,
+		renderedInline: renderedInline,
+		renderedJSON:   renderedJSON,
+	})
+	return selected, renderedInline, renderedJSON, nil
+}
+
+// DiscriminatedSchema returns the schema from the oneOf (or anyOf) of a schema that a decoded payload is discriminated
+// into, by the value at a JSON pointer (such as /operationName) of the payload. If the discriminator mapping of the
+// schema maps the value, the mapped schema is selected. Otherwise, the first schema that defines the property at the
+// pointer with a const (or enum) holding the value is selected.
+//
+// The value at the pointer is returned with the schema, found is false if the payload has no (scalar) value at the
+// pointer. If no schema is selected by the value, the schema is nil.
+func DiscriminatedSchema(schema *base.Schema, payload any, pointer string) (selected *base.Schema, value string,
+	found bool) {
+	tokens := jsonPointerTokens(pointer)
+	raw, found := payloadValue(payload, tokens)
+	if !found {
+		return nil, "", false
+	}
+	value = fmt.Sprint(raw)
+
+	candidates := append(append([]*base.SchemaProxy{}, schema.OneOf...), schema.AnyOf...)
+	if schema.Discriminator != nil && schema.Discriminator.Mapping != nil {
+		if ref, ok := schema.Discriminator.Mapping.Get(value); ok {
+			for _, candidate := range candidates {
+				if candidate != nil && candidate.IsReference() && candidate.GetReference() == ref {
+					return candidate.Schema(), value, true
+				}
+			}
+		}
+	}
+	for _, candidate := range candidates {
+		if candidate == nil {
+			continue
+		}
+		if sch := candidate.Schema(); sch != nil && schemaAllowsOnly(schemaAtPointer(sch, tokens), value) {
+			return sch, value, true
+		}
+	}
+	return nil, value, true
+}
+
+// jsonPointerTokens splits a JSON pointer into its unescaped reference tokens.
+func jsonPointerTokens(pointer string) []string {
+	if pointer == "" || pointer == "/" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+// payloadValue returns the scalar value at the reference tokens of a decoded payload.
+func payloadValue(payload any, tokens []string) (any, bool) {
+	for _, token := range tokens {
+		switch p := payload.(type) {
+		case map[string]any:
+			v, ok := p[token]
+			if !ok {
+				return nil, false
+			}
+			payload = v
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(p) {
+				return nil, false
+			}
+			payload = p[i]
+		default:
+			return nil, false
+		}
+	}
+	switch payload.(type) {
+	case map[string]any, []any, nil:
+		return nil, false
+	}
+	return payload, true
+}
+
+// schemaAtPointer returns the schema of the property at the reference tokens of a schema, following properties for
+// objects, and items for arrays. nil is returned if the schema does not define the property.
+func schemaAtPointer(sch *base.Schema, tokens []string) *base.Schema {
+	for _, token := range tokens {
+		if sch == nil {
+			return nil
+		}
+		if sch.Properties != nil && sch.Properties.GetOrZero(token) != nil {
+			sch = sch.Properties.GetOrZero(token).Schema()
+			continue
+		}
+		if _, err := strconv.Atoi(token); err == nil && sch.Items != nil && sch.Items.IsA() && sch.Items.A != nil {
+			sch = sch.Items.A.Schema()
+			continue
+		}
+		return nil
+	}
+	return sch
+}
+
+// schemaAllowsOnly returns true if a schema restricts its values with a const, or an enum, that holds the value.
+func schemaAllowsOnly(sch *base.Schema, value string) bool {
+	if sch == nil {
+		return false
+	}
+	if sch.Const != nil {
+		return sch.Const.Kind == yaml.ScalarNode && sch.Const.Value == value
+	}
+	for _, enumValue := range sch.Enum {
+		if enumValue != nil && enumValue.Kind == yaml.ScalarNode && enumValue.Value == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package requests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// ValidateFormRequestSchema will validate a http.Request pointer with an application/x-www-form-urlencoded body
+// against a schema. The body is decoded into an object using the encoding of the media type, with repeated keys
+// (or delimited values) becoming arrays, and values cast to the type of the property schema.
+// If validation fails, it will return a list of validation errors as the second return value.
+func ValidateFormRequestSchema(
+	request *http.Request,
+	schema *base.Schema,
+	encoding *orderedmap.Map[string, *v3.Encoding],
+	renderedSchema,
+	jsonSchema []byte,
+	opts ...config.Option) (bool, []*errors.ValidationError) {
+
+	options := config.NewValidationOptions(opts...)
+
+	requestBody, withinLimit := readRequestBody(request, options.MaxRequestBodySize)
+	if !withinLimit {
+		return false, []*errors.ValidationError{errors.RequestBodyTooLarge(request, options.MaxRequestBodySize)}
+	}
+
+	var decodedObj interface{}
+	if len(requestBody) > 0 {
+		values, err := url.ParseQuery(string(requestBody))
+		if err != nil {
+			return false, []*errors.ValidationError{requestBodyDecodeError(request, renderedSchema, requestBody, err)}
+		}
+		decodedObj = DecodeFormValues(values, schema, encoding)
+	}
+
+	return validateDecodedRequestBody(request, schema, renderedSchema, jsonSchema, requestBody, decodedObj, options)
+}
+
+// requestBodyDecodeError creates a validation error for a request body that cannot be decoded.
+func requestBodyDecodeError(request *http.Request, renderedSchema, requestBody []byte, err error) *errors.ValidationError {
+	violation := &errors.SchemaValidationFailure{
+		Reason:          err.Error(),
+		Location:        "unavailable",
+		ReferenceSchema: string(renderedSchema),
+		ReferenceObject: string(requestBody),
+	}
+	return &errors.ValidationError{
+		ValidationType:    helpers.RequestBodyValidation,
+		ValidationSubType: helpers.Schema,
+		Message: fmt.Sprintf("%s request body for '%s' failed to validate schema",
+			request.Method, request.URL.Path),
+		Reason:                 fmt.Sprintf("The request body cannot be decoded: %s", err.Error()),
+		SpecLine:               1,
+		SpecCol:                0,
+		SchemaValidationErrors: []*errors.SchemaValidationFailure{violation},
+		HowToFix:               errors.HowToFixInvalidSchema,
+		Context:                string(renderedSchema), // attach the rendered schema to the error
+	}
+}
+
+// DecodeFormValues will decode form values into an object that can be validated against a schema.
+//
+// Each value is cast to the type of its property schema. Properties with an array schema are built from every value of
+// the repeated key, or from a single delimited value, if the encoding for the property does not explode. Properties
+// with an encoding contentType of JSON are decoded as JSON. Keys that repeat for a non-array property become arrays,
+// so the schema can reject them.
+func DecodeFormValues(values url.Values, schema *base.Schema, encoding *orderedmap.Map[string, *v3.Encoding]) map[string]any {
+	return decodeFormValues(values, schema, encoding, true)
+}
+
+// decodeFormValues decodes form values, the style and explode of the encoding are only applied when useStyle is true,
+// as they are ignored for any media type other than application/x-www-form-urlencoded.
+func decodeFormValues(values url.Values, schema *base.Schema, encoding *orderedmap.Map[string, *v3.Encoding], useStyle bool) map[string]any {
+	decoded := make(map[string]any, len(values))
+	for key, items := range values {
+		var propSchema *base.Schema
+		if schema != nil && schema.Properties != nil {
+			if proxy, ok := schema.Properties.Get(key); ok && proxy != nil {
+				propSchema = proxy.Schema()
+			}
+		}
+		var enc *v3.Encoding
+		if encoding != nil {
+			enc, _ = encoding.Get(key)
+		}
+
+		// encoded values (such as JSON objects) are decoded as they are.
+		if enc != nil && strings.Contains(strings.ToLower(enc.ContentType), helpers.JSONType) {
+			var parsed []any
+			for _, item := range items {
+				var v any
+				if err := json.Unmarshal([]byte(item), &v); err != nil {
+					v = item
+				}
+				parsed = append(parsed, v)
+			}
+			decoded[key] = collapseFormValues(parsed, propSchema)
+			continue
+		}
+
+		if propSchema != nil && slices.Contains(propSchema.Type, helpers.Array) {
+			// form style explodes by default, so arrays are sent as repeated keys, unless configured otherwise.
+			if useStyle && enc != nil && ((enc.Explode != nil && !*enc.Explode) ||
+				enc.Style == helpers.SpaceDelimited || enc.Style == helpers.PipeDelimited) {
+				var exploded []string
+				for _, item := range items {
+					exploded = append(exploded, helpers.ExplodeQueryValue(item, enc.Style)...)
+				}
+				items = exploded
+			}
+			var itemSchema *base.Schema
+			if propSchema.Items != nil && propSchema.Items.IsA() {
+				itemSchema = propSchema.Items.A.Schema()
+			}
+			parsed := make([]any, 0, len(items))
+			for _, item := range items {
+				parsed = append(parsed, castFormValue(item, itemSchema))
+			}
+			decoded[key] = parsed
+			continue
+		}
+
+		parsed := make([]any, 0, len(items))
+		for _, item := range items {
+			parsed = append(parsed, castFormValue(item, propSchema))
+		}
+		decoded[key] = collapseFormValues(parsed, propSchema)
+	}
+	return decoded
+}
+
+// collapseFormValues will return the only value of a key that was sent once, or all values if it was repeated or
+// the property is an array.
+func collapseFormValues(values []any, sch *base.Schema) any {
+	if len(values) == 1 && (sch == nil || !slices.Contains(sch.Type, helpers.Array)) {
+		return values[0]
+	}
+	return values
+}
+
+// castFormValue will cast a form value into the type of a schema, so it can be validated. If the value cannot be
+// cast, it is returned as a string, so the schema can report the type mismatch.
+func castFormValue(value string, sch *base.Schema) any {
+	if sch == nil {
+		return value
+	}
+	for _, typ := range sch.Type {
+		switch typ {
+		case helpers.Integer:
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return i
+			}
+		case helpers.Number:
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				return f
+			}
+		case helpers.Boolean:
+			if value == "true" || value == "false" {
+				return value == "true"
+			}
+		}
+	}
+	return value
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package requests
+
+import (
+	"bytes"
+	stdError "errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// ValidateMultipartRequestSchema will validate a http.Request pointer with a multipart/form-data body against a
+// schema. The parts are read using the boundary of the request Content-Type, and each field is validated against its
+// property schema. File parts are validated as strings, and if the encoding of a file part declares a contentType, the
+// Content-Type of the part must match it.
+// If validation fails, it will return a list of validation errors as the second return value.
+func ValidateMultipartRequestSchema(
+	request *http.Request,
+	schema *base.Schema,
+	encoding *orderedmap.Map[string, *v3.Encoding],
+	renderedSchema,
+	jsonSchema []byte,
+	opts ...config.Option) (bool, []*errors.ValidationError) {
+
+	options := config.NewValidationOptions(opts...)
+	var validationErrors []*errors.ValidationError
+
+	requestBody, withinLimit := readRequestBody(request, options.MaxRequestBodySize)
+	if !withinLimit {
+		return false, []*errors.ValidationError{errors.RequestBodyTooLarge(request, options.MaxRequestBodySize)}
+	}
+
+	var decodedObj interface{}
+	if len(requestBody) > 0 {
+		_, _, boundary := helpers.ExtractContentType(request.Header.Get(helpers.ContentTypeHeader))
+		boundary = strings.Trim(boundary, `"`)
+		if boundary == "" {
+			return false, []*errors.ValidationError{requestBodyDecodeError(request, renderedSchema, requestBody,
+				stdError.New("multipart boundary is missing from the content type"))}
+		}
+
+		values := make(url.Values)
+		reader := multipart.NewReader(bytes.NewReader(requestBody), boundary)
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return false, []*errors.ValidationError{requestBodyDecodeError(request, renderedSchema, requestBody, err)}
+			}
+			content, err := io.ReadAll(part)
+			if err != nil {
+				return false, []*errors.ValidationError{requestBodyDecodeError(request, renderedSchema, requestBody, err)}
+			}
+			name := part.FormName()
+			values.Add(name, string(content))
+
+			// check the content type of file parts, against the content type declared by the encoding.
+			if part.FileName() != "" && encoding != nil {
+				if enc, ok := encoding.Get(name); ok && enc != nil && enc.ContentType != "" {
+					partContentType := part.Header.Get(helpers.ContentTypeHeader)
+					if partContentType == "" {
+						partContentType = "application/octet-stream"
+					}
+					if !partContentTypeAllowed(partContentType, enc.ContentType) {
+						validationErrors = append(validationErrors,
+							errors.RequestPartContentTypeInvalid(enc, request, name, partContentType))
+					}
+				}
+			}
+		}
+		decodedObj = decodeFormValues(values, schema, encoding, false)
+	}
+
+	_, schemaErrors := validateDecodedRequestBody(request, schema, renderedSchema, jsonSchema, requestBody, decodedObj, options)
+	validationErrors = append(validationErrors, schemaErrors...)
+	if len(validationErrors) > 0 {
+		return false, validationErrors
+	}
+	return true, nil
+}
+
+// partContentTypeAllowed checks a part content type against a comma separated list of allowed content types, which
+// may contain wildcards, such as 'image/*'.
+func partContentTypeAllowed(partContentType, allowed string) bool {
+	ct, _, _ := helpers.ExtractContentType(strings.ToLower(partContentType))
+	for _, a := range strings.Split(strings.ToLower(allowed), helpers.Comma) {
+		a = strings.TrimSpace(a)
+		if a == ct || a == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok && strings.HasPrefix(ct, prefix+helpers.Slash) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,16 +4,15 @@
 package requests
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/schema_validation"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/santhosh-tekuri/jsonschema/v5"
 	"gopkg.in/yaml.v3"
-	"io"
 	"net/http"
 	"reflect"
 	"regexp"
@@ -25,28 +24,27 @@ var instanceLocationRegex = regexp.MustCompile(`^/(\d+)`)
 
 // ValidateRequestSchema will validate a http.Request pointer against a schema.
 // If validation fails, it will return a list of validation errors as the second return value.
+// Options can be supplied, for example config.WithReadOnlyWriteOnlyValidation will reject readOnly properties, and
+// config.WithPatchValidation will accept partial payloads for PATCH requests.
 func ValidateRequestSchema(
 	request *http.Request,
 	schema *base.Schema,
 	renderedSchema,
-	jsonSchema []byte) (bool, []*errors.ValidationError) {
+	jsonSchema []byte,
+	opts ...config.Option) (bool, []*errors.ValidationError) {
 
+	options := config.NewValidationOptions(opts...)
 	var validationErrors []*errors.ValidationError
 
-	var requestBody []byte
-	if request != nil && request.Body != nil {
-		requestBody, _ = io.ReadAll(request.Body)
-
-		// close the request body, so it can be re-read later by another player in the chain
-		_ = request.Body.Close()
-		request.Body = io.NopCloser(bytes.NewBuffer(requestBody))
-
+	requestBody, withinLimit := readRequestBody(request, options.MaxRequestBodySize)
+	if !withinLimit {
+		return false, []*errors.ValidationError{errors.RequestBodyTooLarge(request, options.MaxRequestBodySize)}
 	}
 
 	var decodedObj interface{}
 
 	if len(requestBody) > 0 {
-		err := json.Unmarshal(requestBody, &decodedObj)
+		err := helpers.UnmarshalJSON(requestBody, &decodedObj, options.UseJSONNumber)
 
 		if err != nil {
 			// cannot decode the request body, so it's not valid
@@ -72,6 +70,22 @@ func ValidateRequestSchema(
 		}
 	}
 
+	return validateDecodedRequestBody(request, schema, renderedSchema, jsonSchema, requestBody, decodedObj, options)
+}
+
+// validateDecodedRequestBody will validate a request body that has already been decoded into an object, against a
+// schema. The raw request body is used to decide if a body was sent, and is attached to any validation failures.
+func validateDecodedRequestBody(
+	request *http.Request,
+	schema *base.Schema,
+	renderedSchema,
+	jsonSchema,
+	requestBody []byte,
+	decodedObj any,
+	options *config.ValidationOptions) (bool, []*errors.ValidationError) {
+
+	var validationErrors []*errors.ValidationError
+
 	// no request body? but we do have a schema?
 	if len(requestBody) <= 0 && len(jsonSchema) > 0 {
 
@@ -106,10 +120,20 @@ func ValidateRequestSchema(
 		return false, validationErrors
 	}
 
+	jsonSchema = schema_validation.ResolveCircularReferences(schema, jsonSchema)
+
+	// partial payloads are only validated against the properties they contain.
+	if options.RelaxRequired || (options.RelaxRequiredForPatch && request.Method == http.MethodPatch) {
+		jsonSchema = schema_validation.RelaxRequired(jsonSchema)
+	}
+
 	compiler := jsonschema.NewCompiler()
 	_ = compiler.AddResource("requestBody.json", strings.NewReader(string(jsonSchema)))
 	jsch, err := compiler.Compile("requestBody.json")
 	if err != nil {
+		if patternError := schema_validation.CheckUnsupportedPatterns(renderedSchema); patternError != nil {
+			return false, append(validationErrors, patternError)
+		}
 		validationErrors = append(validationErrors, &errors.ValidationError{
 			ValidationType:    helpers.RequestBodyValidation,
 			ValidationSubType: helpers.Schema,
@@ -120,19 +144,26 @@ func ValidateRequestSchema(
 		return false, validationErrors
 	}
 
+	// readOnly properties must not be sent in a request.
+	var accessFailures []*errors.SchemaValidationFailure
+	if options.ValidateReadOnlyWriteOnly {
+		accessFailures = schema_validation.ValidatePropertyAccess(schema, decodedObj, schema_validation.RequestAccess)
+	}
+
 	// validate the object against the schema
 	scErrs := jsch.Validate(decodedObj)
-	if scErrs != nil {
-		jk := scErrs.(*jsonschema.ValidationError)
-
-		// flatten the validationErrors
-		schFlatErrs := jk.BasicOutput().Errors
+	if scErrs != nil || len(accessFailures) > 0 {
+		var schFlatErrs []jsonschema.BasicError
+		var jk *jsonschema.ValidationError
+		if scErrs != nil {
+			jk = scErrs.(*jsonschema.ValidationError)
+
+			// flatten the validationErrors
+			schFlatErrs = schema_validation.FlattenValidationErrors(jk, options.IncludeAllErrors)
+		}
 		var schemaValidationErrors []*errors.SchemaValidationFailure
 		for q := range schFlatErrs {
 			er := schFlatErrs[q]
-			if er.KeywordLocation == "" || strings.HasPrefix(er.Error, "doesn't validate with") {
-				continue // ignore this error, it's useless tbh, utter noise.
-			}
 			if er.Error != "" {
 
 				// re-encode the schema.
@@ -140,7 +171,7 @@ func ValidateRequestSchema(
 				_ = yaml.Unmarshal(renderedSchema, &renderedNode)
 
 				// locate the violated property in the schema
-				located := schema_validation.LocateSchemaPropertyNodeByJSONPath(renderedNode.Content[0], er.KeywordLocation)
+				located := schema_validation.LocateSchemaViolationNode(renderedNode.Content[0], er)
 
 				// extract the element specified by the instance
 				val := instanceLocationRegex.FindStringSubmatch(er.InstanceLocation)
@@ -159,31 +190,30 @@ func ValidateRequestSchema(
 				}
 
 				violation := &errors.SchemaValidationFailure{
-					Reason:          er.Error,
-					Location:        er.KeywordLocation,
-					ReferenceSchema: string(renderedSchema),
-					ReferenceObject: referenceObject,
-					OriginalError:   jk,
+					Reason:           schema_validation.GetFailureReason(er),
+					Location:         er.KeywordLocation,
+					InstanceLocation: er.InstanceLocation,
+					ReferenceSchema:  string(renderedSchema),
+					ReferenceObject:  referenceObject,
+					OriginalError:    jk,
+					LeafError:        schema_validation.LocateValidationErrorCause(jk, er),
 				}
 				// if we have a location within the schema, add it to the error
 				if located != nil {
 
-					line := located.Line
 					// if the located node is a map or an array, then the actual human interpretable
 					// line on which the violation occurred is the line of the key, not the value.
-					if located.Kind == yaml.MappingNode || located.Kind == yaml.SequenceNode {
-						if line > 0 {
-							line--
-						}
-					}
+					line := schema_validation.LocateSchemaViolationLine(renderedNode.Content[0], located)
 
 					// location of the violation within the rendered schema.
 					violation.Line = line
 					violation.Column = located.Column
+					violation.SchemaNode = located
 				}
 				schemaValidationErrors = append(schemaValidationErrors, violation)
 			}
 		}
+		schemaValidationErrors = append(schemaValidationErrors, accessFailures...)
 
 		line := 1
 		col := 0
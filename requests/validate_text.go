@@ -0,0 +1,38 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package requests
+
+import (
+	"net/http"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+)
+
+// ValidateTextRequestSchema will validate a http.Request pointer with a text/plain body against a schema. The raw
+// body is validated as a single string value, so a 'type: string' schema can constrain it with maxLength, minLength,
+// pattern and enum keywords.
+// If validation fails, it will return a list of validation errors as the second return value.
+func ValidateTextRequestSchema(
+	request *http.Request,
+	schema *base.Schema,
+	renderedSchema,
+	jsonSchema []byte,
+	opts ...config.Option) (bool, []*errors.ValidationError) {
+
+	options := config.NewValidationOptions(opts...)
+
+	requestBody, withinLimit := readRequestBody(request, options.MaxRequestBodySize)
+	if !withinLimit {
+		return false, []*errors.ValidationError{errors.RequestBodyTooLarge(request, options.MaxRequestBodySize)}
+	}
+
+	var decodedObj interface{}
+	if len(requestBody) > 0 {
+		decodedObj = string(requestBody)
+	}
+
+	return validateDecodedRequestBody(request, schema, renderedSchema, jsonSchema, requestBody, decodedObj, options)
+}
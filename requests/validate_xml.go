@@ -0,0 +1,216 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package requests
+
+import (
+	"bytes"
+	"encoding/xml"
+	stdError "errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// ValidateXMLRequestSchema will validate a http.Request pointer with an XML body (such as application/xml) against a
+// schema. The body is decoded into an object using the schema (see DecodeXML), and the object is then validated in
+// the same way as a JSON body.
+// If validation fails, it will return a list of validation errors as the second return value.
+func ValidateXMLRequestSchema(
+	request *http.Request,
+	schema *base.Schema,
+	renderedSchema,
+	jsonSchema []byte,
+	opts ...config.Option) (bool, []*errors.ValidationError) {
+
+	options := config.NewValidationOptions(opts...)
+
+	requestBody, withinLimit := readRequestBody(request, options.MaxRequestBodySize)
+	if !withinLimit {
+		return false, []*errors.ValidationError{errors.RequestBodyTooLarge(request, options.MaxRequestBodySize)}
+	}
+
+	var decodedObj interface{}
+	if len(requestBody) > 0 {
+		var err error
+		if decodedObj, err = DecodeXML(requestBody, schema); err != nil {
+			return false, []*errors.ValidationError{requestBodyDecodeError(request, renderedSchema, requestBody, err)}
+		}
+	}
+
+	return validateDecodedRequestBody(request, schema, renderedSchema, jsonSchema, requestBody, decodedObj, options)
+}
+
+// xmlElement is an element of a parsed XML document.
+type xmlElement struct {
+	name     string
+	attrs    []xml.Attr
+	children []*xmlElement
+	text     string
+}
+
+// DecodeXML will decode an XML document into an object that can be validated against a schema.
+//
+// The root element is decoded as the schema. Child elements and attributes are decoded into the properties they are
+// named after, or the property with a matching 'xml.name', and properties marked with 'xml.attribute' are read from
+// attributes. Array properties are read from the children of a single element if they are 'xml.wrapped', or from
+// every repeated element otherwise. Values are cast to the type of their property schema. Elements that repeat for
+// a non-array property become arrays, so the schema can reject them.
+func DecodeXML(body []byte, schema *base.Schema) (any, error) {
+	root, err := parseXMLDocument(body)
+	if err != nil {
+		return nil, err
+	}
+	return decodeXMLElement(root, schema), nil
+}
+
+func parseXMLDocument(body []byte) (*xmlElement, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	var root *xmlElement
+	var open []*xmlElement
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &xmlElement{name: t.Name.Local, attrs: t.Attr}
+			switch {
+			case len(open) > 0:
+				parent := open[len(open)-1]
+				parent.children = append(parent.children, element)
+			case root != nil:
+				return nil, stdError.New("XML document has more than one root element")
+			default:
+				root = element
+			}
+			open = append(open, element)
+		case xml.EndElement:
+			open = open[:len(open)-1]
+		case xml.CharData:
+			if len(open) > 0 {
+				open[len(open)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, stdError.New("XML document has no root element")
+	}
+	return root, nil
+}
+
+func decodeXMLElement(element *xmlElement, sch *base.Schema) any {
+	if sch != nil && slices.Contains(sch.Type, helpers.Array) {
+		// the element wraps the array, so every child is an item.
+		itemSchema := xmlItemSchema(sch)
+		items := make([]any, 0, len(element.children))
+		for _, child := range element.children {
+			items = append(items, decodeXMLElement(child, itemSchema))
+		}
+		return items
+	}
+	isObject := sch != nil && slices.Contains(sch.Type, helpers.Object)
+	if (sch == nil || len(sch.Type) == 0) && (len(element.children) > 0 || len(xmlValueAttrs(element)) > 0) {
+		isObject = true
+	}
+	if !isObject {
+		return castFormValue(strings.TrimSpace(element.text), sch)
+	}
+
+	decoded := make(map[string]any)
+	for _, attr := range xmlValueAttrs(element) {
+		name, propSchema := xmlProperty(sch, attr.Name.Local, true)
+		decoded[name] = castFormValue(attr.Value, propSchema)
+	}
+	repeated := make(map[string]bool)
+	for _, child := range element.children {
+		name, propSchema := xmlProperty(sch, child.name, false)
+		if propSchema != nil && slices.Contains(propSchema.Type, helpers.Array) {
+			if propSchema.XML != nil && propSchema.XML.Wrapped {
+				decoded[name] = decodeXMLElement(child, propSchema)
+				continue
+			}
+			items, _ := decoded[name].([]any)
+			decoded[name] = append(items, decodeXMLElement(child, xmlItemSchema(propSchema)))
+			continue
+		}
+		value := decodeXMLElement(child, propSchema)
+		existing, ok := decoded[name]
+		switch {
+		case !ok:
+			decoded[name] = value
+		case repeated[name]:
+			decoded[name] = append(existing.([]any), value)
+		default:
+			decoded[name] = []any{existing, value}
+			repeated[name] = true
+		}
+	}
+	return decoded
+}
+
+// xmlValueAttrs returns the attributes of an element that hold values, namespace declarations are not values.
+func xmlValueAttrs(element *xmlElement) []xml.Attr {
+	var attrs []xml.Attr
+	for _, attr := range element.attrs {
+		if attr.Name.Space != "xmlns" && attr.Name.Local != "xmlns" {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
+
+// xmlProperty returns the name and schema of the property of an object schema that an element (or attribute) is
+// decoded into. Properties are matched by their 'xml.name' if they define one, otherwise by their name, and the items
+// of an array that is not wrapped are also matched by the 'xml.name' of the items. If no property matches, the name
+// is returned as it is, without a schema.
+func xmlProperty(sch *base.Schema, name string, attribute bool) (string, *base.Schema) {
+	if sch == nil || sch.Properties == nil {
+		return name, nil
+	}
+	for pair := orderedmap.First(sch.Properties); pair != nil; pair = pair.Next() {
+		if pair.Value() == nil {
+			continue
+		}
+		propSchema := pair.Value().Schema()
+		if propSchema == nil || (propSchema.XML != nil && propSchema.XML.Attribute) != attribute {
+			continue
+		}
+		if xmlName(pair.Key(), propSchema) == name {
+			return pair.Key(), propSchema
+		}
+		if !attribute && slices.Contains(propSchema.Type, helpers.Array) &&
+			(propSchema.XML == nil || !propSchema.XML.Wrapped) {
+			if items := xmlItemSchema(propSchema); items != nil && items.XML != nil && items.XML.Name == name {
+				return pair.Key(), propSchema
+			}
+		}
+	}
+	return name, nil
+}
+
+// xmlName returns the name of the element (or attribute) for a property, which is the 'xml.name' if it is defined.
+func xmlName(property string, sch *base.Schema) string {
+	if sch.XML != nil && sch.XML.Name != "" {
+		return sch.XML.Name
+	}
+	return property
+}
+
+func xmlItemSchema(sch *base.Schema) *base.Schema {
+	if sch.Items != nil && sch.Items.IsA() && sch.Items.A != nil {
+		return sch.Items.A.Schema()
+	}
+	return nil
+}
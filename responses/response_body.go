@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 )
@@ -20,7 +22,10 @@ type ResponseBodyValidator interface {
 
 	// ValidateResponseBody will validate the response body for a http.Response pointer. The request is used to
 	// locate the operation in the specification, the response is used to ensure the response code, media type and the
-	// schema of the response body are valid.
+	// schema of the response body are valid. A response with a status code that is not declared is validated against
+	// the default response, if there is one. The errors of such a response have a ResponseCode of 'default', and a
+	// helpers.DefaultResponseUsed warning is passed to any config.WithWarningHandler, whether it is valid or not (see
+	// ResponseCodeFor, to learn which response was used without a warning handler).
 	ValidateResponseBody(request *http.Request, response *http.Response) (bool, []*errors.ValidationError)
 
 	// SetPathItem will set the pathItem for the ResponseBodyValidator, all validations will be performed
@@ -35,8 +40,14 @@ func (v *responseBodyValidator) SetPathItem(path *v3.PathItem, pathValue string)
 }
 
 // NewResponseBodyValidator will create a new ResponseBodyValidator from an OpenAPI 3+ document
-func NewResponseBodyValidator(document *v3.Document) ResponseBodyValidator {
-	return &responseBodyValidator{document: document, schemaCache: &sync.Map{}}
+func NewResponseBodyValidator(document *v3.Document, opts ...config.Option) ResponseBodyValidator {
+	options := config.NewValidationOptions(opts...)
+	return &responseBodyValidator{
+		document:    document,
+		options:     options,
+		operations:  options.OperationCacheFor(document),
+		schemaCache: &sync.Map{},
+	}
 }
 
 type schemaCache struct {
@@ -47,6 +58,8 @@ type schemaCache struct {
 
 type responseBodyValidator struct {
 	document    *v3.Document
+	options     *config.ValidationOptions
+	operations  *helpers.OperationCache
 	pathItem    *v3.PathItem
 	pathValue   string
 	errors      []*errors.ValidationError
@@ -0,0 +1,87 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package responses
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// IsStatusCodeDeclared returns true if an operation declares a response for a status code. The status code is
+// declared if it is listed in the responses of the operation, if it falls within a listed range (such as '2XX' for
+// 201), or if the operation has a 'default' response, which covers every status code that is not listed.
+func IsStatusCodeDeclared(operation *v3.Operation, statusCode int) bool {
+	if operation == nil || operation.Responses == nil {
+		return false
+	}
+	if operation.Responses.Default != nil {
+		return true
+	}
+	response, _ := declaredResponse(operation, statusCode)
+	return response != nil
+}
+
+// ResponseCodeFor returns the response of an operation that a response with a status code is validated against (by
+// ValidateResponseBody): the status code (such as '200'), the range it falls within (such as '2XX'), or 'default'
+// when the status code is not listed and the operation has a default response. If the status code is not declared,
+// an empty string is returned. A response that passes validation has no errors to carry the response code, so use
+// this to learn whether it was validated against a specific status code, or only against the default response.
+func ResponseCodeFor(operation *v3.Operation, statusCode int) string {
+	if _, code := declaredResponse(operation, statusCode); code != "" {
+		return code
+	}
+	if operation != nil && operation.Responses != nil && operation.Responses.Default != nil {
+		return helpers.DefaultResponse
+	}
+	return ""
+}
+
+// declaredResponse returns the response that an operation lists for a status code, and the key it is listed by:
+// the status code itself, or the range it falls within (such as '2XX', which may also be written as '2xx'). The
+// 'default' response is not consulted, if the status code is not listed then nil is returned.
+func declaredResponse(operation *v3.Operation, statusCode int) (*v3.Response, string) {
+	if operation == nil || operation.Responses == nil || operation.Responses.Codes == nil {
+		return nil, ""
+	}
+	codeRange := statusCode / 100
+	for _, code := range []string{
+		strconv.Itoa(statusCode), fmt.Sprintf("%dXX", codeRange), fmt.Sprintf("%dxx", codeRange),
+	} {
+		if response := operation.Responses.Codes.GetOrZero(code); response != nil {
+			return response, code
+		}
+	}
+	return nil, ""
+}
+
+// ValidateResponseStatusCode will validate that the status code a server returned for a request is declared by the
+// operation (see IsStatusCodeDeclared), so contract tests can assert that a server only returns documented status
+// codes. The response body is not validated, use ValidateResponseBody for that.
+// If the status code is not declared, it will return a validation error as the second return value. A nil operation
+// declares no status codes, but there is no operation to report an error against, so no errors are returned.
+func ValidateResponseStatusCode(
+	request *http.Request,
+	operation *v3.Operation,
+	statusCode int,
+	opts ...config.Option) (bool, []*errors.ValidationError) {
+
+	if IsStatusCodeDeclared(operation, statusCode) {
+		return true, nil
+	}
+	if operation == nil {
+		return false, nil
+	}
+	options := config.NewValidationOptions(opts...)
+	validationErrors := []*errors.ValidationError{errors.ResponseCodeNotFound(operation, request, statusCode)}
+	errors.PopulateValidationErrors(validationErrors, request, "")
+	errors.SetValidationDirection(validationErrors, helpers.ResponseDirection)
+	errors.FormatValidationErrors(validationErrors, options.MessageFormatterFor)
+	return false, validationErrors
+}
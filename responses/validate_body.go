@@ -4,14 +4,15 @@
 package responses
 
 import (
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
+	"github.com/pb33f/libopenapi-validator/schema_validation"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
@@ -21,13 +22,23 @@ import (
 func (v *responseBodyValidator) ValidateResponseBody(
 	request *http.Request,
 	response *http.Response,
+) (bool, []*errors.ValidationError) {
+	valid, validationErrors := v.validateResponseBody(request, response)
+	errors.SetValidationDirection(validationErrors, helpers.ResponseDirection)
+	errors.FormatValidationErrors(validationErrors, v.options.MessageFormatterFor)
+	return valid, validationErrors
+}
+
+func (v *responseBodyValidator) validateResponseBody(
+	request *http.Request,
+	response *http.Response,
 ) (bool, []*errors.ValidationError) {
 	// find path
 	var pathItem *v3.PathItem
 	var pathFound string
 	var errs []*errors.ValidationError
 	if v.pathItem == nil {
-		pathItem, errs, pathFound = paths.FindPath(request, v.document)
+		pathItem, errs, pathFound = paths.FindPath(request, v.document, config.WithExistingOpts(v.options))
 		if pathItem == nil || errs != nil {
 			v.errors = errs
 			return false, errs
@@ -38,7 +49,7 @@ func (v *responseBodyValidator) ValidateResponseBody(
 	}
 
 	var validationErrors []*errors.ValidationError
-	operation := helpers.ExtractOperation(request, pathItem)
+	operation := v.operations.ExtractOperation(request, pathItem)
 
 	// extract the response code from the response
 	httpCode := response.StatusCode
@@ -47,12 +58,8 @@ func (v *responseBodyValidator) ValidateResponseBody(
 	// extract the media type from the content type header.
 	mediaTypeSting, _, _ := helpers.ExtractContentType(contentType)
 
-	// check if the response code is in the contract
-	foundResponse := operation.Responses.Codes.GetOrZero(fmt.Sprintf("%d", httpCode))
-	if foundResponse == nil {
-		// check range definition for response codes
-		foundResponse = operation.Responses.Codes.GetOrZero(fmt.Sprintf("%dXX", httpCode/100))
-	}
+	// check if the response code is in the contract, or falls within a range definition (see IsStatusCodeDeclared)
+	foundResponse, responseCode := declaredResponse(operation, httpCode)
 
 	if foundResponse != nil {
 		if foundResponse.Content != nil { // only validate if we have content types.
@@ -72,16 +79,21 @@ func (v *responseBodyValidator) ValidateResponseBody(
 				}
 			}
 		}
-	} else {
-		// no code match, check for default response
-		if operation.Responses.Default != nil && operation.Responses.Default.Content != nil {
+	} else if operation.Responses.Default != nil {
+		// no code match, so the default response is used, which is reported so that it is clear the response
+		// was not validated against a specific status code.
+		responseCode = helpers.DefaultResponse
+		if v.options.WarningHandler != nil {
+			v.options.WarningHandler(errors.DefaultResponseUsed(request, response, operation, pathFound))
+		}
+		if operation.Responses.Default.Content != nil {
 			// check content type has been defined in the contract
 			if mediaType, ok := operation.Responses.Default.Content.Get(mediaTypeSting); ok {
 				validationErrors = append(validationErrors,
 					v.checkResponseSchema(request, response, contentType, mediaType)...)
 			} else {
 				// check that the operation *actually* returns a body. (i.e. a 204 response)
-				if operation.Responses.Default.Content != nil && orderedmap.Len(operation.Responses.Default.Content) > 0 {
+				if orderedmap.Len(operation.Responses.Default.Content) > 0 {
 
 					// content type not found in the contract
 					codeStr := strconv.Itoa(httpCode)
@@ -89,15 +101,18 @@ func (v *responseBodyValidator) ValidateResponseBody(
 						errors.ResponseContentTypeNotFound(operation, request, response, codeStr, true))
 				}
 			}
-		} else {
-			// TODO: add support for '2XX' and '3XX' responses in the contract
-			// no default, no code match, nothing!
-			validationErrors = append(validationErrors,
-				errors.ResponseCodeNotFound(operation, request, httpCode))
 		}
+	} else {
+		// no default, no code match, nothing!
+		responseCode = ""
+		validationErrors = append(validationErrors,
+			errors.ResponseCodeNotFound(operation, request, httpCode))
 	}
 
 	errors.PopulateValidationErrors(validationErrors, request, pathFound)
+	for _, validationError := range validationErrors {
+		validationError.ResponseCode = responseCode
+	}
 
 	if len(validationErrors) > 0 {
 		return false, validationErrors
@@ -136,8 +151,11 @@ func (v *responseBodyValidator) checkResponseSchema(
 
 				// render the schema inline and perform the intensive work of rendering and converting
 				// this is only performed once per schema and cached in the validator.
-				schema = mediaType.Schema.Schema()
-				renderedInline, _ = schema.RenderInline()
+				var renderError *errors.ValidationError
+				schema, renderedInline, renderError = schema_validation.RenderSchemaProxy(mediaType.Schema)
+				if renderError != nil {
+					return append(validationErrors, renderError)
+				}
 				renderedJSON, _ = utils.ConvertYAMLtoJSON(renderedInline)
 				v.schemaCache.Store(hash, &schemaCache{
 					schema:         schema,
@@ -147,7 +165,8 @@ func (v *responseBodyValidator) checkResponseSchema(
 			}
 
 			// render the schema, to be used for validation
-			valid, vErrs := ValidateResponseSchema(request, response, schema, renderedInline, renderedJSON)
+			valid, vErrs := ValidateResponseSchema(request, response, schema, renderedInline, renderedJSON,
+				config.WithExistingOpts(v.options))
 			if !valid {
 				validationErrors = append(validationErrors, vErrs...)
 			}
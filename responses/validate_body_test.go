@@ -13,6 +13,8 @@ import (
 	"testing"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
 	"github.com/stretchr/testify/assert"
@@ -1191,3 +1193,285 @@ func (er *errorReader) Read(p []byte) (n int, err error) {
 func (er *errorReader) Close() error {
 	return nil
 }
+
+func TestValidateBody_WriteOnlyProperty(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                  secretSauce:
+                    type: string
+                    writeOnly: true`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	v := NewResponseBodyValidator(&m.Model, config.WithReadOnlyWriteOnlyValidation())
+
+	body := map[string]interface{}{
+		"name":        "Big Mac",
+		"secretSauce": "mayo and ketchup",
+	}
+
+	bodyBytes, _ := json.Marshal(body)
+
+	// build a request
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", nil)
+	request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+
+	// simulate a request/response
+	res := httptest.NewRecorder()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(bodyBytes)
+	}
+
+	// fire the request
+	handler(res, request)
+
+	// record response
+	response := res.Result()
+
+	// validate!
+	valid, errors := v.ValidateResponseBody(request, response)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "200 response body for '/burgers/createBurger' failed to validate schema", errors[0].Message)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "The property '/secretSauce' is writeOnly, it must not be returned in a response", errors[0].SchemaValidationErrors[0].Reason)
+}
+
+func TestValidateResponseStatusCode(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      responses:
+        '200':
+          description: ok
+        4XX:
+          description: client error
+    post:
+      responses:
+        '201':
+          description: created
+        default:
+          description: anything else
+    delete: {}`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	pathItem := m.Model.Paths.PathItems.GetOrZero("/burgers")
+
+	assert.True(t, IsStatusCodeDeclared(pathItem.Get, http.StatusOK))
+	assert.True(t, IsStatusCodeDeclared(pathItem.Get, http.StatusNotFound))
+	assert.False(t, IsStatusCodeDeclared(pathItem.Get, http.StatusCreated))
+	assert.False(t, IsStatusCodeDeclared(pathItem.Get, http.StatusInternalServerError))
+	assert.True(t, IsStatusCodeDeclared(pathItem.Post, http.StatusInternalServerError))
+	assert.False(t, IsStatusCodeDeclared(pathItem.Delete, http.StatusOK))
+	assert.False(t, IsStatusCodeDeclared(nil, http.StatusOK))
+
+	assert.Equal(t, "4XX", ResponseCodeFor(pathItem.Get, http.StatusNotFound))
+	assert.Equal(t, "", ResponseCodeFor(pathItem.Get, http.StatusInternalServerError))
+	assert.Equal(t, "", ResponseCodeFor(nil, http.StatusOK))
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+	valid, errs := ValidateResponseStatusCode(request, pathItem.Get, http.StatusTeapot)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = ValidateResponseStatusCode(request, pathItem.Get, http.StatusInternalServerError)
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "GET operation request response code '500' does not exist", errs[0].Message)
+	assert.Equal(t, helpers.ResponseBodyResponseCode, errs[0].ValidationSubType)
+	assert.Equal(t, helpers.ResponseDirection, errs[0].Direction)
+	assert.Equal(t, "/burgers", errs[0].RequestPath)
+	assert.Equal(t, 5, errs[0].SpecLine)
+
+	request, _ = http.NewRequest(http.MethodDelete, "https://things.com/burgers", nil)
+	valid, errs = ValidateResponseStatusCode(request, pathItem.Delete, http.StatusOK)
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, -1, errs[0].SpecLine)
+}
+
+func TestValidateBody_LowerCaseRangeResponse(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      responses:
+        2xx:
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewResponseBodyValidator(&m.Model)
+
+	respond := func(body string) *http.Response {
+		res := httptest.NewRecorder()
+		res.Header().Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+		res.WriteHeader(http.StatusCreated)
+		_, _ = res.Write([]byte(body))
+		return res.Result()
+	}
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", nil)
+	request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+
+	// the range is declared (see IsStatusCodeDeclared), so the body is validated against it.
+	assert.True(t, IsStatusCodeDeclared(m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post,
+		http.StatusCreated))
+	valid, errs := v.ValidateResponseBody(request, respond(`{"name":"Big Mac"}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = v.ValidateResponseBody(request, respond(`{"patties":2}`))
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "2xx", errs[0].ResponseCode)
+}
+
+func TestValidateBody_DefaultResponse(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string
+        default:
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [error]
+                properties:
+                  error:
+                    type: string
+  /burgers/deleteBurger:
+    delete:
+      responses:
+        '204':
+          description: deleted
+        default:
+          description: anything else`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	var warnings []*liberrors.ValidationWarning
+	v := NewResponseBodyValidator(&m.Model, config.WithWarningHandler(func(warning *liberrors.ValidationWarning) {
+		warnings = append(warnings, warning)
+	}))
+
+	respond := func(request *http.Request, code int, body string) *http.Response {
+		res := httptest.NewRecorder()
+		res.Header().Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+		res.WriteHeader(code)
+		_, _ = res.Write([]byte(body))
+		return res.Result()
+	}
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", nil)
+	request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+
+	// a 418 is not declared, so it is validated against the default response.
+	valid, errs := v.ValidateResponseBody(request, respond(request, http.StatusTeapot, `{"error":"I'm a teapot"}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, helpers.DefaultResponseUsed, warnings[0].WarningType)
+	assert.Equal(t, "POST response code '418' for path '/burgers/createBurger' was validated against the "+
+		"default response", warnings[0].Message)
+	assert.Equal(t, 15, warnings[0].SpecLine)
+
+	// without a warning handler, the response that was used can still be looked up.
+	operation := m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post
+	assert.Equal(t, helpers.DefaultResponse, ResponseCodeFor(operation, http.StatusTeapot))
+	assert.Equal(t, "200", ResponseCodeFor(operation, http.StatusOK))
+
+	valid, errs = v.ValidateResponseBody(request, respond(request, http.StatusTeapot, `{"name":"Big Mac"}`))
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "418 response body for '/burgers/createBurger' failed to validate schema", errs[0].Message)
+	assert.Equal(t, helpers.DefaultResponse, errs[0].ResponseCode)
+	assert.Len(t, warnings, 2)
+
+	// a declared status code is validated against its own response, without a warning.
+	valid, errs = v.ValidateResponseBody(request, respond(request, http.StatusOK, `{"error":"nope"}`))
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "200", errs[0].ResponseCode)
+	assert.Len(t, warnings, 2)
+
+	// a default response without any content declares every status code.
+	request, _ = http.NewRequest(http.MethodDelete, "https://things.com/burgers/deleteBurger", nil)
+	valid, errs = v.ValidateResponseBody(request, respond(request, http.StatusTeapot, `{}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	assert.Len(t, warnings, 3)
+}
+
+func TestValidateBody_UnresolvedSchemaReference(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                  fries:
+                    $ref: '#/components/schemas/Fries'`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", nil)
+	res := httptest.NewRecorder()
+	res.Header().Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+	res.WriteHeader(http.StatusOK)
+	_, _ = res.Write([]byte(`{"name":"chips","fries":12}`))
+
+	// the fries would be left out of the rendered schema, so the body is not validated against it.
+	valid, errs := NewResponseBodyValidator(&m.Model).ValidateResponseBody(request, res.Result())
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, helpers.SchemaRenderFailed, errs[0].ValidationSubType)
+	assert.Contains(t, errs[0].Reason, "cannot find reference #/components/schemas/Fries")
+	assert.Equal(t, 10, errs[0].SpecLine)
+	assert.Equal(t, "/burgers/createBurger", errs[0].SpecPath)
+	assert.Equal(t, "200", errs[0].ResponseCode)
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/schema_validation"
@@ -27,14 +28,17 @@ var instanceLocationRegex = regexp.MustCompile(`^/(\d+)`)
 // locate the operation in the specification, the response is used to ensure the response code, media type and the
 // schema of the response body are valid.
 //
-// This function is used by the ValidateResponseBody function, but can be used independently.
+// This function is used by the ValidateResponseBody function, but can be used independently. Options can be supplied,
+// for example config.WithReadOnlyWriteOnlyValidation will reject writeOnly properties.
 func ValidateResponseSchema(
 	request *http.Request,
 	response *http.Response,
 	schema *base.Schema,
 	renderedSchema,
-	jsonSchema []byte) (bool, []*errors.ValidationError) {
+	jsonSchema []byte,
+	opts ...config.Option) (bool, []*errors.ValidationError) {
 
+	options := config.NewValidationOptions(opts...)
 	var validationErrors []*errors.ValidationError
 
 	if response == nil || response.Body == nil {
@@ -90,7 +94,7 @@ func ValidateResponseSchema(
 	var decodedObj interface{}
 
 	if len(responseBody) > 0 {
-		err := json.Unmarshal(responseBody, &decodedObj)
+		err := helpers.UnmarshalJSON(responseBody, &decodedObj, options.UseJSONNumber)
 
 		if err != nil {
 			// cannot decode the response body, so it's not valid
@@ -121,26 +125,48 @@ func ValidateResponseSchema(
 		return true, nil
 	}
 
+	jsonSchema = schema_validation.ResolveCircularReferences(schema, jsonSchema)
+
 	// create a new jsonschema compiler and add in the rendered JSON schema.
 	compiler := jsonschema.NewCompiler()
 	fName := fmt.Sprintf("%s.json", helpers.ResponseBodyValidation)
 	_ = compiler.AddResource(fName,
 		strings.NewReader(string(jsonSchema)))
-	jsch, _ := compiler.Compile(fName)
+	jsch, err := compiler.Compile(fName)
+	if err != nil {
+		if patternError := schema_validation.CheckUnsupportedPatterns(renderedSchema); patternError != nil {
+			return false, append(validationErrors, patternError)
+		}
+		validationErrors = append(validationErrors, &errors.ValidationError{
+			ValidationType:    helpers.ResponseBodyValidation,
+			ValidationSubType: helpers.Schema,
+			Message:           err.Error(),
+			Reason:            "Failed to compile the response body for validation.",
+			Context:           string(renderedSchema),
+		})
+		return false, validationErrors
+	}
+
+	// writeOnly properties must not be returned in a response.
+	var accessFailures []*errors.SchemaValidationFailure
+	if options.ValidateReadOnlyWriteOnly {
+		accessFailures = schema_validation.ValidatePropertyAccess(schema, decodedObj, schema_validation.ResponseAccess)
+	}
 
 	// validate the object against the schema
 	scErrs := jsch.Validate(decodedObj)
-	if scErrs != nil {
-		jk := scErrs.(*jsonschema.ValidationError)
-
-		// flatten the validationErrors
-		schFlatErrs := jk.BasicOutput().Errors
+	if scErrs != nil || len(accessFailures) > 0 {
+		var schFlatErrs []jsonschema.BasicError
+		var jk *jsonschema.ValidationError
+		if scErrs != nil {
+			jk = scErrs.(*jsonschema.ValidationError)
+
+			// flatten the validationErrors
+			schFlatErrs = schema_validation.FlattenValidationErrors(jk, options.IncludeAllErrors)
+		}
 		var schemaValidationErrors []*errors.SchemaValidationFailure
 		for q := range schFlatErrs {
 			er := schFlatErrs[q]
-			if er.KeywordLocation == "" || strings.HasPrefix(er.Error, "doesn't validate with") {
-				continue // ignore this error, it's useless tbh, utter noise.
-			}
 			if er.Error != "" {
 
 				// re-encode the schema.
@@ -148,7 +174,7 @@ func ValidateResponseSchema(
 				_ = yaml.Unmarshal(renderedSchema, &renderedNode)
 
 				// locate the violated property in the schema
-				located := schema_validation.LocateSchemaPropertyNodeByJSONPath(renderedNode.Content[0], er.KeywordLocation)
+				located := schema_validation.LocateSchemaViolationNode(renderedNode.Content[0], er)
 
 				// extract the element specified by the instance
 				val := instanceLocationRegex.FindStringSubmatch(er.InstanceLocation)
@@ -167,31 +193,30 @@ func ValidateResponseSchema(
 				}
 
 				violation := &errors.SchemaValidationFailure{
-					Reason:          er.Error,
-					Location:        er.KeywordLocation,
-					ReferenceSchema: string(renderedSchema),
-					ReferenceObject: referenceObject,
-					OriginalError:   jk,
+					Reason:           schema_validation.GetFailureReason(er),
+					Location:         er.KeywordLocation,
+					InstanceLocation: er.InstanceLocation,
+					ReferenceSchema:  string(renderedSchema),
+					ReferenceObject:  referenceObject,
+					OriginalError:    jk,
+					LeafError:        schema_validation.LocateValidationErrorCause(jk, er),
 				}
 				// if we have a location within the schema, add it to the error
 				if located != nil {
 
-					line := located.Line
 					// if the located node is a map or an array, then the actual human interpretable
 					// line on which the violation occurred is the line of the key, not the value.
-					if located.Kind == yaml.MappingNode || located.Kind == yaml.SequenceNode {
-						if line > 0 {
-							line--
-						}
-					}
+					line := schema_validation.LocateSchemaViolationLine(renderedNode.Content[0], located)
 
 					// location of the violation within the rendered schema.
 					violation.Line = line
 					violation.Column = located.Column
+					violation.SchemaNode = located
 				}
 				schemaValidationErrors = append(schemaValidationErrors, violation)
 			}
 		}
+		schemaValidationErrors = append(schemaValidationErrors, accessFailures...)
 
 		line := 1
 		col := 0
@@ -0,0 +1,108 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"encoding/json"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// ValidateSchemaWithDefaults will decode a payload (a JSON/YAML blob), fill in every missing property that has a
+// 'default' in the schema (see ApplyDefaults), and then validate the enriched object against the schema. This suits
+// lenient APIs, where a payload that omits a defaulted property is still valid. The enriched object is returned along
+// with the result, so it can be used in place of the payload. If the payload is empty, or cannot be decoded, it is
+// validated as it is, and no object is returned.
+func ValidateSchemaWithDefaults(schema *base.Schema, payload []byte,
+	opts ...config.Option) (bool, []*liberrors.ValidationError, any) {
+
+	options := config.NewValidationOptions(opts...)
+	validator := NewSchemaValidator(config.WithExistingOpts(options))
+
+	var decodedObject any
+	if len(payload) == 0 || helpers.UnmarshalJSON(payload, &decodedObject, options.UseJSONNumber) != nil {
+		valid, validationErrors := validator.ValidateSchemaBytes(schema, payload)
+		return valid, validationErrors, nil
+	}
+	decodedObject = ApplyDefaults(schema, decodedObject, opts...)
+	valid, validationErrors := validator.ValidateSchemaObject(schema, decodedObject)
+	return valid, validationErrors, decodedObject
+}
+
+// ApplyDefaults will walk a schema and a decoded payload together, and set every property that is missing from an
+// object to the 'default' of its property schema. Objects are walked through their properties (and the properties
+// of allOf schemas), and arrays through their items (and prefixItems), so defaults are filled in at every depth of
+// the payload. Defaults are only applied to objects that are present, an object that is missing is not created
+// unless it has a default itself. The payload is modified in place, and returned.
+func ApplyDefaults(schema *base.Schema, payload any, opts ...config.Option) any {
+	applier := &defaultApplier{options: config.NewValidationOptions(opts...)}
+	return applier.apply(schema, payload, make(map[*base.Schema]bool))
+}
+
+type defaultApplier struct {
+	options *config.ValidationOptions
+}
+
+// apply fills the defaults of a schema into a value. The visited schemas guard against circular allOf references,
+// the value itself cannot be circular.
+func (d *defaultApplier) apply(schema *base.Schema, value any, visited map[*base.Schema]bool) any {
+	if schema == nil || visited[schema] {
+		return value
+	}
+	visited[schema] = true
+	defer delete(visited, schema)
+
+	for _, proxy := range schema.AllOf {
+		value = d.apply(proxySchema(proxy), value, visited)
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		for pair := orderedmap.First(schema.Properties); pair != nil; pair = pair.Next() {
+			propSchema := proxySchema(pair.Value())
+			if propSchema == nil {
+				continue
+			}
+			if property, ok := v[pair.Key()]; ok {
+				v[pair.Key()] = d.apply(propSchema, property, make(map[*base.Schema]bool))
+			} else if defaultValue, ok := d.defaultValue(propSchema); ok {
+				v[pair.Key()] = defaultValue
+			}
+		}
+	case []any:
+		for i := range v {
+			var itemSchema *base.Schema
+			if i < len(schema.PrefixItems) {
+				itemSchema = proxySchema(schema.PrefixItems[i])
+			} else if schema.Items != nil && schema.Items.IsA() {
+				itemSchema = proxySchema(schema.Items.A)
+			}
+			v[i] = d.apply(itemSchema, v[i], make(map[*base.Schema]bool))
+		}
+	}
+	return value
+}
+
+// defaultValue decodes the default of a schema in the same way as a JSON payload, so it can be validated.
+func (d *defaultApplier) defaultValue(schema *base.Schema) (any, bool) {
+	if schema.Default == nil {
+		return nil, false
+	}
+	var decoded any
+	if schema.Default.Decode(&decoded) != nil {
+		return nil, false
+	}
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, false
+	}
+	var value any
+	if helpers.UnmarshalJSON(encoded, &value, d.options.UseJSONNumber) != nil {
+		return nil, false
+	}
+	return value, true
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// ResolveCircularReferences will make the circular references of a JSON render of a schema resolvable. A circular
+// reference (such as a tree node that contains its own children) cannot be rendered inline, so it is rendered as a
+// reference to the component it refers to, which does not exist within the rendered schema. Without the component,
+// the schema cannot be compiled, so the components of the document are embedded into the JSON schema, where the
+// compiler can resolve them. If the schema has no references left, it is returned as-is.
+func ResolveCircularReferences(schema *base.Schema, jsonSchema []byte) []byte {
+	if schema == nil || schema.GoLow() == nil || schema.GoLow().Index == nil ||
+		!bytes.Contains(jsonSchema, []byte(`"$ref"`)) {
+		return jsonSchema
+	}
+	components := locateComponents(schema.GoLow().Index.GetRootNode())
+	if components == nil {
+		return jsonSchema
+	}
+	var decoded map[string]any
+	if json.Unmarshal(jsonSchema, &decoded) != nil {
+		return jsonSchema
+	}
+	if _, ok := decoded["components"]; ok {
+		return jsonSchema
+	}
+	rendered, err := yaml.Marshal(components)
+	if err != nil {
+		return jsonSchema
+	}
+	renderedJSON, err := utils.ConvertYAMLtoJSON(rendered)
+	if err != nil {
+		return jsonSchema
+	}
+	decoded["components"] = json.RawMessage(renderedJSON)
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return jsonSchema
+	}
+	return encoded
+}
+
+// locateComponents returns the value of the components of a document root node, or nil if there are none.
+func locateComponents(root *yaml.Node) *yaml.Node {
+	if root != nil && root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "components" {
+			return root.Content[i+1]
+		}
+	}
+	return nil
+}
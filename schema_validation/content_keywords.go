@@ -0,0 +1,96 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"encoding/json"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+)
+
+// renderContentKeywords will add the contentEncoding and contentMediaType keywords of a schema (and all of its
+// sub-schemas) to a JSON render of that schema. The high-level schema model does not render these keywords, so
+// without this they would never be asserted by the compiler. Strings with a 'byte' format are given a base64
+// contentEncoding. If nothing needs adding, the JSON is returned as-is.
+func renderContentKeywords(schema *base.Schema, jsonSchema []byte) []byte {
+	var decoded map[string]any
+	if schema == nil || json.Unmarshal(jsonSchema, &decoded) != nil {
+		return jsonSchema
+	}
+	if !addContentKeywords(schema, decoded) {
+		return jsonSchema
+	}
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return jsonSchema
+	}
+	return encoded
+}
+
+// addContentKeywords walks a schema alongside its decoded JSON render, copying content keywords from the low-level
+// model. Returns true if any keywords were added.
+func addContentKeywords(schema *base.Schema, rendered map[string]any) bool {
+	if schema == nil || rendered == nil || schema.GoLow() == nil {
+		return false
+	}
+	added := false
+	low := schema.GoLow()
+	if low.ContentEncoding.Value != "" {
+		if _, ok := rendered["contentEncoding"]; !ok {
+			rendered["contentEncoding"] = low.ContentEncoding.Value
+			added = true
+		}
+	}
+	// 'format: byte' strings are base64 encoded, so they are asserted as such.
+	if schema.Format == helpers.ByteFormat {
+		if _, ok := rendered["contentEncoding"]; !ok {
+			rendered["contentEncoding"] = "base64"
+			added = true
+		}
+	}
+	// 'format: binary' strings are raw bytes, not text, so a media type cannot be asserted against them.
+	if low.ContentMediaType.Value != "" && schema.Format != helpers.BinaryFormat {
+		if _, ok := rendered["contentMediaType"]; !ok {
+			rendered["contentMediaType"] = low.ContentMediaType.Value
+			added = true
+		}
+	}
+
+	walk := func(proxy *base.SchemaProxy, node any) {
+		if proxy == nil {
+			return
+		}
+		if m, ok := node.(map[string]any); ok && addContentKeywords(proxy.Schema(), m) {
+			added = true
+		}
+	}
+	walkList := func(proxies []*base.SchemaProxy, node any) {
+		if list, ok := node.([]any); ok {
+			for i := range proxies {
+				if i < len(list) {
+					walk(proxies[i], list[i])
+				}
+			}
+		}
+	}
+
+	if props, ok := rendered["properties"].(map[string]any); ok && schema.Properties != nil {
+		for pair := schema.Properties.First(); pair != nil; pair = pair.Next() {
+			walk(pair.Value(), props[pair.Key()])
+		}
+	}
+	if schema.Items != nil && schema.Items.IsA() {
+		walk(schema.Items.A, rendered["items"])
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.IsA() {
+		walk(schema.AdditionalProperties.A, rendered["additionalProperties"])
+	}
+	walk(schema.Not, rendered["not"])
+	walkList(schema.AllOf, rendered["allOf"])
+	walkList(schema.OneOf, rendered["oneOf"])
+	walkList(schema.AnyOf, rendered["anyOf"])
+	walkList(schema.PrefixItems, rendered["prefixItems"])
+	return added
+}
@@ -0,0 +1,240 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
+)
+
+var objectSizeRegex = regexp.MustCompile(`^(minimum|maximum) (\d+) properties allowed, but found (\d+) properties$`)
+var arraySizeRegex = regexp.MustCompile(`^(minimum|maximum) (\d+) items required, but found (\d+) items$`)
+var uniqueItemsRegex = regexp.MustCompile(`^items at index (\d+) and (\d+) are equal$`)
+var additionalPropertiesRegex = regexp.MustCompile(`^additionalProperties (.+) not allowed$`)
+var quotedPropertyRegex = regexp.MustCompile(`'(?:[^'\\]|\\.)*'`)
+var typeMismatchRegex = regexp.MustCompile(`^expected (.+), but got (.+)$`)
+var prefixItemTypeRegex = regexp.MustCompile(`/prefixItems/(\d+)/type$`)
+
+// GetFailureReason will return a human-readable reason for a flattened jsonschema error. Most errors are returned
+// as they are, however some keyword violations are quite terse (such as object and array sizes, and unique items),
+// so they are re-phrased to be clearer. The items of tuples (prefixItems) name their position, and the type that
+// position expects. Violations within the 'then' or 'else' branch of a conditional explain which branch applied, and why.
+func GetFailureReason(er jsonschema.BasicError) string {
+	reason := er.Error
+	if m := objectSizeRegex.FindStringSubmatch(er.Error); m != nil {
+		keyword := "minProperties"
+		if m[1] == "maximum" {
+			keyword = "maxProperties"
+		}
+		reason = fmt.Sprintf("%s has %s properties, however the schema allows a %s of %s properties (%s)",
+			describeInstance(er.InstanceLocation), m[3], m[1], m[2], keyword)
+	} else if m = arraySizeRegex.FindStringSubmatch(er.Error); m != nil {
+		keyword := "minItems"
+		if m[1] == "maximum" {
+			keyword = "maxItems"
+		}
+		reason = fmt.Sprintf("%s has %s items, however the schema allows a %s of %s items (%s)",
+			describeArray(er.InstanceLocation), m[3], m[1], m[2], keyword)
+	} else if m = uniqueItemsRegex.FindStringSubmatch(er.Error); m != nil {
+		reason = fmt.Sprintf("%s must only contain unique items (uniqueItems), however the items at index %s and %s "+
+			"are equal", describeArray(er.InstanceLocation), m[1], m[2])
+	} else if m = tupleTypeMismatch(er); m != nil {
+		reason = fmt.Sprintf("%s expects %s at index %s (prefixItems), however the item is %s",
+			describeArray(parentInstanceLocation(er.InstanceLocation)), m[1], m[3], m[2])
+	} else if strings.HasSuffix(er.KeywordLocation, "/items") && er.Error == "not allowed" {
+		reason = fmt.Sprintf("%s does not allow an item at index %s (items: false), only the items defined by "+
+			"'prefixItems' are allowed", describeArray(parentInstanceLocation(er.InstanceLocation)),
+			er.InstanceLocation[strings.LastIndex(er.InstanceLocation, "/")+1:])
+	} else if name, ok := additionalPropertyName(er); ok {
+		reason = fmt.Sprintf("%s contains the property '%s', which is not defined by the schema "+
+			"and additional properties are not allowed", describeInstance(parentInstanceLocation(er.InstanceLocation)), name)
+	}
+	if conditional, branch := conditionalBranch(er.KeywordLocation); branch == "then" {
+		reason = fmt.Sprintf("%s (the 'if' condition at '%s/if' matched, so the 'then' schema applies)", reason, conditional)
+	} else if branch == "else" {
+		reason = fmt.Sprintf("%s (the 'if' condition at '%s/if' did not match, so the 'else' schema applies)",
+			reason, conditional)
+	}
+	return reason
+}
+
+// IsNoiseError returns true if a flattened jsonschema error carries no information of its own, such as the errors
+// that wrap the failures of a referenced schema, or of a conditional (if/then/else) branch. The failures they wrap
+// are reported instead.
+func IsNoiseError(er jsonschema.BasicError) bool {
+	return er.KeywordLocation == "" || strings.HasPrefix(er.Error, "doesn't validate with") ||
+		er.Error == "if-then failed" || er.Error == "if-else failed"
+}
+
+// FlattenValidationErrors will flatten a jsonschema error into the list of errors to report. Violations of
+// additionalProperties are split into a violation per property (see ExpandAdditionalPropertiesErrors), and errors
+// that are noise are removed (see IsNoiseError). If includeAll is true, every error from the basic output is returned
+// exactly as jsonschema reported it.
+func FlattenValidationErrors(jk *jsonschema.ValidationError, includeAll bool) []jsonschema.BasicError {
+	if jk == nil {
+		return nil
+	}
+	if includeAll {
+		return jk.BasicOutput().Errors
+	}
+	var flattened []jsonschema.BasicError
+	for _, er := range ExpandAdditionalPropertiesErrors(jk.BasicOutput().Errors) {
+		if !IsNoiseError(er) {
+			flattened = append(flattened, er)
+		}
+	}
+	return flattened
+}
+
+// tupleTypeMismatch returns the expected type, the actual type and the position of a type violation of a tuple
+// item (a 'prefixItems' schema), or nil if the error is not one.
+func tupleTypeMismatch(er jsonschema.BasicError) []string {
+	position := prefixItemTypeRegex.FindStringSubmatch(er.KeywordLocation)
+	types := typeMismatchRegex.FindStringSubmatch(er.Error)
+	if position == nil || types == nil {
+		return nil
+	}
+	return []string{er.Error, types[1], types[2], position[1]}
+}
+
+// conditionalBranch returns the keyword location of the schema that owns the innermost conditional branch
+// (then / else) a keyword location is within, and the name of that branch. If the keyword location is not within a
+// conditional branch, the branch is empty.
+func conditionalBranch(keywordLocation string) (string, string) {
+	tokens := strings.Split(keywordLocation, "/")
+	for i := len(tokens) - 1; i > 0; i-- {
+		// a property named 'then' or 'else' is not a conditional branch.
+		if (tokens[i] == "then" || tokens[i] == "else") && !slices.Contains(namedSchemaKeywords, tokens[i-1]) {
+			return strings.Join(tokens[:i], "/"), tokens[i]
+		}
+	}
+	return "", ""
+}
+
+// ExpandAdditionalPropertiesErrors will split every additionalProperties violation that names more than one
+// unexpected property into a violation per property, with an instance location pointing at that property. All
+// other errors are returned as they are. The properties of each violation are sorted by name.
+func ExpandAdditionalPropertiesErrors(errs []jsonschema.BasicError) []jsonschema.BasicError {
+	var expanded []jsonschema.BasicError
+	for _, er := range errs {
+		m := additionalPropertiesRegex.FindStringSubmatch(er.Error)
+		if m == nil || !strings.HasSuffix(er.KeywordLocation, "/additionalProperties") {
+			expanded = append(expanded, er)
+			continue
+		}
+		quoted := quotedPropertyRegex.FindAllString(m[1], -1)
+		if len(quoted) == 0 {
+			expanded = append(expanded, er)
+			continue
+		}
+		slices.Sort(quoted)
+		for _, q := range quoted {
+			split := er
+			split.Error = fmt.Sprintf("additionalProperties %s not allowed", q)
+			split.InstanceLocation = er.InstanceLocation + "/" + escapeJSONPointer(unquoteProperty(q))
+			expanded = append(expanded, split)
+		}
+	}
+	return expanded
+}
+
+// additionalPropertyName returns the name of the property of an additionalProperties violation, that has been
+// split by ExpandAdditionalPropertiesErrors.
+func additionalPropertyName(er jsonschema.BasicError) (string, bool) {
+	m := additionalPropertiesRegex.FindStringSubmatch(er.Error)
+	if m == nil || quotedPropertyRegex.FindString(m[1]) != m[1] {
+		return "", false
+	}
+	return unquoteProperty(m[1]), true
+}
+
+// unquoteProperty reverses the quoting applied to property names by jsonschema.
+func unquoteProperty(quoted string) string {
+	s := strings.ReplaceAll(quoted[1:len(quoted)-1], `\'`, `'`)
+	if unquoted, err := strconv.Unquote(`"` + strings.ReplaceAll(s, `"`, `\"`) + `"`); err == nil {
+		return unquoted
+	}
+	return s
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func parentInstanceLocation(instanceLocation string) string {
+	if i := strings.LastIndex(instanceLocation, "/"); i >= 0 {
+		return instanceLocation[:i]
+	}
+	return instanceLocation
+}
+
+// LocateSchemaViolationNode will locate the node in the rendered schema that a flattened jsonschema error
+// is referring to. Object size violations (minProperties / maxProperties) are located at the object that owns
+// the keyword, rather than the keyword value itself. Violations of the root object have no owning key, so nil is returned.
+func LocateSchemaViolationNode(renderedSchema *yaml.Node, er jsonschema.BasicError) *yaml.Node {
+	keywordLocation := er.KeywordLocation
+	if strings.HasSuffix(keywordLocation, "/minProperties") || strings.HasSuffix(keywordLocation, "/maxProperties") {
+		keywordLocation = keywordLocation[:strings.LastIndex(keywordLocation, "/")]
+		if keywordLocation == "" {
+			return nil
+		}
+	}
+	return LocateSchemaPropertyNodeByJSONPath(renderedSchema, keywordLocation)
+}
+
+func describeInstance(instanceLocation string) string {
+	if instanceLocation == "" {
+		return "The object"
+	}
+	return fmt.Sprintf("The object at '%s'", instanceLocation)
+}
+
+func describeArray(instanceLocation string) string {
+	if instanceLocation == "" {
+		return "The array"
+	}
+	return fmt.Sprintf("The array at '%s'", instanceLocation)
+}
+
+// LocateValidationErrorCause will walk the causes of a jsonschema error, and return the specific error that a
+// flattened jsonschema error was created from. This is the leaf responsible for the failure, rather than the root
+// of the chain. If no matching cause can be found, the root is returned.
+func LocateValidationErrorCause(root *jsonschema.ValidationError, er jsonschema.BasicError) *jsonschema.ValidationError {
+	if root == nil {
+		return nil
+	}
+	if cause := findValidationErrorCause(root, er); cause != nil {
+		return cause
+	}
+	return root
+}
+
+func findValidationErrorCause(ve *jsonschema.ValidationError, er jsonschema.BasicError) *jsonschema.ValidationError {
+	if ve.KeywordLocation == er.KeywordLocation &&
+		ve.AbsoluteKeywordLocation == er.AbsoluteKeywordLocation &&
+		ve.InstanceLocation == er.InstanceLocation &&
+		ve.Message == er.Error {
+		return ve
+	}
+	// additionalProperties violations that have been split, are caused by the violation of the owning object.
+	if _, ok := additionalPropertyName(er); ok &&
+		ve.KeywordLocation == er.KeywordLocation &&
+		ve.AbsoluteKeywordLocation == er.AbsoluteKeywordLocation &&
+		ve.InstanceLocation == parentInstanceLocation(er.InstanceLocation) &&
+		additionalPropertiesRegex.MatchString(ve.Message) {
+		return ve
+	}
+	for _, cause := range ve.Causes {
+		if found := findValidationErrorCause(cause, er); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -4,39 +4,100 @@
 package schema_validation
 
 import (
-	"github.com/pb33f/libopenapi/utils"
-	"github.com/vmware-labs/yaml-jsonpath/pkg/yamlpath"
+	"strconv"
+	"strings"
+
 	"gopkg.in/yaml.v3"
 )
 
 // LocateSchemaPropertyNodeByJSONPath will locate a schema property node by a JSONPath. It converts something like
 // #/components/schemas/MySchema/properties/MyProperty to something like $.components.schemas.MySchema.properties.MyProperty
-func LocateSchemaPropertyNodeByJSONPath(doc *yaml.Node, JSONPath string) *yaml.Node {
-	var locatedNode *yaml.Node
-	doneChan := make(chan bool)
-	locatedNodeChan := make(chan *yaml.Node)
-	go func() {
-		defer func() {
-			if err := recover(); err != nil {
-				// can't search path, too crazy.
-				doneChan <- true
-			}
-		}()
-		_, path := utils.ConvertComponentIdIntoFriendlyPathSearch(JSONPath)
-		if path == "" {
-			doneChan <- true
-		}
-		yamlPath, _ := yamlpath.NewPath(path)
-		locatedNodes, _ := yamlPath.Find(doc)
-		if len(locatedNodes) > 0 {
-			locatedNode = locatedNodes[0]
+//
+// The node is only read, never modified, so it is safe to locate nodes within the same document from many goroutines
+// at once. The located node belongs to the document, so it must not be modified either if the document is shared.
+func LocateSchemaPropertyNodeByJSONPath(doc *yaml.Node, JSONPath string) (locatedNode *yaml.Node) {
+	defer func() {
+		if err := recover(); err != nil {
+			// can't search path, too crazy.
+			locatedNode = nil
 		}
-		locatedNodeChan <- locatedNode
 	}()
-	select {
-	case locatedNode = <-locatedNodeChan:
-		return locatedNode
-	case <-doneChan:
+	path := strings.TrimPrefix(JSONPath, "#")
+	if path == "" {
 		return nil
 	}
+	return locateJSONPointer(doc, path)
+}
+
+// locateJSONPointer will walk a node by the tokens of a JSON pointer. Maps are walked by key, and arrays by index, so
+// the schemas of array items (prefixItems, allOf etc.) and property names that look like an index are both located
+// correctly. Returns nil if the pointer cannot be walked.
+func locateJSONPointer(node *yaml.Node, pointer string) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if node == nil {
+			return nil
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node.Kind {
+		case yaml.MappingNode:
+			var value *yaml.Node
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					value = node.Content[i+1]
+					break
+				}
+			}
+			node = value
+		case yaml.SequenceNode:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[i]
+		default:
+			return nil
+		}
+	}
+	return node
+}
+
+// LocateSchemaViolationLine will return the line a violation of a located node should be reported on. Scalars are
+// reported on their own line. Maps and arrays are reported on the line of their key, unless they are an item of
+// an array (such as a prefixItems schema), which has no key, so they are reported on their own line.
+func LocateSchemaViolationLine(root, located *yaml.Node) int {
+	if located.Kind != yaml.MappingNode && located.Kind != yaml.SequenceNode {
+		return located.Line
+	}
+	parent, key := locateParentNode(root, located)
+	switch {
+	case key != nil:
+		return key.Line
+	case parent != nil && parent.Kind == yaml.SequenceNode:
+		return located.Line
+	case located.Line > 0:
+		return located.Line - 1
+	}
+	return located.Line
+}
+
+// locateParentNode will find the parent of a node, and the key of the node, if the parent is a map.
+func locateParentNode(node, child *yaml.Node) (*yaml.Node, *yaml.Node) {
+	if node == nil {
+		return nil, nil
+	}
+	for i, n := range node.Content {
+		if n == child {
+			if node.Kind == yaml.MappingNode && i%2 == 1 {
+				return node, node.Content[i-1]
+			}
+			return node, nil
+		}
+		if parent, key := locateParentNode(n, child); parent != nil {
+			return parent, key
+		}
+	}
+	return nil, nil
 }
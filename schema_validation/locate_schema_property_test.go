@@ -5,6 +5,8 @@ package schema_validation
 
 import (
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"runtime"
 	"testing"
 )
 
@@ -13,3 +15,39 @@ func TestLocateSchemaPropertyNodeByJSONPath_BadNode(t *testing.T) {
 	assert.Nil(t, LocateSchemaPropertyNodeByJSONPath(nil, ""))
 
 }
+
+func TestLocateSchemaPropertyNodeByJSONPath_EmptyPath(t *testing.T) {
+	var node yaml.Node
+	_ = yaml.Unmarshal([]byte(`type: string`), &node)
+
+	// an empty path (the root of the schema) is not located, and nothing is left running once it returns.
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, LocateSchemaPropertyNodeByJSONPath(node.Content[0], "#"))
+		assert.Nil(t, LocateSchemaPropertyNodeByJSONPath(node.Content[0], ""))
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
+
+func TestLocateSchemaPropertyNodeByJSONPath_ArrayItems(t *testing.T) {
+	var node yaml.Node
+	_ = yaml.Unmarshal([]byte(`properties:
+  "0":
+    prefixItems:
+      - type: string
+      - type: integer
+  a/b:
+    items:
+      type: boolean`), &node)
+
+	located := LocateSchemaPropertyNodeByJSONPath(node.Content[0], "/properties/0/prefixItems/1/type")
+	assert.NotNil(t, located)
+	assert.Equal(t, "integer", located.Value)
+	assert.Equal(t, 5, located.Line)
+
+	located = LocateSchemaPropertyNodeByJSONPath(node.Content[0], "/properties/a~1b/items/type")
+	assert.NotNil(t, located)
+	assert.Equal(t, "boolean", located.Value)
+
+	assert.Nil(t, LocateSchemaPropertyNodeByJSONPath(node.Content[0], "/properties/0/prefixItems/2"))
+}
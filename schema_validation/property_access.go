@@ -0,0 +1,93 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"fmt"
+	"strings"
+
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+)
+
+// AccessMode determines the direction a payload is travelling in, which controls whether readOnly or
+// writeOnly properties are permitted.
+type AccessMode int
+
+const (
+	// RequestAccess is used for request bodies, readOnly properties must not be sent.
+	RequestAccess AccessMode = iota
+
+	// ResponseAccess is used for response bodies, writeOnly properties must not be returned.
+	ResponseAccess
+)
+
+// ValidatePropertyAccess will traverse a schema alongside a decoded payload, and return a failure for every
+// readOnly property found in a request payload, or writeOnly property found in a response payload.
+// Properties are followed through nested objects, array items and allOf compositions.
+func ValidatePropertyAccess(schema *base.Schema, payload any, mode AccessMode) []*liberrors.SchemaValidationFailure {
+	return checkPropertyAccess(schema, payload, mode, "", "")
+}
+
+func checkPropertyAccess(schema *base.Schema, payload any, mode AccessMode,
+	keywordLocation, instanceLocation string,
+) []*liberrors.SchemaValidationFailure {
+	if schema == nil || payload == nil {
+		return nil
+	}
+	var failures []*liberrors.SchemaValidationFailure
+
+	for i, proxy := range schema.AllOf {
+		failures = append(failures, checkPropertyAccess(proxy.Schema(), payload, mode,
+			fmt.Sprintf("%s/allOf/%d", keywordLocation, i), instanceLocation)...)
+	}
+
+	switch value := payload.(type) {
+	case map[string]any:
+		if schema.Properties == nil {
+			return failures
+		}
+		for pair := schema.Properties.First(); pair != nil; pair = pair.Next() {
+			propValue, ok := value[pair.Key()]
+			if !ok {
+				continue
+			}
+			propSchema := pair.Value().Schema()
+			if propSchema == nil {
+				continue
+			}
+			propKeyword := fmt.Sprintf("%s/properties/%s", keywordLocation, escapePointer(pair.Key()))
+			propInstance := fmt.Sprintf("%s/%s", instanceLocation, escapePointer(pair.Key()))
+			switch {
+			case mode == RequestAccess && propSchema.ReadOnly != nil && *propSchema.ReadOnly:
+				failures = append(failures, &liberrors.SchemaValidationFailure{
+					Reason:   fmt.Sprintf("The property '%s' is readOnly, it must not be sent in a request", propInstance),
+					Location: propKeyword + "/readOnly",
+				})
+			case mode == ResponseAccess && propSchema.WriteOnly != nil && *propSchema.WriteOnly:
+				failures = append(failures, &liberrors.SchemaValidationFailure{
+					Reason:   fmt.Sprintf("The property '%s' is writeOnly, it must not be returned in a response", propInstance),
+					Location: propKeyword + "/writeOnly",
+				})
+			default:
+				failures = append(failures, checkPropertyAccess(propSchema, propValue, mode, propKeyword, propInstance)...)
+			}
+		}
+	case []any:
+		if schema.Items == nil || !schema.Items.IsA() {
+			return failures
+		}
+		itemsSchema := schema.Items.A.Schema()
+		for i, item := range value {
+			failures = append(failures, checkPropertyAccess(itemsSchema, item, mode,
+				keywordLocation+"/items", fmt.Sprintf("%s/%d", instanceLocation, i))...)
+		}
+	}
+	return failures
+}
+
+// escapePointer will escape a property name so it can be used as a JSON pointer segment.
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
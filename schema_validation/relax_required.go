@@ -0,0 +1,75 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"encoding/json"
+)
+
+// schema keywords whose value is a map of named sub-schemas.
+var namedSchemaKeywords = []string{"properties", "patternProperties", "dependentSchemas", "$defs", "definitions"}
+
+// schema keywords whose value is a sub-schema, or a list of sub-schemas.
+var subSchemaKeywords = []string{"items", "prefixItems", "additionalItems", "additionalProperties",
+	"unevaluatedProperties", "unevaluatedItems", "contains", "propertyNames", "not", "if", "then", "else",
+	"allOf", "anyOf", "oneOf"}
+
+// RelaxRequired will remove every 'required' keyword from a JSON schema (and all of its sub-schemas), so a partial
+// object (such as a JSON Merge Patch document) is only validated against the schemas of the properties it contains.
+// If the schema cannot be decoded, or has nothing to relax, it is returned as-is.
+func RelaxRequired(jsonSchema []byte) []byte {
+	var decoded any
+	if json.Unmarshal(jsonSchema, &decoded) != nil {
+		return jsonSchema
+	}
+	if !removeRequired(decoded) {
+		return jsonSchema
+	}
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return jsonSchema
+	}
+	return encoded
+}
+
+// removeRequired removes the required keyword from a decoded schema, and all of its sub-schemas. Returns true if
+// anything was removed.
+func removeRequired(schema any) bool {
+	switch s := schema.(type) {
+	case []any:
+		removed := false
+		for _, sub := range s {
+			removed = removeRequired(sub) || removed
+		}
+		return removed
+	case map[string]any:
+		removed := false
+		if _, ok := s["required"].([]any); ok {
+			delete(s, "required")
+			removed = true
+		}
+		for _, keyword := range namedSchemaKeywords {
+			if named, ok := s[keyword].(map[string]any); ok {
+				for _, sub := range named {
+					removed = removeRequired(sub) || removed
+				}
+			}
+		}
+		for _, keyword := range subSchemaKeywords {
+			if sub, ok := s[keyword]; ok {
+				removed = removeRequired(sub) || removed
+			}
+		}
+		// components embedded to resolve circular references (see ResolveCircularReferences).
+		if components, ok := s["components"].(map[string]any); ok {
+			if schemas, ok := components["schemas"].(map[string]any); ok {
+				for _, sub := range schemas {
+					removed = removeRequired(sub) || removed
+				}
+			}
+		}
+		return removed
+	}
+	return false
+}
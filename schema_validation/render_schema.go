@@ -0,0 +1,140 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// RenderSchema will render a schema with every reference inlined, so it can be compiled. A reference that cannot be
+// resolved is left out of the rendering without an error, so the rendered schema accepts payloads the contract does
+// not (or renders as nothing at all). Rather than validating against it, an error is returned that explains the
+// schema could not be rendered, located at the reference that could not be resolved.
+func RenderSchema(schema *base.Schema) ([]byte, *liberrors.ValidationError) {
+	// unresolved references are found first, as a reference created by hand cannot be rendered at all.
+	if reason, location, node := findUnresolvedSchema(schema, "", make(map[string]bool)); reason != "" {
+		return nil, liberrors.SchemaRenderFailed(reason, location, node)
+	}
+	rendered, err := schema.RenderInline()
+	if err != nil {
+		return nil, liberrors.SchemaRenderFailed(err.Error(), "", schemaNode(schema))
+	}
+	if trimmed := bytes.TrimSpace(rendered); len(trimmed) == 0 || string(trimmed) == "null" {
+		return nil, liberrors.SchemaRenderFailed("the schema rendered as nothing", "", schemaNode(schema))
+	}
+	return rendered, nil
+}
+
+// RenderSchemaProxy is the same as RenderSchema, for the schema of a proxy (such as the schema of a media type), which
+// is returned with its rendering. libopenapi does not build a schema at all when a reference within it cannot be
+// resolved, in which case the error explains why the schema could not be built, located at the schema.
+func RenderSchemaProxy(proxy *base.SchemaProxy) (*base.Schema, []byte, *liberrors.ValidationError) {
+	sch := proxySchema(proxy)
+	if sch == nil {
+		reason, node := unbuiltReason(proxy)
+		return nil, nil, liberrors.SchemaRenderFailed(reason, "", node)
+	}
+	rendered, renderError := RenderSchema(sch)
+	return sch, rendered, renderError
+}
+
+// findUnresolvedSchema returns why the first schema within a schema could not be built (such as a reference that
+// cannot be found), and where it is. If every schema could be built, the reason is empty.
+func findUnresolvedSchema(sch *base.Schema, location string, visited map[string]bool) (string, string, *yaml.Node) {
+	var reason, foundLocation string
+	var node *yaml.Node
+	walkSubSchemas(sch, location, func(proxy *base.SchemaProxy, location string) bool {
+		if reason != "" {
+			return false
+		}
+		if proxy.IsReference() {
+			if visited[proxy.GetReference()] {
+				return false
+			}
+			visited[proxy.GetReference()] = true
+		}
+		if proxySchema(proxy) != nil {
+			return true
+		}
+		reason, node = unbuiltReason(proxy)
+		foundLocation = location
+		return false
+	})
+	return reason, foundLocation, node
+}
+
+// unbuiltReason returns why the schema of a proxy could not be built, and the node of the proxy (if it is known).
+func unbuiltReason(proxy *base.SchemaProxy) (string, *yaml.Node) {
+	reason := "the schema could not be built"
+	switch {
+	case proxy == nil:
+		return reason, nil
+	case proxy.GoLow() == nil:
+		reason = fmt.Sprintf("the reference '%s' has not been resolved", proxy.GetReference())
+	case proxy.GetBuildError() != nil:
+		reason = proxy.GetBuildError().Error()
+	}
+	var node *yaml.Node
+	if proxy.GoLow() != nil {
+		node = proxy.GoLow().GetValueNode()
+	}
+	return reason, node
+}
+
+// walkSubSchemas invokes visit with every schema directly within a schema, and its JSON pointer location. Schemas
+// within those schemas are visited as well, unless visit returns false.
+func walkSubSchemas(sch *base.Schema, location string, visit func(proxy *base.SchemaProxy, location string) bool) {
+	if sch == nil {
+		return
+	}
+	walk := func(proxy *base.SchemaProxy, location string) {
+		if proxy != nil && visit(proxy, location) {
+			walkSubSchemas(proxySchema(proxy), location, visit)
+		}
+	}
+	for pair := orderedmap.First(sch.Properties); pair != nil; pair = pair.Next() {
+		walk(pair.Value(), location+"/properties/"+escapeJSONPointer(pair.Key()))
+	}
+	for pair := orderedmap.First(sch.PatternProperties); pair != nil; pair = pair.Next() {
+		walk(pair.Value(), location+"/patternProperties/"+escapeJSONPointer(pair.Key()))
+	}
+	for keyword, proxies := range map[string][]*base.SchemaProxy{
+		"allOf": sch.AllOf, "oneOf": sch.OneOf, "anyOf": sch.AnyOf, "prefixItems": sch.PrefixItems,
+	} {
+		for i, proxy := range proxies {
+			walk(proxy, location+"/"+keyword+"/"+strconv.Itoa(i))
+		}
+	}
+	walk(sch.Not, location+"/not")
+	if sch.Items != nil && sch.Items.IsA() {
+		walk(sch.Items.A, location+"/items")
+	}
+	if sch.AdditionalProperties != nil && sch.AdditionalProperties.IsA() {
+		walk(sch.AdditionalProperties.A, location+"/additionalProperties")
+	}
+}
+
+// proxySchema returns the schema of a proxy, or nil if it cannot be built. A reference proxy that was created by hand
+// (with base.CreateSchemaProxyRef) has nothing to build the schema from, so it is never built.
+func proxySchema(proxy *base.SchemaProxy) *base.Schema {
+	if proxy == nil || (proxy.GoLow() == nil && proxy.IsReference()) {
+		return nil
+	}
+	return proxy.Schema()
+}
+
+// schemaNode returns the node a schema was built from, if it is known.
+func schemaNode(sch *base.Schema) *yaml.Node {
+	if sch.ParentProxy != nil && sch.ParentProxy.GoLow() != nil {
+		return sch.ParentProxy.GoLow().GetValueNode()
+	}
+	return nil
+}
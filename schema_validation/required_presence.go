@@ -0,0 +1,54 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+)
+
+// ValidateRequiredPresence will check that every required top-level property of a schema (including the required
+// properties of its allOf schemas) is present in a JSON payload, without performing full schema validation. Only the
+// keys of the payload are decoded, so this is a cheap pre-check that can reject payloads before they are validated in
+// full. The required properties that are missing are returned, in the order they are required. A payload that is not
+// a JSON object is missing every required property.
+func ValidateRequiredPresence(schema *base.Schema, payload []byte) (bool, []string) {
+	required := requiredProperties(schema, make(map[*base.Schema]bool))
+	if len(required) == 0 {
+		return true, nil
+	}
+	var keys map[string]json.RawMessage
+	if json.Unmarshal(payload, &keys) != nil {
+		keys = nil
+	}
+	var missing []string
+	for _, name := range required {
+		if _, ok := keys[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return len(missing) == 0, missing
+}
+
+// requiredProperties returns the required properties of a schema and its allOf schemas, without duplicates.
+func requiredProperties(schema *base.Schema, visited map[*base.Schema]bool) []string {
+	if schema == nil || visited[schema] {
+		return nil
+	}
+	visited[schema] = true
+	required := slices.Clone(schema.Required)
+	for _, proxy := range schema.AllOf {
+		if proxy == nil {
+			continue
+		}
+		for _, name := range requiredProperties(proxySchema(proxy), visited) {
+			if !slices.Contains(required, name) {
+				required = append(required, name)
+			}
+		}
+	}
+	return required
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// the ECMA-262 regex features that Go's regexp package (RE2) does not support. An escaped backslash before a
+// construct means it is a literal, so it is not reported.
+var unsupportedRegexFeatures = []struct {
+	feature string
+	detect  *regexp.Regexp
+}{
+	{"lookahead assertions", regexp.MustCompile(`(?:^|[^\\])(?:\\\\)*\(\?[=!]`)},
+	{"lookbehind assertions", regexp.MustCompile(`(?:^|[^\\])(?:\\\\)*\(\?<[=!]`)},
+	{"atomic groups", regexp.MustCompile(`(?:^|[^\\])(?:\\\\)*\(\?>`)},
+	{"backreferences", regexp.MustCompile(`(?:^|[^\\])(?:\\\\)*\\(?:[1-9]|k<)`)},
+}
+
+// keywords whose values are data, rather than schemas, so a 'pattern' key within them is not a keyword.
+var dataKeywords = []string{"enum", "const", "default", "example", "examples"}
+
+// CheckUnsupportedPatterns will return a ValidationError describing every pattern in a rendered schema (YAML or JSON)
+// that cannot be compiled by Go's regexp package, for example because it uses a lookahead. The jsonschema compiler
+// refuses such schemas, so without this check the reason a schema cannot be used is hard to find. If every pattern is
+// supported, nil is returned.
+func CheckUnsupportedPatterns(renderedSchema []byte) *liberrors.ValidationError {
+	failures := LocateUnsupportedPatterns(renderedSchema)
+	if len(failures) == 0 {
+		return nil
+	}
+	return liberrors.SchemaPatternUnsupported(failures, string(renderedSchema))
+}
+
+// LocateUnsupportedPatterns will locate every pattern in a rendered schema (YAML or JSON) that cannot be compiled by
+// Go's regexp package. Both 'pattern' keywords and the keys of 'patternProperties' are checked, and each unsupported
+// pattern is returned as a failure, located at the pattern within the rendered schema.
+func LocateUnsupportedPatterns(renderedSchema []byte) []*liberrors.SchemaValidationFailure {
+	var root yaml.Node
+	if yaml.Unmarshal(renderedSchema, &root) != nil {
+		return nil
+	}
+	return locateUnsupportedPatterns(&root, "", nil)
+}
+
+func locateUnsupportedPatterns(node *yaml.Node, pointer string,
+	failures []*liberrors.SchemaValidationFailure) []*liberrors.SchemaValidationFailure {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			failures = locateUnsupportedPatterns(n, pointer, failures)
+		}
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			failures = locateUnsupportedPatterns(n, pointer+"/"+strconv.Itoa(i), failures)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			location := pointer + "/" + escapeJSONPointer(key.Value)
+			switch {
+			case slices.Contains(dataKeywords, key.Value):
+				continue
+			case key.Value == "pattern" && value.Kind == yaml.ScalarNode:
+				if failure := unsupportedPattern(value.Value, location, value); failure != nil {
+					failures = append(failures, failure)
+				}
+				continue
+			case key.Value == "patternProperties" && value.Kind == yaml.MappingNode:
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					propertyLocation := location + "/" + escapeJSONPointer(value.Content[j].Value)
+					if failure := unsupportedPattern(value.Content[j].Value, propertyLocation, value.Content[j]); failure != nil {
+						failures = append(failures, failure)
+					}
+					failures = locateUnsupportedPatterns(value.Content[j+1], propertyLocation, failures)
+				}
+				continue
+			case slices.Contains(namedSchemaKeywords, key.Value) && value.Kind == yaml.MappingNode:
+				// the keys are names, not keywords, so only the schemas they name are checked.
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					failures = locateUnsupportedPatterns(value.Content[j+1],
+						location+"/"+escapeJSONPointer(value.Content[j].Value), failures)
+				}
+				continue
+			}
+			failures = locateUnsupportedPatterns(value, location, failures)
+		}
+	}
+	return failures
+}
+
+// unsupportedPattern returns a failure for a pattern that cannot be compiled, naming the regex feature responsible
+// when it can be identified. If the pattern compiles, nil is returned.
+func unsupportedPattern(pattern, location string, node *yaml.Node) *liberrors.SchemaValidationFailure {
+	_, err := regexp.Compile(pattern)
+	if err == nil {
+		return nil
+	}
+	reason := fmt.Sprintf("pattern '%s' cannot be compiled by Go's regexp package: %s", pattern, err.Error())
+	for _, unsupported := range unsupportedRegexFeatures {
+		if unsupported.detect.MatchString(pattern) {
+			reason = fmt.Sprintf("pattern '%s' uses %s, which are not supported by Go's regexp package",
+				pattern, unsupported.feature)
+			break
+		}
+	}
+	return &liberrors.SchemaValidationFailure{
+		Reason:     reason,
+		Location:   location,
+		Line:       node.Line,
+		Column:     node.Column,
+		SchemaNode: node,
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/santhosh-tekuri/jsonschema/v5"
 	_ "github.com/santhosh-tekuri/jsonschema/v5/httploader"
-	"gopkg.in/yaml.v3"
 	"strings"
 )
 
@@ -38,7 +37,7 @@ func ValidateOpenAPIDocument(doc libopenapi.Document) (bool, []*liberrors.Valida
 		if errors.As(scErrs, &jk) {
 
 			// flatten the validationErrors
-			schFlatErrs := jk.BasicOutput().Errors
+			schFlatErrs := ExpandAdditionalPropertiesErrors(jk.BasicOutput().Errors)
 
 			for q := range schFlatErrs {
 				er := schFlatErrs[q]
@@ -58,25 +57,23 @@ func ValidateOpenAPIDocument(doc libopenapi.Document) (bool, []*liberrors.Valida
 					violation := &liberrors.SchemaValidationFailure{
 						Reason:           er.Error,
 						Location:         er.InstanceLocation,
+						InstanceLocation: er.InstanceLocation,
 						DeepLocation:     er.KeywordLocation,
 						AbsoluteLocation: er.AbsoluteKeywordLocation,
 						OriginalError:    jk,
+						LeafError:        LocateValidationErrorCause(jk, er),
 					}
 
 					// if we have a location within the schema, add it to the error
 					if located != nil {
-						line := located.Line
 						// if the located node is a map or an array, then the actual human interpretable
 						// line on which the violation occurred is the line of the key, not the value.
-						if located.Kind == yaml.MappingNode || located.Kind == yaml.SequenceNode {
-							if line > 0 {
-								line--
-							}
-						}
+						line := LocateSchemaViolationLine(info.RootNode.Content[0], located)
 
 						// location of the violation within the rendered schema.
 						violation.Line = line
 						violation.Column = located.Column
+						violation.SchemaNode = located
 					}
 					schemaValidationErrors = append(schemaValidationErrors, violation)
 				}
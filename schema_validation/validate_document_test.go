@@ -5,6 +5,7 @@ package schema_validation
 
 import (
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
@@ -75,3 +76,137 @@ components:
 	assert.Len(t, errors, 1)
 	assert.Len(t, errors[0].SchemaValidationErrors, 1)
 }
+
+func TestValidateDocumentEnumValues(t *testing.T) {
+	spec := `openapi: 3.0.0
+paths:
+  /burgers/{size}:
+    get:
+      parameters:
+        - name: size
+          in: path
+          required: true
+          schema:
+            type: integer
+            maximum: 10
+            enum: [1, 5, 20]
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+          enum: [cheese, 12]
+        rating:
+          type: number
+          minimum: 0
+          exclusiveMinimum: true
+          enum: [0, 1.5]
+        eaten:
+          type: string
+          format: date
+          nullable: true
+          enum: ["2023-01-01", null]`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	valid, errors := ValidateDocumentEnumValues(&m.Model)
+	assert.False(t, valid)
+	assert.Len(t, errors, 3)
+
+	var locations []string
+	for _, e := range errors {
+		assert.Equal(t, "invalidEnumValue", e.ValidationSubType)
+		assert.NotEmpty(t, e.SchemaValidationErrors)
+		locations = append(locations, e.SchemaValidationErrors[0].Location)
+	}
+	assert.ElementsMatch(t, []string{
+		"/components/schemas/Burger/properties/name/enum/1",
+		"/components/schemas/Burger/properties/rating/enum/0",
+		"/paths/~1burgers~1{size}/get/parameters/0/schema/enum/2",
+	}, locations)
+}
+
+func TestValidateEnumValues_Valid(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Size:
+      type: string
+      maxLength: 6
+      enum: [small, medium, large]`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	valid, errors := ValidateEnumValues(m.Model.Components.Schemas.GetOrZero("Size").Schema())
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+}
+
+func TestValidateDocumentExamples(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Burger'
+            examples:
+              good:
+                value:
+                  patties: 2
+              bad:
+                value:
+                  patties: many
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Burger:
+      type: object
+      examples:
+        - patties: 1
+        - patties: -1
+      properties:
+        patties:
+          type: integer
+          minimum: 0
+          default: "two"`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	valid, errors := ValidateDocumentExamples(&m.Model)
+	assert.False(t, valid)
+	assert.Len(t, errors, 3)
+
+	assert.Equal(t, helpers.SchemaExampleInvalid, errors[0].ValidationSubType)
+	assert.Equal(t, "example {\"patties\":-1} at '/components/schemas/Burger/examples/1' is not valid for its schema",
+		errors[0].Message)
+	assert.Equal(t, 26, errors[0].SpecLine)
+	assert.NotEmpty(t, errors[0].SchemaValidationErrors)
+
+	assert.Equal(t, helpers.SchemaDefaultInvalid, errors[1].ValidationSubType)
+	assert.Equal(t, "default value \"two\" at '/components/schemas/Burger/properties/patties/default' is not "+
+		"valid for its schema", errors[1].Message)
+	assert.Equal(t, 31, errors[1].SpecLine)
+
+	assert.Equal(t, helpers.SchemaExampleInvalid, errors[2].ValidationSubType)
+	assert.Equal(t, "example {\"patties\":\"many\"} at '/paths/~1burgers/post/requestBody/content/application~1json/"+
+		"examples/bad/value' is not valid for its schema", errors[2].Message)
+	assert.Equal(t, 16, errors[2].SpecLine)
+
+	// a schema is validated on its own, as well as within a document.
+	valid, errors = ValidateSchemaExamples(m.Model.Components.Schemas.GetOrZero("Burger").Schema())
+	assert.False(t, valid)
+	assert.Len(t, errors, 2)
+}
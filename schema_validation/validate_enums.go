@@ -0,0 +1,140 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ValidateEnumValues will check that every enum value of a schema (and of every schema it contains) conforms to the
+// type, format and bounds of its schema, for example a string enum that contains a number, or an integer enum with a
+// value greater than its maximum. No request can ever send such a value, so it is almost always an authoring mistake.
+// An error is returned for every bad enum value, located at the value in the specification.
+func ValidateEnumValues(schema *base.Schema) (bool, []*liberrors.ValidationError) {
+	checker := &enumChecker{}
+	newSchemaWalker(checker.checkEnum).walkSchema(schema, "")
+	return len(checker.errors) == 0, checker.errors
+}
+
+// ValidateDocumentEnumValues is the same as ValidateEnumValues, for every schema of a document: the schemas of the
+// components, and the schemas of the parameters, request bodies and responses of every operation.
+func ValidateDocumentEnumValues(document *v3.Document) (bool, []*liberrors.ValidationError) {
+	checker := &enumChecker{}
+	newSchemaWalker(checker.checkEnum).walkDocument(document)
+	return len(checker.errors) == 0, checker.errors
+}
+
+// enumChecker checks the enum values of the schemas visited by a schemaWalker, and collects an error for every bad
+// value.
+type enumChecker struct {
+	errors []*liberrors.ValidationError
+}
+
+// checkEnum validates every enum value of a schema against the type, format and bounds of the schema.
+func (c *enumChecker) checkEnum(sch *base.Schema, location string) {
+	if len(sch.Enum) == 0 {
+		return
+	}
+	constraints, _ := json.Marshal(enumConstraints(sch))
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat = true
+	_ = compiler.AddResource("enum.json", strings.NewReader(string(constraints)))
+	jsch, err := compiler.Compile("enum.json")
+	if err != nil {
+		return // a schema that cannot be compiled (such as an unsupported pattern) is reported elsewhere.
+	}
+
+	for i, enumValue := range sch.Enum {
+		if enumValue == nil {
+			continue
+		}
+		var decoded any
+		_ = enumValue.Decode(&decoded)
+		encoded, _ := json.Marshal(decoded)
+		var value any
+		_ = json.Unmarshal(encoded, &value)
+
+		var jk *jsonschema.ValidationError
+		if scErrs := jsch.Validate(value); !errors.As(scErrs, &jk) {
+			continue
+		}
+		enumLocation := fmt.Sprintf("%s/enum/%d", location, i)
+		var failures []*liberrors.SchemaValidationFailure
+		for _, er := range FlattenValidationErrors(jk, false) {
+			if er.Error == "" {
+				continue
+			}
+			failures = append(failures, &liberrors.SchemaValidationFailure{
+				Reason:     GetFailureReason(er),
+				Location:   enumLocation,
+				Line:       enumValue.Line,
+				Column:     enumValue.Column,
+				SchemaNode: enumValue,
+			})
+		}
+		c.errors = append(c.errors, liberrors.SchemaEnumValueInvalid(string(encoded), enumLocation, enumValue,
+			failures))
+	}
+}
+
+// enumConstraints returns a JSON schema with only the type, format and bounds keywords of a schema, those that every
+// enum value must satisfy. An exclusive minimum (or maximum) written as a boolean, as in OpenAPI 3.0, is converted
+// into the number form of JSON schema.
+func enumConstraints(sch *base.Schema) map[string]any {
+	constraints := make(map[string]any)
+	types := append([]string{}, sch.Type...)
+	if len(types) > 0 && sch.Nullable != nil && *sch.Nullable {
+		types = append(types, helpers.Null)
+	}
+	if len(types) > 0 {
+		constraints["type"] = types
+	}
+	if sch.Format != "" {
+		constraints["format"] = sch.Format
+	}
+	if sch.Minimum != nil {
+		constraints["minimum"] = *sch.Minimum
+		if sch.ExclusiveMinimum != nil && sch.ExclusiveMinimum.IsA() && sch.ExclusiveMinimum.A {
+			delete(constraints, "minimum")
+			constraints["exclusiveMinimum"] = *sch.Minimum
+		}
+	}
+	if sch.ExclusiveMinimum != nil && sch.ExclusiveMinimum.IsB() {
+		constraints["exclusiveMinimum"] = sch.ExclusiveMinimum.B
+	}
+	if sch.Maximum != nil {
+		constraints["maximum"] = *sch.Maximum
+		if sch.ExclusiveMaximum != nil && sch.ExclusiveMaximum.IsA() && sch.ExclusiveMaximum.A {
+			delete(constraints, "maximum")
+			constraints["exclusiveMaximum"] = *sch.Maximum
+		}
+	}
+	if sch.ExclusiveMaximum != nil && sch.ExclusiveMaximum.IsB() {
+		constraints["exclusiveMaximum"] = sch.ExclusiveMaximum.B
+	}
+	if sch.MultipleOf != nil {
+		constraints["multipleOf"] = *sch.MultipleOf
+	}
+	if sch.Pattern != "" {
+		constraints["pattern"] = sch.Pattern
+	}
+	for keyword, value := range map[string]*int64{
+		"minLength": sch.MinLength, "maxLength": sch.MaxLength, "minItems": sch.MinItems, "maxItems": sch.MaxItems,
+		"minProperties": sch.MinProperties, "maxProperties": sch.MaxProperties,
+	} {
+		if value != nil {
+			constraints[keyword] = *value
+		}
+	}
+	return constraints
+}
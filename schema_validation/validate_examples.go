@@ -0,0 +1,60 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"encoding/json"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateExample will validate the value of an example object against a schema. Examples with an inline value are
+// validated as they are. Examples that reference an external value (via 'externalValue') are fetched using the
+// fetcher configured with config.WithExampleFetcher. Fetching is disabled by default, so without a fetcher an
+// external example cannot be validated, and an error explains why. Examples without any value are valid.
+func ValidateExample(schema *base.Schema, example *base.Example, opts ...config.Option) (bool, []*liberrors.ValidationError) {
+	if example == nil {
+		return true, nil
+	}
+	options := config.NewValidationOptions(opts...)
+
+	var payload []byte
+	switch {
+	case example.Value != nil:
+		// the value has already been parsed from the specification, so it can always be decoded.
+		var decoded any
+		_ = example.Value.Decode(&decoded)
+		payload, _ = json.Marshal(decoded)
+	case example.ExternalValue != "":
+		if options.ExampleFetcher == nil {
+			return exampleFailure(liberrors.ExampleFetchingDisabled(example), options)
+		}
+		fetched, err := options.ExampleFetcher.FetchExample(example.ExternalValue)
+		if err != nil {
+			return exampleFailure(liberrors.ExampleFetchFailed(example, err), options)
+		}
+		// external values may be JSON or YAML, anything that cannot be decoded is reported by the validator.
+		payload = fetched
+		var decoded any
+		if yaml.Unmarshal(fetched, &decoded) == nil {
+			if encoded, err := json.Marshal(decoded); err == nil {
+				payload = encoded
+			}
+		}
+	default:
+		return true, nil
+	}
+	return NewSchemaValidator(opts...).ValidateSchemaBytes(schema, payload)
+}
+
+// exampleFailure returns an example that could not be validated as a failed validation.
+func exampleFailure(validationError *liberrors.ValidationError,
+	options *config.ValidationOptions) (bool, []*liberrors.ValidationError) {
+	validationErrors := []*liberrors.ValidationError{validationError}
+	liberrors.FormatValidationErrors(validationErrors, options.MessageFormatterFor)
+	return false, validationErrors
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi/utils"
+)
+
+// ValidateRawSchema will validate a payload (a JSON/YAML blob) against a schema that is also a JSON/YAML blob, rather
+// than a *base.Schema, for example a schema read from configuration or a fragment of a document. The schema is
+// compiled as it is, outside of any OpenAPI document, so it can only reference itself. The errors returned are the
+// same as those of a SchemaValidator, and the lines and columns of failures are located within the schema bytes.
+func ValidateRawSchema(schemaBytes []byte, payload []byte, opts ...config.Option) (bool, []*liberrors.ValidationError) {
+	options := config.NewValidationOptions(opts...)
+	valid, validationErrors := validateRawSchema(schemaBytes, payload, options)
+	liberrors.FormatValidationErrors(validationErrors, options.MessageFormatterFor)
+	return valid, validationErrors
+}
+
+func validateRawSchema(schemaBytes []byte, payload []byte,
+	options *config.ValidationOptions) (bool, []*liberrors.ValidationError) {
+
+	jsonSchema, err := utils.ConvertYAMLtoJSON(schemaBytes)
+	if err == nil && (len(bytes.TrimSpace(schemaBytes)) == 0 || bytes.Equal(jsonSchema, []byte("null"))) {
+		err = errors.New("the schema is empty")
+	}
+	if err != nil {
+		return false, []*liberrors.ValidationError{rawSchemaError(schemaBytes, err)}
+	}
+
+	jsch, err := compileJSONSchema(jsonSchema)
+	if err != nil {
+		if compileError := compileSchemaError(schemaBytes, payload, err); compileError != nil {
+			return false, []*liberrors.ValidationError{compileError}
+		}
+		// the schema is not within a document, so a reference that cannot be resolved is only found here.
+		return false, []*liberrors.ValidationError{rawSchemaError(schemaBytes, err)}
+	}
+
+	if len(payload) == 0 {
+		return true, nil
+	}
+	var decodedObject interface{}
+	if err = helpers.UnmarshalJSON(payload, &decodedObject, options.UseJSONNumber); err != nil {
+		return false, []*liberrors.ValidationError{payloadDecodeError(schemaBytes, payload, err)}
+	}
+	validationErrors := validateDecodedObject(nil, jsch, schemaBytes, decodedObject, payload, options, "")
+	return len(validationErrors) == 0, validationErrors
+}
+
+// rawSchemaError returns a ValidationError for a raw schema that cannot be decoded or compiled.
+func rawSchemaError(schemaBytes []byte, err error) *liberrors.ValidationError {
+	return &liberrors.ValidationError{
+		ValidationType: helpers.Schema,
+		Message:        "schema cannot be compiled",
+		Reason:         fmt.Sprintf("The schema cannot be decoded or compiled: %s", err.Error()),
+		SpecLine:       1,
+		SpecCol:        0,
+		HowToFix:       liberrors.HowToFixInvalidSchema,
+		Context:        string(schemaBytes), // attach the schema to the error
+	}
+}
@@ -4,17 +4,23 @@
 package schema_validation
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
 	_ "embed"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/pb33f/libopenapi-validator/config"
 	liberrors "github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/utils"
 	"github.com/santhosh-tekuri/jsonschema/v5"
 	_ "github.com/santhosh-tekuri/jsonschema/v5/httploader"
 	"gopkg.in/yaml.v3"
+	"io"
 	"log/slog"
 	"os"
 	"reflect"
@@ -30,6 +36,10 @@ import (
 //	ValidateSchemaString accepts a schema object to validate against, and a JSON/YAML blob that is defined as a string.
 //	ValidateSchemaObject accepts a schema object to validate against, and an object, created from unmarshalled JSON/YAML.
 //	ValidateSchemaBytes accepts a schema object to validate against, and a JSON/YAML blob that is defined as a byte array.
+//	ValidateSchemaNDJSON accepts a schema object to validate each record against, and a reader of newline-delimited JSON.
+//	ValidateSchemaJSONArray accepts an array schema object, and a reader of a JSON array to validate one item at a time.
+//	ValidateSchemas accepts several schema objects to validate against, and a JSON/YAML blob defined as a byte array.
+//	ValidateSchemaBytesDecoded is ValidateSchemaBytes, and also returns the object the blob was decoded into.
 type SchemaValidator interface {
 
 	// ValidateSchemaString accepts a schema object to validate against, and a JSON/YAML blob that is defined as a string.
@@ -42,38 +52,331 @@ type SchemaValidator interface {
 	// ValidateSchemaBytes accepts a schema object to validate against, and a byte slice containing a schema to
 	// validate against.
 	ValidateSchemaBytes(schema *base.Schema, payload []byte) (bool, []*liberrors.ValidationError)
+
+	// ValidateSchemaNDJSON accepts a schema object to validate each record against, and a reader containing
+	// newline-delimited JSON (NDJSON). Every line is decoded and validated as a separate record, the schema is only
+	// compiled once. Any errors returned are annotated with the line number of the record that failed.
+	ValidateSchemaNDJSON(schema *base.Schema, reader io.Reader) (bool, []*liberrors.ValidationError)
+
+	// ValidateSchemaJSONArray accepts an array schema object, and a reader containing a JSON array. The array is
+	// streamed, so every item is decoded and validated against the items schema on its own, and only one item is held
+	// in memory at a time. The minItems, maxItems and uniqueItems of the array schema are also checked, items are
+	// compared for uniqueItems by a hash of their canonical JSON, so only the hash of every item is kept. Any errors
+	// returned are annotated with the index of the item that failed.
+	ValidateSchemaJSONArray(schema *base.Schema, reader io.Reader) (bool, []*liberrors.ValidationError)
+
+	// ValidateSchemas accepts several schema objects, and a JSON/YAML blob defined as a byte array. The payload is
+	// validated against every schema (as if they were combined with allOf), and the errors of all of them are
+	// returned. The message of each error is prefixed with the index of the schema that failed, so messages such as
+	// the one for a schema that cannot be rendered are kept.
+	ValidateSchemas(schemas []*base.Schema, payload []byte) (bool, []*liberrors.ValidationError)
+
+	// ValidateSchemaBytesDecoded is the same as ValidateSchemaBytes, except the object the payload was decoded into is
+	// also returned, so callers that use the payload after validating it do not need to decode it again. The object is
+	// returned whether it is valid or not, and is nil if the payload is empty or cannot be decoded. Numbers are
+	// decoded as json.Number if config.WithJSONNumber is used, so large integers keep their precision.
+	ValidateSchemaBytesDecoded(schema *base.Schema, payload []byte) (bool, []*liberrors.ValidationError, interface{})
 }
 
+// maxNDJSONRecordSize is the largest single record (line) that ValidateSchemaNDJSON will read.
+const maxNDJSONRecordSize = 10 * 1024 * 1024
+
 var instanceLocationRegex = regexp.MustCompile(`^/(\d+)`)
 
 type schemaValidator struct {
-	logger *slog.Logger
-	lock   sync.Mutex
+	logger  *slog.Logger
+	options *config.ValidationOptions
+	lock    sync.Mutex
 }
 
 // NewSchemaValidatorWithLogger will create a new SchemaValidator instance, ready to accept schemas and payloads to validate.
-func NewSchemaValidatorWithLogger(logger *slog.Logger) SchemaValidator {
-	return &schemaValidator{logger: logger, lock: sync.Mutex{}}
+// Options can be supplied, for example config.WithSchemaFailureVisitor will stream each failure to a callback.
+func NewSchemaValidatorWithLogger(logger *slog.Logger, opts ...config.Option) SchemaValidator {
+	return &schemaValidator{logger: logger, options: config.NewValidationOptions(opts...), lock: sync.Mutex{}}
 }
 
 // NewSchemaValidator will create a new SchemaValidator instance, ready to accept schemas and payloads to validate.
-func NewSchemaValidator() SchemaValidator {
+// Options can be supplied, for example config.WithSchemaFailureVisitor will stream each failure to a callback.
+func NewSchemaValidator(opts ...config.Option) SchemaValidator {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelError,
 	}))
-	return NewSchemaValidatorWithLogger(logger)
+	return NewSchemaValidatorWithLogger(logger, opts...)
+}
+
+// ValidateNamedSchema will look up a schema by its name in 'components.schemas' of the document, and validate the
+// payload (a JSON/YAML blob) against it. If the named schema does not exist, a validation error is returned.
+func ValidateNamedSchema(document *v3.Document, schemaName string, payload []byte) (bool, []*liberrors.ValidationError) {
+	var proxy *base.SchemaProxy
+	if document != nil && document.Components != nil && document.Components.Schemas != nil {
+		proxy = document.Components.Schemas.GetOrZero(schemaName)
+	}
+	if proxy == nil || proxy.Schema() == nil {
+		return false, []*liberrors.ValidationError{liberrors.SchemaNotFound(schemaName)}
+	}
+	return NewSchemaValidator().ValidateSchemaBytes(proxy.Schema(), payload)
 }
 
 func (s *schemaValidator) ValidateSchemaString(schema *base.Schema, payload string) (bool, []*liberrors.ValidationError) {
-	return s.validateSchema(schema, []byte(payload), nil, s.logger)
+	return s.formatErrors(s.validateSchema(schema, []byte(payload), nil, s.logger))
 }
 
 func (s *schemaValidator) ValidateSchemaObject(schema *base.Schema, payload interface{}) (bool, []*liberrors.ValidationError) {
-	return s.validateSchema(schema, nil, payload, s.logger)
+	return s.formatErrors(s.validateSchema(schema, nil, payload, s.logger))
 }
 
 func (s *schemaValidator) ValidateSchemaBytes(schema *base.Schema, payload []byte) (bool, []*liberrors.ValidationError) {
-	return s.validateSchema(schema, payload, nil, s.logger)
+	return s.formatErrors(s.validateSchema(schema, payload, nil, s.logger))
+}
+
+func (s *schemaValidator) ValidateSchemaBytesDecoded(schema *base.Schema,
+	payload []byte) (bool, []*liberrors.ValidationError, interface{}) {
+
+	// if the payload cannot be decoded, it is decoded again while it is validated, which reports the error.
+	var decodedObject interface{}
+	if len(payload) > 0 && helpers.UnmarshalJSON(payload, &decodedObject, s.options.UseJSONNumber) != nil {
+		decodedObject = nil
+	}
+	valid, validationErrors := s.formatErrors(s.validateSchema(schema, payload, decodedObject, s.logger))
+	return valid, validationErrors, decodedObject
+}
+
+func (s *schemaValidator) ValidateSchemas(schemas []*base.Schema, payload []byte) (bool, []*liberrors.ValidationError) {
+	// the payload is the same for every schema, so it is only decoded once. If it cannot be decoded, the error is
+	// reported once, by the first schema.
+	var decodedObject interface{}
+	if len(payload) > 0 {
+		if err := helpers.UnmarshalJSON(payload, &decodedObject, s.options.UseJSONNumber); err != nil && len(schemas) > 0 {
+			schemas = schemas[:1]
+		}
+	}
+
+	valid := true
+	var validationErrors []*liberrors.ValidationError
+	for i, schema := range schemas {
+		schemaValid, schemaErrors := s.validateSchema(schema, payload, decodedObject, s.logger)
+		valid = valid && schemaValid
+		for _, ve := range schemaErrors {
+			ve.Message = fmt.Sprintf("schema at index %d: %s", i, ve.Message)
+			ve.Reason = fmt.Sprintf("The payload failed to validate against the schema at index %d: %s", i, ve.Reason)
+			validationErrors = append(validationErrors, ve)
+		}
+	}
+	return s.formatErrors(valid, validationErrors)
+}
+
+// formatErrors will apply any configured message formatters to the errors of a validation.
+func (s *schemaValidator) formatErrors(valid bool, validationErrors []*liberrors.ValidationError) (bool, []*liberrors.ValidationError) {
+	liberrors.FormatValidationErrors(validationErrors, s.options.MessageFormatterFor)
+	return valid, validationErrors
+}
+
+// compileSchema renders and compiles a schema, so it can be used to validate many payloads.
+func (s *schemaValidator) compileSchema(schema *base.Schema) ([]byte, *jsonschema.Schema, *liberrors.ValidationError) {
+	s.lock.Lock()
+	renderedSchema, renderError := RenderSchema(schema)
+	s.lock.Unlock()
+	if renderError != nil {
+		return nil, nil, renderError
+	}
+
+	jsonSchema, _ := utils.ConvertYAMLtoJSON(renderedSchema)
+	jsonSchema = renderContentKeywords(schema, jsonSchema)
+	jsonSchema = ResolveCircularReferences(schema, jsonSchema)
+
+	jsch, compileError := compileRenderedSchema(renderedSchema, jsonSchema, nil)
+	return renderedSchema, jsch, compileError
+}
+
+func (s *schemaValidator) ValidateSchemaNDJSON(schema *base.Schema, reader io.Reader) (bool, []*liberrors.ValidationError) {
+
+	var validationErrors []*liberrors.ValidationError
+
+	if schema == nil {
+		s.logger.Info("schema is empty and cannot be validated. This generally means the schema is missing from the spec, or could not be read.")
+		return false, validationErrors
+	}
+
+	// render the schema once, every record is validated against the same compiled schema.
+	renderedSchema, jsch, compileError := s.compileSchema(schema)
+	if compileError != nil {
+		return false, append(validationErrors, compileError)
+	}
+	if jsch == nil {
+		return true, nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxNDJSONRecordSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		record := bytes.TrimSpace(scanner.Bytes())
+		if len(record) == 0 {
+			continue // blank lines are not records.
+		}
+
+		var decodedObject interface{}
+		if err := helpers.UnmarshalJSON(record, &decodedObject, s.options.UseJSONNumber); err != nil {
+			violation := &liberrors.SchemaValidationFailure{
+				Reason:          err.Error(),
+				Location:        "unavailable",
+				ReferenceSchema: string(renderedSchema),
+				ReferenceObject: string(record),
+			}
+			validationErrors = append(validationErrors, &liberrors.ValidationError{
+				ValidationType:         helpers.Schema,
+				Message:                fmt.Sprintf("record on line %d cannot be decoded", lineNumber),
+				Reason:                 fmt.Sprintf("The record on line %d cannot be decoded: %s", lineNumber, err.Error()),
+				SpecLine:               1,
+				SpecCol:                0,
+				SchemaValidationErrors: []*liberrors.SchemaValidationFailure{violation},
+				HowToFix:               liberrors.HowToFixInvalidJSON,
+				Context:                string(renderedSchema), // attach the rendered schema to the error
+			})
+			continue
+		}
+
+		for _, ve := range validateDecodedObject(schema, jsch, renderedSchema, decodedObject, record, s.options, "") {
+			ve.Message = fmt.Sprintf("record on line %d does not pass validation", lineNumber)
+			ve.Reason = fmt.Sprintf("The record on line %d failed to validate against the contract requirements", lineNumber)
+			validationErrors = append(validationErrors, ve)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		validationErrors = append(validationErrors, &liberrors.ValidationError{
+			ValidationType: helpers.Schema,
+			Message:        fmt.Sprintf("record on line %d cannot be read", lineNumber+1),
+			Reason:         fmt.Sprintf("The NDJSON payload cannot be read: %s", err.Error()),
+			SpecLine:       1,
+			SpecCol:        0,
+			HowToFix:       liberrors.HowToFixInvalidEncoding,
+			Context:        string(renderedSchema), // attach the rendered schema to the error
+		})
+	}
+
+	if len(validationErrors) > 0 {
+		return s.formatErrors(false, validationErrors)
+	}
+	return true, nil
+}
+
+func (s *schemaValidator) ValidateSchemaJSONArray(schema *base.Schema, reader io.Reader) (bool, []*liberrors.ValidationError) {
+
+	var validationErrors []*liberrors.ValidationError
+
+	if schema == nil {
+		s.logger.Info("schema is empty and cannot be validated. This generally means the schema is missing from the spec, or could not be read.")
+		return false, validationErrors
+	}
+
+	// compile the items schema once, every item is validated against the same compiled schema. Without an items
+	// schema, any item is valid.
+	var itemsSchema *base.Schema
+	var renderedSchema []byte
+	var jsch *jsonschema.Schema
+	if schema.Items != nil && schema.Items.IsA() && schema.Items.A != nil {
+		itemsSchema = schema.Items.A.Schema()
+	}
+	if itemsSchema != nil {
+		var compileError *liberrors.ValidationError
+		renderedSchema, jsch, compileError = s.compileSchema(itemsSchema)
+		if compileError != nil {
+			return false, append(validationErrors, compileError)
+		}
+	}
+
+	arrayError := func(message, reason, howToFix string) *liberrors.ValidationError {
+		return &liberrors.ValidationError{
+			ValidationType: helpers.Schema,
+			Message:        message,
+			Reason:         reason,
+			SpecLine:       1,
+			SpecCol:        0,
+			HowToFix:       howToFix,
+			Context:        string(renderedSchema), // attach the rendered schema to the error
+		}
+	}
+
+	decoder := json.NewDecoder(reader)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return s.formatErrors(false, append(validationErrors, arrayError("payload is not a JSON array",
+			"The payload must be a JSON array, so it can be validated one item at a time", liberrors.HowToFixInvalidJSON)))
+	}
+
+	// the index of the first item with each hash, if the items must be unique.
+	var seen map[[sha256.Size]byte]int
+	if schema.UniqueItems != nil && *schema.UniqueItems {
+		seen = make(map[[sha256.Size]byte]int)
+	}
+
+	index := 0
+	for ; decoder.More(); index++ {
+		var item json.RawMessage
+		if err := decoder.Decode(&item); err != nil {
+			// the decoder cannot recover from invalid JSON, so nothing after this item can be validated.
+			return s.formatErrors(false, append(validationErrors,
+				arrayError(fmt.Sprintf("array item at index %d cannot be decoded", index),
+					fmt.Sprintf("The array item at index %d cannot be decoded: %s", index, err.Error()),
+					liberrors.HowToFixInvalidJSON)))
+		}
+		if seen != nil {
+			if first, found := trackUniqueItem(seen, item, index); found {
+				validationErrors = append(validationErrors, arrayError("array does not pass validation",
+					fmt.Sprintf("The array items at index %d and %d are equal, however the schema requires unique "+
+						"items (uniqueItems)", first, index), liberrors.HowToFixInvalidSchema))
+			}
+		}
+		if jsch == nil {
+			continue
+		}
+
+		var decodedObject interface{}
+		_ = helpers.UnmarshalJSON(item, &decodedObject, s.options.UseJSONNumber) // the decoder has already checked the item is valid JSON.
+		// failures are located within the array, before they are passed to any failure visitor.
+		for _, ve := range validateDecodedObject(itemsSchema, jsch, renderedSchema, decodedObject, item, s.options,
+			fmt.Sprintf("/%d", index)) {
+			ve.Message = fmt.Sprintf("array item at index %d does not pass validation", index)
+			ve.Reason = fmt.Sprintf("The array item at index %d failed to validate against the contract requirements", index)
+			validationErrors = append(validationErrors, ve)
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		validationErrors = append(validationErrors, arrayError("array cannot be read",
+			fmt.Sprintf("The JSON array cannot be read after %d items: %s", index, err.Error()),
+			liberrors.HowToFixInvalidJSON))
+	}
+
+	if schema.MinItems != nil && int64(index) < *schema.MinItems {
+		validationErrors = append(validationErrors, arrayError("array does not pass validation",
+			fmt.Sprintf("The array has %d items, however the schema requires a minimum of %d items (minItems)",
+				index, *schema.MinItems), liberrors.HowToFixInvalidSchema))
+	}
+	if schema.MaxItems != nil && int64(index) > *schema.MaxItems {
+		validationErrors = append(validationErrors, arrayError("array does not pass validation",
+			fmt.Sprintf("The array has %d items, however the schema allows a maximum of %d items (maxItems)",
+				index, *schema.MaxItems), liberrors.HowToFixInvalidSchema))
+	}
+
+	if len(validationErrors) > 0 {
+		return s.formatErrors(false, validationErrors)
+	}
+	return true, nil
+}
+
+// trackUniqueItem records the hash of the canonical JSON of an array item (with sorted keys, and numbers that are
+// equal encoded the same way), and returns the index of an earlier item with the same hash, if there is one.
+func trackUniqueItem(seen map[[sha256.Size]byte]int, item json.RawMessage, index int) (int, bool) {
+	var decoded any
+	_ = json.Unmarshal(item, &decoded)
+	canonical, _ := json.Marshal(decoded)
+	hash := sha256.Sum256(canonical)
+	if first, found := seen[hash]; found {
+		return first, true
+	}
+	seen[hash] = index
+	return 0, false
 }
 
 func (s *schemaValidator) validateSchema(schema *base.Schema, payload []byte, decodedObject interface{}, log *slog.Logger) (bool, []*liberrors.ValidationError) {
@@ -87,59 +390,98 @@ func (s *schemaValidator) validateSchema(schema *base.Schema, payload []byte, de
 
 	// extract index of schema, and check the version
 	//schemaIndex := schema.GoLow().Index
-	var renderedSchema []byte
 
 	// render the schema, to be used for validation, stop this from running concurrently, mutations are made to state
 	// and, it will cause async issues.
 	s.lock.Lock()
-	renderedSchema, _ = schema.RenderInline()
+	renderedSchema, renderError := RenderSchema(schema)
 	s.lock.Unlock()
+	if renderError != nil {
+		return false, []*liberrors.ValidationError{renderError}
+	}
 
 	jsonSchema, _ := utils.ConvertYAMLtoJSON(renderedSchema)
+	jsonSchema = renderContentKeywords(schema, jsonSchema)
+	jsonSchema = ResolveCircularReferences(schema, jsonSchema)
 
 	if decodedObject == nil && len(payload) > 0 {
-		err := json.Unmarshal(payload, &decodedObject)
+		err := helpers.UnmarshalJSON(payload, &decodedObject, s.options.UseJSONNumber)
 
 		if err != nil {
 			// cannot decode the request body, so it's not valid
-			violation := &liberrors.SchemaValidationFailure{
-				Reason:          err.Error(),
-				Location:        "unavailable",
-				ReferenceSchema: string(renderedSchema),
-				ReferenceObject: string(payload),
-			}
-			validationErrors = append(validationErrors, &liberrors.ValidationError{
-				ValidationType:         helpers.RequestBodyValidation,
-				ValidationSubType:      helpers.Schema,
-				Message:                "schema does not pass validation",
-				Reason:                 fmt.Sprintf("The schema cannot be decoded: %s", err.Error()),
-				SpecLine:               1,
-				SpecCol:                0,
-				SchemaValidationErrors: []*liberrors.SchemaValidationFailure{violation},
-				HowToFix:               liberrors.HowToFixInvalidSchema,
-				Context:                string(renderedSchema), // attach the rendered schema to the error
-			})
-			return false, validationErrors
+			return false, append(validationErrors, payloadDecodeError(renderedSchema, payload, err))
 		}
 
 	}
+	jsch, compileError := compileRenderedSchema(renderedSchema, jsonSchema, payload)
+	if compileError != nil {
+		return false, append(validationErrors, compileError)
+	}
+
+	// 4. validate the object against the schema
+	if jsch != nil && decodedObject != nil {
+		validationErrors = append(validationErrors,
+			validateDecodedObject(schema, jsch, renderedSchema, decodedObject, payload, s.options, "")...)
+	}
+	if len(validationErrors) > 0 {
+		return false, validationErrors
+	}
+	return true, nil
+}
+
+// payloadDecodeError returns a ValidationError for a payload that cannot be decoded, so cannot be validated.
+func payloadDecodeError(renderedSchema, payload []byte, err error) *liberrors.ValidationError {
+	violation := &liberrors.SchemaValidationFailure{
+		Reason:          err.Error(),
+		Location:        "unavailable",
+		ReferenceSchema: string(renderedSchema),
+		ReferenceObject: string(payload),
+	}
+	return &liberrors.ValidationError{
+		ValidationType:         helpers.RequestBodyValidation,
+		ValidationSubType:      helpers.Schema,
+		Message:                "schema does not pass validation",
+		Reason:                 fmt.Sprintf("The schema cannot be decoded: %s", err.Error()),
+		SpecLine:               1,
+		SpecCol:                0,
+		SchemaValidationErrors: []*liberrors.SchemaValidationFailure{violation},
+		HowToFix:               liberrors.HowToFixInvalidSchema,
+		Context:                string(renderedSchema), // attach the rendered schema to the error
+	}
+}
+
+// compileRenderedSchema will compile a rendered JSON schema, ready to validate objects. If the schema cannot be
+// compiled, a ValidationError describing why is returned instead.
+func compileRenderedSchema(renderedSchema, jsonSchema []byte, payload []byte) (*jsonschema.Schema, *liberrors.ValidationError) {
+	jsch, err := compileJSONSchema(jsonSchema)
+	return jsch, compileSchemaError(renderedSchema, payload, err)
+}
+
+// compileJSONSchema will compile a JSON schema, ready to validate objects.
+func compileJSONSchema(jsonSchema []byte) (*jsonschema.Schema, error) {
 	compiler := jsonschema.NewCompiler()
 
-	_ = compiler.AddResource("schema.json", strings.NewReader(string(jsonSchema)))
-	jsch, err := compiler.Compile("schema.json")
+	// assert contentEncoding (e.g. base64) and contentMediaType (e.g. application/json) of string values.
+	compiler.AssertContent = true
 
-	var schemaValidationErrors []*liberrors.SchemaValidationFailure
+	_ = compiler.AddResource("schema.json", strings.NewReader(string(jsonSchema)))
+	return compiler.Compile("schema.json")
+}
 
+// compileSchemaError returns a ValidationError describing why a schema failed to compile. Only errors within the
+// schema itself are described, for any other error (or no error at all) nil is returned.
+func compileSchemaError(renderedSchema, payload []byte, err error) *liberrors.ValidationError {
 	// is the schema even valid? did it compile?
 	if err != nil {
+		// patterns Go cannot evaluate are the most common reason a valid schema fails to compile.
+		if patternError := CheckUnsupportedPatterns(renderedSchema); patternError != nil {
+			return patternError
+		}
 		var se *jsonschema.SchemaError
 		if errors.As(err, &se) {
 			var ve *jsonschema.ValidationError
 			if errors.As(se.Err, &ve) {
 
-				// no, this won't work, so we need to extract the errors and return them.
-				basicErrors := ve.BasicOutput().Errors
-				schemaValidationErrors = extractBasicErrors(basicErrors, renderedSchema, decodedObject, payload, ve, schemaValidationErrors)
 				// cannot compile schema, so it's not valid
 				violation := &liberrors.SchemaValidationFailure{
 					Reason:          err.Error(),
@@ -147,7 +489,7 @@ func (s *schemaValidator) validateSchema(schema *base.Schema, payload []byte, de
 					ReferenceSchema: string(renderedSchema),
 					ReferenceObject: string(payload),
 				}
-				validationErrors = append(validationErrors, &liberrors.ValidationError{
+				return &liberrors.ValidationError{
 					ValidationType:         helpers.RequestBodyValidation,
 					ValidationSubType:      helpers.Schema,
 					Message:                "schema does not pass validation",
@@ -157,70 +499,79 @@ func (s *schemaValidator) validateSchema(schema *base.Schema, payload []byte, de
 					SchemaValidationErrors: []*liberrors.SchemaValidationFailure{violation},
 					HowToFix:               liberrors.HowToFixInvalidSchema,
 					Context:                string(renderedSchema), // attach the rendered schema to the error
-				})
-				return false, validationErrors
+				}
 			}
 		}
 	}
+	return nil
+}
 
-	// 4. validate the object against the schema
-	if jsch != nil && decodedObject != nil {
-		scErrs := jsch.Validate(decodedObject)
-		if scErrs != nil {
+// validateDecodedObject will validate an already decoded object against a compiled schema. If a failure visitor is
+// configured, it is invoked with each failure as it is discovered. The location prefix (if any) is prepended to the
+// location of every failure, for objects that are validated within a larger payload.
+func validateDecodedObject(schema *base.Schema, jsch *jsonschema.Schema,
+	renderedSchema []byte, decodedObject interface{}, payload []byte,
+	options *config.ValidationOptions, locationPrefix string) []*liberrors.ValidationError {
 
-			// check for invalid JSON type errors.
-			var invalidJSONTypeError jsonschema.InvalidJSONTypeError
-			if errors.As(scErrs, &invalidJSONTypeError) {
-				violation := &liberrors.SchemaValidationFailure{
-					Reason:   scErrs.Error(),
-					Location: "unavailable", // we don't have a location for this error, so we'll just say it's unavailable.
-				}
-				schemaValidationErrors = append(schemaValidationErrors, violation)
-			}
+	visitor := options.SchemaFailureVisitor
 
-			var jk *jsonschema.ValidationError
-			if errors.As(scErrs, &jk) {
+	var validationErrors []*liberrors.ValidationError
+	var schemaValidationErrors []*liberrors.SchemaValidationFailure
 
-				// flatten the validationErrors
-				schFlatErrs := jk.BasicOutput().Errors
+	scErrs := jsch.Validate(decodedObject)
+	if scErrs != nil {
 
-				schemaValidationErrors = extractBasicErrors(schFlatErrs, renderedSchema, decodedObject, payload, jk, schemaValidationErrors)
+		// check for invalid JSON type errors.
+		var invalidJSONTypeError jsonschema.InvalidJSONTypeError
+		if errors.As(scErrs, &invalidJSONTypeError) {
+			violation := &liberrors.SchemaValidationFailure{
+				Reason:   scErrs.Error(),
+				Location: "unavailable", // we don't have a location for this error, so we'll just say it's unavailable.
 			}
-			line := 1
-			col := 0
-			if schema.GoLow().Type.KeyNode != nil {
-				line = schema.GoLow().Type.KeyNode.Line
-				col = schema.GoLow().Type.KeyNode.Column
+			if visitor != nil {
+				visitor(violation)
 			}
+			schemaValidationErrors = append(schemaValidationErrors, violation)
+		}
 
-			// add the error to the list
-			validationErrors = append(validationErrors, &liberrors.ValidationError{
-				ValidationType:         helpers.Schema,
-				Message:                "schema does not pass validation",
-				Reason:                 "Schema failed to validate against the contract requirements",
-				SpecLine:               line,
-				SpecCol:                col,
-				SchemaValidationErrors: schemaValidationErrors,
-				HowToFix:               liberrors.HowToFixInvalidSchema,
-				Context:                string(renderedSchema), // attach the rendered schema to the error
-			})
+		var jk *jsonschema.ValidationError
+		if errors.As(scErrs, &jk) {
+
+			// flatten the validationErrors
+			schFlatErrs := FlattenValidationErrors(jk, options.IncludeAllErrors)
+
+			schemaValidationErrors = extractBasicErrors(schFlatErrs, renderedSchema, decodedObject, payload, jk,
+				schemaValidationErrors, visitor, locationPrefix)
 		}
+		line := 1
+		col := 0
+		if schema != nil && schema.GoLow() != nil && schema.GoLow().Type.KeyNode != nil {
+			line = schema.GoLow().Type.KeyNode.Line
+			col = schema.GoLow().Type.KeyNode.Column
+		}
+
+		// add the error to the list
+		validationErrors = append(validationErrors, &liberrors.ValidationError{
+			ValidationType:         helpers.Schema,
+			Message:                "schema does not pass validation",
+			Reason:                 "Schema failed to validate against the contract requirements",
+			SpecLine:               line,
+			SpecCol:                col,
+			SchemaValidationErrors: schemaValidationErrors,
+			HowToFix:               liberrors.HowToFixInvalidSchema,
+			Context:                string(renderedSchema), // attach the rendered schema to the error
+		})
 	}
-	if len(validationErrors) > 0 {
-		return false, validationErrors
-	}
-	return true, nil
+	return validationErrors
 }
 
 func extractBasicErrors(schFlatErrs []jsonschema.BasicError,
 	renderedSchema []byte, decodedObject interface{},
 	payload []byte, jk *jsonschema.ValidationError,
-	schemaValidationErrors []*liberrors.SchemaValidationFailure) []*liberrors.SchemaValidationFailure {
+	schemaValidationErrors []*liberrors.SchemaValidationFailure,
+	visitor config.SchemaFailureVisitor, locationPrefix string) []*liberrors.SchemaValidationFailure {
 	for q := range schFlatErrs {
 		er := schFlatErrs[q]
-		if er.KeywordLocation == "" || strings.HasPrefix(er.Error, "doesn't validate with") {
-			continue // ignore this error, it's useless tbh, utter noise.
-		}
 		if er.Error != "" {
 
 			// re-encode the schema.
@@ -228,7 +579,7 @@ func extractBasicErrors(schFlatErrs []jsonschema.BasicError,
 			_ = yaml.Unmarshal(renderedSchema, &renderedNode)
 
 			// locate the violated property in the schema
-			located := LocateSchemaPropertyNodeByJSONPath(renderedNode.Content[0], er.KeywordLocation)
+			located := LocateSchemaViolationNode(renderedNode.Content[0], er)
 
 			// extract the element specified by the instance
 			val := instanceLocationRegex.FindStringSubmatch(er.InstanceLocation)
@@ -247,28 +598,29 @@ func extractBasicErrors(schFlatErrs []jsonschema.BasicError,
 			}
 
 			violation := &liberrors.SchemaValidationFailure{
-				Reason:           er.Error,
-				Location:         er.InstanceLocation,
+				Reason:           GetFailureReason(er),
+				Location:         locationPrefix + er.InstanceLocation,
+				InstanceLocation: locationPrefix + er.InstanceLocation,
 				DeepLocation:     er.KeywordLocation,
 				AbsoluteLocation: er.AbsoluteKeywordLocation,
 				ReferenceSchema:  string(renderedSchema),
 				ReferenceObject:  referenceObject,
 				OriginalError:    jk,
+				LeafError:        LocateValidationErrorCause(jk, er),
 			}
 			// if we have a location within the schema, add it to the error
 			if located != nil {
-				line := located.Line
 				// if the located node is a map or an array, then the actual human interpretable
 				// line on which the violation occurred is the line of the key, not the value.
-				if located.Kind == yaml.MappingNode || located.Kind == yaml.SequenceNode {
-					if line > 0 {
-						line--
-					}
-				}
+				line := LocateSchemaViolationLine(renderedNode.Content[0], located)
 
 				// location of the violation within the rendered schema.
 				violation.Line = line
 				violation.Column = located.Column
+				violation.SchemaNode = located
+			}
+			if visitor != nil {
+				visitor(violation)
 			}
 			schemaValidationErrors = append(schemaValidationErrors, violation)
 		}
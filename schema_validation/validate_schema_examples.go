@@ -0,0 +1,97 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateSchemaExamples will validate the 'default', 'example' and 'examples' values of a schema (and of every
+// schema it contains) against the schema they belong to. A default that fails validation breaks every payload it is
+// filled into (see ApplyDefaults), and an example that fails validation misleads anyone reading the contract, so both
+// are almost always authoring mistakes. An error is returned for every bad value, located at the value in the
+// specification.
+func ValidateSchemaExamples(schema *base.Schema, opts ...config.Option) (bool, []*liberrors.ValidationError) {
+	checker := &exampleChecker{validator: NewSchemaValidator(opts...)}
+	newSchemaWalker(checker.checkSchemaValues).walkSchema(schema, "")
+	return len(checker.errors) == 0, checker.errors
+}
+
+// ValidateDocumentExamples is the same as ValidateSchemaExamples, for every schema of a document: the schemas of the
+// components, and the schemas of the parameters, request bodies and responses of every operation. The 'example' and
+// 'examples' values of every request and response media type are also validated against the schema of the media
+// type. Examples that only have an external value are not fetched, and the examples of parameters are serialized
+// for their style, so they are validated by parameters.ParameterExamples instead.
+func ValidateDocumentExamples(document *v3.Document, opts ...config.Option) (bool, []*liberrors.ValidationError) {
+	checker := &exampleChecker{validator: NewSchemaValidator(opts...)}
+	walker := newSchemaWalker(checker.checkSchemaValues)
+	walker.mediaType = checker.checkMediaTypeValues
+	walker.walkDocument(document)
+	return len(checker.errors) == 0, checker.errors
+}
+
+// invalidValueError builds the error for a default or example value that fails validation.
+type invalidValueError func(value, location string, node *yaml.Node,
+	failures []*liberrors.SchemaValidationFailure) *liberrors.ValidationError
+
+// exampleChecker validates the default and example values of the schemas and media types visited by a
+// schemaWalker, and collects an error for every bad value.
+type exampleChecker struct {
+	validator SchemaValidator
+	errors    []*liberrors.ValidationError
+}
+
+// checkMediaTypeValues validates the example and examples of a media type against the schema of the media type.
+func (c *exampleChecker) checkMediaTypeValues(mediaType *v3.MediaType, sch *base.Schema, location string) {
+	c.checkValue(sch, mediaType.Example, location+"/example", liberrors.SchemaExampleInvalid)
+	for example := orderedmap.First(mediaType.Examples); example != nil; example = example.Next() {
+		if example.Value() != nil {
+			c.checkValue(sch, example.Value().Value,
+				location+"/examples/"+escapeJSONPointer(example.Key())+"/value", liberrors.SchemaExampleInvalid)
+		}
+	}
+}
+
+// checkSchemaValues validates the default, example and examples of a schema against the schema.
+func (c *exampleChecker) checkSchemaValues(sch *base.Schema, location string) {
+	c.checkValue(sch, sch.Default, location+"/default", liberrors.SchemaDefaultInvalid)
+	c.checkValue(sch, sch.Example, location+"/example", liberrors.SchemaExampleInvalid)
+	for i, example := range sch.Examples {
+		c.checkValue(sch, example, fmt.Sprintf("%s/examples/%d", location, i), liberrors.SchemaExampleInvalid)
+	}
+}
+
+// checkValue validates a value from the specification against a schema, and collects an error (built by invalid)
+// if it fails. A schema that cannot be rendered or compiled is reported with a sub-type, and it is reported elsewhere,
+// so only the failures of the value itself are reported here.
+func (c *exampleChecker) checkValue(sch *base.Schema, node *yaml.Node, location string, invalid invalidValueError) {
+
+	if node == nil {
+		return
+	}
+	var decoded any
+	_ = node.Decode(&decoded)
+	encoded, _ := json.Marshal(decoded)
+
+	valid, validationErrors := c.validator.ValidateSchemaBytes(sch, encoded)
+	if valid {
+		return
+	}
+	var failures []*liberrors.SchemaValidationFailure
+	for _, validationError := range validationErrors {
+		if validationError.ValidationSubType != "" {
+			return
+		}
+		failures = append(failures, validationError.SchemaValidationErrors...)
+	}
+	c.errors = append(c.errors, invalid(string(encoded), location, node, failures))
+}
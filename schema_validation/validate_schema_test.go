@@ -4,10 +4,24 @@
 package schema_validation
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/datamodel/low"
+	lowbase "github.com/pb33f/libopenapi/datamodel/low/base"
+	"github.com/pb33f/libopenapi/index"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
 )
@@ -680,3 +694,1347 @@ paths:
 //	assert.Len(t, errors, 0)
 //
 //}
+
+func TestValidateSchema_ObjectSizeViolations(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              maxProperties: 2
+              properties:
+                toppings:
+                  type: object
+                  minProperties: 2`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	body := map[string]interface{}{
+		"name":     "Big Mac",
+		"patties":  2,
+		"toppings": map[string]interface{}{"cheese": true},
+	}
+
+	bodyBytes, _ := json.Marshal(body)
+	sch := m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post.RequestBody.Content.GetOrZero("application/json").Schema
+
+	// create a schema validator
+	v := NewSchemaValidator()
+
+	// validate!
+	valid, errors := v.ValidateSchemaBytes(sch.Schema(), bodyBytes)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 2)
+
+	var reasons []string
+	for _, e := range errors[0].SchemaValidationErrors {
+		reasons = append(reasons, e.Reason)
+		if e.Location == "/toppings" {
+			// located at the 'toppings' object, not the 'minProperties' keyword.
+			assert.Equal(t, 4, e.Line)
+		}
+	}
+	assert.Contains(t, reasons,
+		"The object has 3 properties, however the schema allows a maximum of 2 properties (maxProperties)")
+	assert.Contains(t, reasons,
+		"The object at '/toppings' has 1 properties, however the schema allows a minimum of 2 properties (minProperties)")
+}
+
+func TestValidateSchema_PrefixItems(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        pair:
+          type: array
+          prefixItems:
+            - type: string
+            - type: integer
+          items: false`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Components.Schemas.GetOrZero("Order").Schema()
+
+	v := NewSchemaValidator()
+
+	valid, errors := v.ValidateSchemaBytes(sch, []byte(`{"pair": ["burger", 2]}`))
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// the second element of the tuple is the wrong type.
+	valid, errors = v.ValidateSchemaBytes(sch, []byte(`{"pair": ["burger", "two"]}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+
+	failure := errors[0].SchemaValidationErrors[0]
+	assert.Equal(t, "The array at '/pair' expects integer at index 1 (prefixItems), however the item is string",
+		failure.Reason)
+	assert.Equal(t, "/pair/1", failure.Location)
+	assert.Equal(t, "/properties/pair/prefixItems/1/type", failure.DeepLocation)
+	assert.Equal(t, 7, failure.Line) // the type of the second position.
+
+	// items beyond the tuple are not allowed.
+	valid, errors = v.ValidateSchemaBytes(sch, []byte(`{"pair": ["burger", 2, 3]}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "The array at '/pair' does not allow an item at index 2 (items: false), only the items "+
+		"defined by 'prefixItems' are allowed", errors[0].SchemaValidationErrors[0].Reason)
+	assert.Equal(t, "/pair/2", errors[0].SchemaValidationErrors[0].Location)
+}
+
+func TestValidateSchema_NDJSON(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Burger:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        patties:
+          type: integer`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Components.Schemas.GetOrZero("Burger")
+
+	payload := `{"name": "Big Mac", "patties": 2}
+{"name": "Whopper", "patties": "two"}
+
+{"patties": 1}
+{"name": "Quarter Pounder"}
+not json
+`
+
+	// create a schema validator
+	v := NewSchemaValidator()
+
+	// validate!
+	valid, errors := v.ValidateSchemaNDJSON(sch.Schema(), strings.NewReader(payload))
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 3)
+	assert.Equal(t, "record on line 2 does not pass validation", errors[0].Message)
+	assert.Equal(t, "record on line 4 does not pass validation", errors[1].Message)
+	assert.Equal(t, "record on line 6 cannot be decoded", errors[2].Message)
+	assert.Equal(t, `{"patties": 1}`, errors[1].SchemaValidationErrors[0].ReferenceObject)
+}
+
+func TestValidateSchema_NDJSON_Valid(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Components.Schemas.GetOrZero("Burger")
+
+	v := NewSchemaValidator()
+
+	valid, errors := v.ValidateSchemaNDJSON(sch.Schema(), strings.NewReader("{\"name\": \"a\"}\n{\"name\": \"b\"}"))
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = v.ValidateSchemaNDJSON(nil, strings.NewReader(""))
+	assert.False(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestValidateSchema_LeafError(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                patties:
+                  type: integer
+                  maximum: 3`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	body := map[string]interface{}{
+		"name":    12,
+		"patties": 5,
+	}
+
+	bodyBytes, _ := json.Marshal(body)
+	sch := m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post.RequestBody.Content.GetOrZero("application/json").Schema
+
+	v := NewSchemaValidator()
+	valid, errors := v.ValidateSchemaBytes(sch.Schema(), bodyBytes)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 2)
+
+	for _, e := range errors[0].SchemaValidationErrors {
+		assert.NotNil(t, e.OriginalError)
+		assert.NotNil(t, e.LeafError)
+		assert.NotSame(t, e.OriginalError, e.LeafError)
+		assert.Empty(t, e.LeafError.Causes)
+		assert.Equal(t, e.DeepLocation, e.LeafError.KeywordLocation)
+		assert.Equal(t, e.Location, e.LeafError.InstanceLocation)
+	}
+}
+
+func TestValidateSchema_SchemaNode(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                patties:
+                  description: the number of patties
+                  type: integer
+                  maximum: 3`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{"patties": 5})
+	sch := m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post.RequestBody.Content.GetOrZero("application/json").Schema
+
+	v := NewSchemaValidator()
+	valid, errors := v.ValidateSchemaBytes(sch.Schema(), bodyBytes)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+
+	failure := errors[0].SchemaValidationErrors[0]
+	assert.NotNil(t, failure.SchemaNode)
+	assert.Equal(t, "3", failure.SchemaNode.Value)
+	assert.Equal(t, failure.SchemaNode.Column, failure.Column)
+
+	// the violated sub-schema can be found from the rendered schema.
+	var rendered yaml.Node
+	_ = yaml.Unmarshal([]byte(failure.ReferenceSchema), &rendered)
+	description := LocateSchemaPropertyNodeByJSONPath(rendered.Content[0], "/properties/patties/description")
+	assert.NotNil(t, description)
+	assert.Equal(t, "the number of patties", description.Value)
+}
+
+func TestValidateSchema_ContentAssertions(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                image:
+                  type: string
+                  contentEncoding: base64
+                recipe:
+                  type: string
+                  contentMediaType: application/json`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post.RequestBody.Content.GetOrZero("application/json").Schema
+
+	v := NewSchemaValidator()
+
+	valid, errors := v.ValidateSchemaString(sch.Schema(), `{"image": "YmVlZg==", "recipe": "{\"patties\": 2}"}`)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = v.ValidateSchemaString(sch.Schema(), `{"image": "not base64!"}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "/image", errors[0].SchemaValidationErrors[0].Location)
+	assert.Contains(t, errors[0].SchemaValidationErrors[0].Reason, "base64")
+
+	valid, errors = v.ValidateSchemaString(sch.Schema(), `{"recipe": "{\"patties\": "}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "/recipe", errors[0].SchemaValidationErrors[0].Location)
+	assert.Contains(t, errors[0].SchemaValidationErrors[0].Reason, "application/json")
+}
+
+func TestValidateNamedSchema(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Burger:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        patties:
+          type: integer`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	valid, errors := ValidateNamedSchema(&m.Model, "Burger", []byte(`{"name": "Big Mac", "patties": 2}`))
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = ValidateNamedSchema(&m.Model, "Burger", []byte(`{"patties": "two"}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 2)
+
+	valid, errors = ValidateNamedSchema(&m.Model, "Pizza", []byte(`{}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "schema 'Pizza' does not exist", errors[0].Message)
+	assert.Equal(t, helpers.SchemaMissing, errors[0].ValidationSubType)
+}
+
+func TestValidateSchemaWithDefaults(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Burger:
+      type: object
+      required: [name, patties, sauce]
+      allOf:
+        - $ref: '#/components/schemas/Named'
+      properties:
+        patties:
+          type: integer
+          default: 1
+        sauce:
+          type: object
+          required: [name]
+          properties:
+            name:
+              type: string
+              default: ketchup
+            spicy:
+              type: boolean
+        toppings:
+          type: array
+          items:
+            type: object
+            required: [amount]
+            properties:
+              name:
+                type: string
+              amount:
+                type: number
+                default: 0.5
+        extras:
+          type: object
+          properties:
+            fries:
+              type: boolean
+              default: true
+    Named:
+      type: object
+      properties:
+        name:
+          type: string
+          default: Big Mac`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Components.Schemas.GetOrZero("Burger").Schema()
+
+	payload := []byte(`{"sauce": {}, "toppings": [{"name": "cheese"}, {"name": "onion", "amount": 2}]}`)
+
+	// without defaults, the required properties are missing.
+	valid, _ := NewSchemaValidator().ValidateSchemaBytes(sch, payload)
+	assert.False(t, valid)
+
+	valid, errors, enriched := ValidateSchemaWithDefaults(sch, payload)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+	assert.Equal(t, map[string]any{
+		"name":    "Big Mac",
+		"patties": float64(1),
+		"sauce":   map[string]any{"name": "ketchup"},
+		"toppings": []any{
+			map[string]any{"name": "cheese", "amount": 0.5},
+			map[string]any{"name": "onion", "amount": float64(2)},
+		},
+	}, enriched) // the missing 'extras' object is not created.
+
+	// values that are present are not replaced, and are still validated.
+	valid, errors, enriched = ValidateSchemaWithDefaults(sch, []byte(`{"name": "Whopper", "patties": "two", "sauce": {}}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Whopper", enriched.(map[string]any)["name"])
+
+	valid, errors, enriched = ValidateSchemaWithDefaults(sch, []byte(`{"name": `))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Nil(t, enriched)
+}
+
+func TestValidateRawSchema(t *testing.T) {
+	schema := `type: object
+required: [name]
+properties:
+  name:
+    type: string
+  patties:
+    type: integer
+    maximum: 4`
+
+	valid, errors := ValidateRawSchema([]byte(schema), []byte(`{"name": "Big Mac", "patties": 2}`))
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = ValidateRawSchema([]byte(schema), []byte(`{"name": "Big Mac", "patties": 5}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "schema does not pass validation", errors[0].Message)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "/patties", errors[0].SchemaValidationErrors[0].Location)
+	assert.Equal(t, 8, errors[0].SchemaValidationErrors[0].Line) // located within the raw schema.
+
+	// JSON schemas work the same way.
+	valid, errors = ValidateRawSchema([]byte(`{"type": "string", "minLength": 3}`), []byte(`"ok"`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+
+	valid, errors = ValidateRawSchema([]byte(schema), []byte(`{"name": `))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Reason, "cannot be decoded")
+
+	valid, errors = ValidateRawSchema([]byte(`type: [object`), []byte(`{}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "schema cannot be compiled", errors[0].Message)
+
+	valid, errors = ValidateRawSchema([]byte(`$ref: '#/$defs/Missing'`), []byte(`{}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "schema cannot be compiled", errors[0].Message)
+
+	valid, errors = ValidateRawSchema(nil, []byte(`{}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Reason, "the schema is empty")
+}
+
+func TestValidateSchema_AdditionalPropertiesFalse(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              additionalProperties: false
+              properties:
+                name:
+                  type: string
+                sauce:
+                  type: object
+                  additionalProperties: false
+                  properties:
+                    flavor:
+                      type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	body := map[string]interface{}{
+		"name":    "Big Mac",
+		"pickles": true,
+		"cheese":  "cheddar",
+		"sauce":   map[string]interface{}{"flavor": "secret", "spicy/hot": true},
+	}
+
+	bodyBytes, _ := json.Marshal(body)
+	sch := m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post.RequestBody.Content.GetOrZero("application/json").Schema
+
+	v := NewSchemaValidator()
+	valid, errors := v.ValidateSchemaBytes(sch.Schema(), bodyBytes)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 3)
+
+	failures := errors[0].SchemaValidationErrors
+	slices.SortFunc(failures, func(a, b *liberrors.SchemaValidationFailure) int {
+		return strings.Compare(a.InstanceLocation, b.InstanceLocation)
+	})
+
+	assert.Equal(t, "/cheese", failures[0].InstanceLocation)
+	assert.Equal(t, "The object contains the property 'cheese', which is not defined by the schema "+
+		"and additional properties are not allowed", failures[0].Reason)
+	assert.Equal(t, "/pickles", failures[1].InstanceLocation)
+	assert.Equal(t, "/sauce/spicy~1hot", failures[2].InstanceLocation)
+	assert.Equal(t, "The object at '/sauce' contains the property 'spicy/hot', which is not defined by the schema "+
+		"and additional properties are not allowed", failures[2].Reason)
+
+	for _, e := range failures {
+		assert.NotNil(t, e.LeafError)
+		assert.NotSame(t, e.OriginalError, e.LeafError)
+	}
+}
+
+func TestValidateSchema_FailureVisitor(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                patties:
+                  type: integer
+                  maximum: 3`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	body := map[string]interface{}{
+		"name":    12,
+		"patties": 5,
+	}
+
+	bodyBytes, _ := json.Marshal(body)
+	sch := m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post.RequestBody.Content.GetOrZero("application/json").Schema
+
+	var visited []*liberrors.SchemaValidationFailure
+	v := NewSchemaValidator(config.WithSchemaFailureVisitor(func(failure *liberrors.SchemaValidationFailure) {
+		visited = append(visited, failure)
+	}))
+	valid, errors := v.ValidateSchemaBytes(sch.Schema(), bodyBytes)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, visited, 2)
+	assert.Equal(t, errors[0].SchemaValidationErrors, visited)
+
+	// nothing is visited when the payload is valid.
+	visited = nil
+	valid, _ = v.ValidateSchemaString(sch.Schema(), `{"name": "Big Mac", "patties": 2}`)
+	assert.True(t, valid)
+	assert.Nil(t, visited)
+}
+
+func TestValidateSchema_ByteAndBinaryFormats(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                photo:
+                  type: string
+                  format: byte
+                receipt:
+                  type: string
+                  format: binary
+                  contentMediaType: application/json`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post.RequestBody.Content.GetOrZero("application/json").Schema
+
+	v := NewSchemaValidator()
+
+	valid, errors := v.ValidateSchemaString(sch.Schema(), `{"photo": "YmVlZg==", "receipt": "\u0000ÿ raw"}`)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = v.ValidateSchemaString(sch.Schema(), `{"photo": "not base64!"}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "/photo", errors[0].SchemaValidationErrors[0].Location)
+	assert.Contains(t, errors[0].SchemaValidationErrors[0].Reason, "base64")
+}
+
+func TestValidateSchema_Conditional(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        type:
+          type: string
+        patties:
+          type: integer
+        bun:
+          type: string
+      if:
+        properties:
+          type:
+            const: double
+        required: [type]
+      then:
+        required: [patties]
+      else:
+        required: [bun]`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Components.Schemas.GetOrZero("Burger")
+
+	v := NewSchemaValidator()
+
+	valid, errors := v.ValidateSchemaString(sch.Schema(), `{"type": "double", "patties": 2}`)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = v.ValidateSchemaString(sch.Schema(), `{"type": "single", "bun": "sesame"}`)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// the 'then' branch fails
+	valid, errors = v.ValidateSchemaString(sch.Schema(), `{"type": "double"}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "missing properties: 'patties' (the 'if' condition at '/if' matched, "+
+		"so the 'then' schema applies)", errors[0].SchemaValidationErrors[0].Reason)
+	assert.Equal(t, "/then/required", errors[0].SchemaValidationErrors[0].DeepLocation)
+
+	// the 'else' branch fails
+	valid, errors = v.ValidateSchemaString(sch.Schema(), `{"type": "single"}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "missing properties: 'bun' (the 'if' condition at '/if' did not match, "+
+		"so the 'else' schema applies)", errors[0].SchemaValidationErrors[0].Reason)
+	assert.Equal(t, "/else/required", errors[0].SchemaValidationErrors[0].DeepLocation)
+}
+
+func TestValidateSchema_ArrayItemsLocation(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        burgers:
+          type: array
+          items:
+            type: object
+            properties:
+              name:
+                type: string
+        sides:
+          type: array
+          prefixItems:
+            - type: string
+            - type: object
+              minProperties: 1
+        "0":
+          type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Components.Schemas.GetOrZero("Order")
+
+	v := NewSchemaValidator()
+
+	valid, errors := v.ValidateSchemaString(sch.Schema(), `{"burgers": [{"name": "big"}, {"name": 1}], "sides": ["fries", {}], "0": 1}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+
+	// lines are local to the rendered schema, held by the error context.
+	failures := make(map[string]*liberrors.SchemaValidationFailure)
+	for _, f := range errors[0].SchemaValidationErrors {
+		failures[f.DeepLocation] = f
+	}
+	assert.Len(t, failures, 3)
+	assert.Equal(t, 9, failures["/properties/burgers/items/properties/name/type"].Line)
+	assert.Equal(t, 14, failures["/properties/sides/prefixItems/1/minProperties"].Line)
+	assert.Equal(t, 17, failures["/properties/0/type"].Line)
+}
+
+func TestValidateSchema_IncludeAllErrors(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Burger:
+      type: object
+      additionalProperties: false
+      properties:
+        name:
+          type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Components.Schemas.GetOrZero("Burger")
+
+	valid, errors := NewSchemaValidator().ValidateSchemaString(sch.Schema(), `{"name": "Big Mac", "pickles": 1, "onions": 2}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 2)
+
+	valid, errors = NewSchemaValidator(config.WithAllErrors()).ValidateSchemaString(sch.Schema(),
+		`{"name": "Big Mac", "pickles": 1, "onions": 2}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 2)
+	assert.Equal(t, "", errors[0].SchemaValidationErrors[0].DeepLocation)
+	assert.Contains(t, errors[0].SchemaValidationErrors[0].Reason, "doesn't validate with")
+	assert.Equal(t, "/additionalProperties", errors[0].SchemaValidationErrors[1].DeepLocation)
+}
+
+func TestValidateSchema_CircularReference(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Node:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Components.Schemas.GetOrZero("Node")
+
+	v := NewSchemaValidator()
+
+	valid, errors := v.ValidateSchemaString(sch.Schema(),
+		`{"name": "root", "children": [{"name": "a", "children": [{"name": "b"}]}, {"name": "c"}]}`)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// a node deep within the tree is missing a name.
+	valid, errors = v.ValidateSchemaString(sch.Schema(),
+		`{"name": "root", "children": [{"name": "a", "children": [{"children": []}]}]}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "missing properties: 'name'", errors[0].SchemaValidationErrors[0].Reason)
+	assert.Equal(t, "/children/0/children/0", errors[0].SchemaValidationErrors[0].InstanceLocation)
+}
+
+func TestValidateExample_ExternalValue(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+            examples:
+              inline:
+                value:
+                  name: Big Mac
+              external:
+                externalValue: https://things.com/examples/burger.json
+              broken:
+                externalValue: https://things.com/examples/missing.json`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	mediaType := m.Model.Paths.PathItems.GetOrZero("/burgers").Post.RequestBody.Content.GetOrZero("application/json")
+	sch := mediaType.Schema.Schema()
+	examples := mediaType.Examples
+
+	valid, errs := ValidateExample(sch, examples.GetOrZero("inline"))
+	assert.True(t, valid)
+	assert.Len(t, errs, 0)
+
+	// fetching is disabled by default.
+	valid, errs = ValidateExample(sch, examples.GetOrZero("external"))
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, helpers.ExampleFetchDisabled, errs[0].ValidationSubType)
+	assert.Equal(t, "example external value 'https://things.com/examples/burger.json' cannot be fetched", errs[0].Message)
+	assert.Equal(t, 19, errs[0].SpecLine)
+
+	fetcher := config.ExampleFetcherFunc(func(externalValue string) ([]byte, error) {
+		if externalValue == "https://things.com/examples/burger.json" {
+			return []byte(`{"name": 42}`), nil
+		}
+		return nil, errors.New("404 not found")
+	})
+
+	valid, errs = ValidateExample(sch, examples.GetOrZero("external"), config.WithExampleFetcher(fetcher))
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Len(t, errs[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "/name", errs[0].SchemaValidationErrors[0].Location)
+
+	valid, errs = ValidateExample(sch, examples.GetOrZero("broken"), config.WithExampleFetcher(fetcher))
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, helpers.ExampleFetchFailed, errs[0].ValidationSubType)
+	assert.Contains(t, errs[0].Reason, "could not be fetched: 404 not found")
+}
+
+func TestValidateSchema_JSONArray(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Burgers:
+      type: array
+      maxItems: 100000
+      items:
+        type: object
+        required: [name]
+        properties:
+          name:
+            type: string
+          patties:
+            type: integer`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Components.Schemas.GetOrZero("Burgers")
+
+	// stream a large array, without ever building it in memory.
+	reader, writer := io.Pipe()
+	go func() {
+		_, _ = writer.Write([]byte("["))
+		for i := 0; i < 50000; i++ {
+			if i > 0 {
+				_, _ = writer.Write([]byte(","))
+			}
+			switch i {
+			case 123:
+				_, _ = writer.Write([]byte(`{"name": "Whopper", "patties": "two"}`))
+			case 40000:
+				_, _ = writer.Write([]byte(`{"patties": 1}`))
+			default:
+				_, _ = fmt.Fprintf(writer, `{"name": "Burger %d", "patties": %d}`, i, i%3)
+			}
+		}
+		_, _ = writer.Write([]byte("]"))
+		_ = writer.Close()
+	}()
+
+	v := NewSchemaValidator()
+
+	valid, errors := v.ValidateSchemaJSONArray(sch.Schema(), reader)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 2)
+	assert.Equal(t, "array item at index 123 does not pass validation", errors[0].Message)
+	assert.Equal(t, "/123/patties", errors[0].SchemaValidationErrors[0].Location)
+	assert.Equal(t, "array item at index 40000 does not pass validation", errors[1].Message)
+
+	valid, errors = v.ValidateSchemaJSONArray(sch.Schema(), strings.NewReader(`[{"name": "a"}, {"name": "b"}]`))
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = v.ValidateSchemaJSONArray(sch.Schema(), strings.NewReader(`{"name": "a"}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "payload is not a JSON array", errors[0].Message)
+
+	valid, errors = v.ValidateSchemaJSONArray(sch.Schema(), strings.NewReader(`[{"name": "a"}, {"name": nope}]`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "array item at index 1 cannot be decoded", errors[0].Message)
+
+	// failures are located within the array before they are visited.
+	var visited []string
+	visiting := NewSchemaValidator(config.WithSchemaFailureVisitor(func(failure *liberrors.SchemaValidationFailure) {
+		visited = append(visited, failure.Location, failure.InstanceLocation)
+	}))
+	valid, _ = visiting.ValidateSchemaJSONArray(sch.Schema(), strings.NewReader(`[{"name": "a"}, {"name": 7}]`))
+	assert.False(t, valid)
+	assert.Equal(t, []string{"/1/name", "/1/name"}, visited)
+}
+
+func TestValidateSchema_JSONArray_UniqueItems(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Burgers:
+      type: array
+      uniqueItems: true
+      items:
+        type: object`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	sch := m.Model.Components.Schemas.GetOrZero("Burgers")
+
+	v := NewSchemaValidator()
+
+	// items are compared by their canonical JSON, so the order of keys and the way numbers are written do not matter.
+	valid, errors := v.ValidateSchemaJSONArray(sch.Schema(),
+		strings.NewReader(`[{"name": "a", "patties": 1}, {"name": "b"}, {"patties": 1.0, "name": "a"}]`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "The array items at index 0 and 2 are equal, however the schema requires unique items (uniqueItems)",
+		errors[0].Reason)
+
+	valid, errors = v.ValidateSchemaJSONArray(sch.Schema(), strings.NewReader(`[{"name": "a"}, {"name": "b"}]`))
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestValidateSchema_UnsupportedPattern(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                pattern:
+                  type: string
+                  pattern: '^(?!pickle).*$'
+                name:
+                  type: string
+                  pattern: '^[a-z]+$'
+              patternProperties:
+                '^(x)-\1$':
+                  type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{"name": "big mac"})
+	sch := m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post.RequestBody.Content.GetOrZero("application/json").Schema
+
+	v := NewSchemaValidator()
+	valid, errors := v.ValidateSchemaBytes(sch.Schema(), bodyBytes)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "pattern uses unsupported regex feature", errors[0].Message)
+	assert.Equal(t, helpers.SchemaPatternUnsupported, errors[0].ValidationSubType)
+	assert.Len(t, errors[0].SchemaValidationErrors, 2)
+
+	failures := map[string]string{}
+	for _, failure := range errors[0].SchemaValidationErrors {
+		failures[failure.Location] = failure.Reason
+		assert.NotNil(t, failure.SchemaNode)
+		assert.Greater(t, failure.Line, 0)
+	}
+	assert.Equal(t, "pattern '^(?!pickle).*$' uses lookahead assertions, which are not supported by Go's regexp package",
+		failures["/properties/pattern/pattern"])
+	assert.Equal(t, `pattern '^(x)-\1$' uses backreferences, which are not supported by Go's regexp package`,
+		failures[`/patternProperties/^(x)-\1$`])
+}
+
+func TestLocateUnsupportedPatterns(t *testing.T) {
+	// escaped constructs are literals, and data keywords are not schemas.
+	schema := `type: string
+pattern: '^\(?=[a-z]+$'
+enum:
+  - pattern: '(?<=a)b'
+allOf:
+  - pattern: '(?<=a)b'
+  - pattern: '(?>a)'`
+
+	failures := LocateUnsupportedPatterns([]byte(schema))
+	assert.Len(t, failures, 2)
+	assert.Equal(t, "/allOf/0/pattern", failures[0].Location)
+	assert.Equal(t, "pattern '(?<=a)b' uses lookbehind assertions, which are not supported by Go's regexp package",
+		failures[0].Reason)
+	assert.Equal(t, "/allOf/1/pattern", failures[1].Location)
+	assert.Equal(t, "pattern '(?>a)' uses atomic groups, which are not supported by Go's regexp package",
+		failures[1].Reason)
+
+	assert.Nil(t, CheckUnsupportedPatterns([]byte(`pattern: '^[a-z]+$'`)))
+}
+
+func TestValidateSchema_JSONNumber(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                orderId:
+                  type: integer
+                  maximum: 9223372036854775000`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+
+	// 9223372036854775001 is rounded down to 9223372036854774784 as a float64, so it appears to be within the maximum.
+	bodyBytes := []byte(`{"orderId": 9223372036854775001}`)
+	sch := m.Model.Paths.PathItems.GetOrZero("/burgers/createBurger").Post.RequestBody.Content.GetOrZero("application/json").Schema
+
+	v := NewSchemaValidator()
+	valid, errors := v.ValidateSchemaBytes(sch.Schema(), bodyBytes)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	v = NewSchemaValidator(config.WithJSONNumber())
+	valid, errors = v.ValidateSchemaBytes(sch.Schema(), bodyBytes)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "/orderId", errors[0].SchemaValidationErrors[0].Location)
+
+	valid, errors = v.ValidateSchemaBytes(sch.Schema(), []byte(`{"orderId": 9223372036854775000}`))
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	// payloads are still decoded as strictly as json.Unmarshal.
+	valid, errors = v.ValidateSchemaBytes(sch.Schema(), []byte(`{"orderId": 1} {}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+}
+
+func TestValidateSchemas(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Base:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+    Overlay:
+      type: object
+      properties:
+        patties:
+          type: integer
+          maximum: 3`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	schemas := []*base.Schema{
+		m.Model.Components.Schemas.GetOrZero("Base").Schema(),
+		m.Model.Components.Schemas.GetOrZero("Overlay").Schema(),
+	}
+
+	v := NewSchemaValidator()
+	valid, errors := v.ValidateSchemas(schemas, []byte(`{"name": "big mac", "patties": 2}`))
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = v.ValidateSchemas(schemas, []byte(`{"patties": 5}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 2)
+	assert.Equal(t, "schema at index 0: schema does not pass validation", errors[0].Message)
+	assert.Equal(t, "schema at index 1: schema does not pass validation", errors[1].Message)
+	assert.Equal(t, "/patties", errors[1].SchemaValidationErrors[0].Location)
+
+	valid, errors = v.ValidateSchemas(schemas, []byte(`{"name": 1, "patties": 2}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Reason, "schema at index 0")
+
+	// invalid JSON is only reported once.
+	valid, errors = v.ValidateSchemas(schemas, []byte(`{"name": `))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+
+	// the message of each error is kept, after the index of the schema.
+	patternDoc, _ := libopenapi.NewDocument([]byte(`openapi: 3.1.0
+components:
+  schemas:
+    Pickles:
+      type: string
+      pattern: '^(?!pickle).*$'`))
+	patternModel, _ := patternDoc.BuildV3Model()
+	valid, errors = v.ValidateSchemas(append(schemas,
+		patternModel.Model.Components.Schemas.GetOrZero("Pickles").Schema()), []byte(`{"name": "big mac"}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "schema at index 2: pattern uses unsupported regex feature", errors[0].Message)
+}
+
+func TestValidateSchema_UnresolvedReference(t *testing.T) {
+	spec := `type: object
+properties:
+  name:
+    type: string`
+
+	var root yaml.Node
+	_ = yaml.Unmarshal([]byte(spec), &root)
+	idx := index.NewSpecIndexWithConfig(&root, index.CreateClosedAPIIndexConfig())
+	lowSchema := new(lowbase.Schema)
+	_ = lowSchema.Build(context.Background(), root.Content[0], idx)
+	schema := base.NewSchema(lowSchema)
+
+	// the reference cannot be found, so the property would be left out of the rendered schema.
+	var friesNode yaml.Node
+	_ = yaml.Unmarshal([]byte(`$ref: '#/components/schemas/Fries'`), &friesNode)
+	lowProxy := new(lowbase.SchemaProxy)
+	_ = lowProxy.Build(context.Background(), nil, friesNode.Content[0], idx)
+	schema.Properties.Set("fries", base.NewSchemaProxy(&low.NodeReference[*lowbase.SchemaProxy]{
+		Value: lowProxy, ValueNode: friesNode.Content[0]}))
+
+	valid, errors := NewSchemaValidator().ValidateSchemaString(schema, `{"name":"chips","fries":12}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, helpers.SchemaRenderFailed, errors[0].ValidationSubType)
+	assert.Equal(t, "schema cannot be rendered, it likely contains an unresolved reference", errors[0].Message)
+	assert.Contains(t, errors[0].Reason, "reference cannot be found: '#/components/schemas/Fries'")
+	assert.Equal(t, 1, errors[0].SpecLine)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "/properties/fries", errors[0].SchemaValidationErrors[0].Location)
+
+	// a reference that was created by hand is never resolved.
+	schema.Properties.Set("fries", base.CreateSchemaProxyRef("#/components/schemas/Fries"))
+	valid, errors = NewSchemaValidator().ValidateSchemaString(schema, `{"name":"chips"}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Reason, "the reference '#/components/schemas/Fries' has not been resolved")
+}
+
+func TestValidateRequiredPresence(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Named:
+      type: object
+      required: [name]
+    Burger:
+      allOf:
+        - $ref: '#/components/schemas/Named'
+      type: object
+      required: [patties, name, vegetarian]
+      properties:
+        name:
+          type: string
+        patties:
+          type: integer
+        vegetarian:
+          type: boolean`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	schema := m.Model.Components.Schemas.GetOrZero("Burger").Schema()
+
+	valid, missing := ValidateRequiredPresence(schema, []byte(`{"name":"Big Mac","patties":2,"vegetarian":false}`))
+	assert.True(t, valid)
+	assert.Empty(t, missing)
+
+	// presence is all that is checked, the value of patties is not validated.
+	valid, missing = ValidateRequiredPresence(schema, []byte(`{"patties":"two"}`))
+	assert.False(t, valid)
+	assert.Equal(t, []string{"name", "vegetarian"}, missing)
+
+	valid, missing = ValidateRequiredPresence(schema, []byte(`[1, 2]`))
+	assert.False(t, valid)
+	assert.Equal(t, []string{"patties", "name", "vegetarian"}, missing)
+
+	valid, missing = ValidateRequiredPresence(m.Model.Components.Schemas.GetOrZero("Named").Schema(), []byte(`{"name":"x"}`))
+	assert.True(t, valid)
+	assert.Empty(t, missing)
+}
+
+func TestValidateSchema_StringLengthCodePoints(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        name:
+          type: string
+          minLength: 4
+          maxLength: 4`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	schema := m.Model.Components.Schemas.GetOrZero("Burger").Schema()
+	sv := NewSchemaValidator()
+
+	// each name is four code points, however many bytes they are encoded as.
+	for _, name := range []string{"cafe", "café", "日本語字", "🍔🍔🍔🍔"} {
+		valid, errors := sv.ValidateSchemaString(schema, `{"name":"`+name+`"}`)
+		assert.True(t, valid, name)
+		assert.Empty(t, errors, name)
+	}
+
+	valid, errors := sv.ValidateSchemaString(schema, `{"name":"cafés"}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+
+	valid, errors = sv.ValidateSchemaString(schema, `{"name":"🍔🍔🍔"}`)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+}
+
+func TestValidateSchemaBytesDecoded(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Order:
+      type: object
+      required: [orderId]
+      properties:
+        orderId:
+          type: integer
+        items:
+          type: array
+          items:
+            type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	schema := m.Model.Components.Schemas.GetOrZero("Order").Schema()
+
+	v := NewSchemaValidator()
+	valid, errors, decoded := v.ValidateSchemaBytesDecoded(schema, []byte(`{"orderId": 12, "items": ["fries"]}`))
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+	assert.Equal(t, map[string]interface{}{"orderId": float64(12), "items": []interface{}{"fries"}}, decoded)
+
+	// the object is returned even if it is not valid.
+	valid, errors, decoded = v.ValidateSchemaBytesDecoded(schema, []byte(`{"items": [1]}`))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, map[string]interface{}{"items": []interface{}{float64(1)}}, decoded)
+
+	valid, errors, decoded = v.ValidateSchemaBytesDecoded(schema, []byte(`{"orderId": `))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Nil(t, decoded)
+
+	// 9007199254740993 cannot be represented exactly by a float64, but is kept as a json.Number.
+	v = NewSchemaValidator(config.WithJSONNumber())
+	valid, errors, decoded = v.ValidateSchemaBytesDecoded(schema, []byte(`{"orderId": 9007199254740993}`))
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+	assert.Equal(t, map[string]interface{}{"orderId": json.Number("9007199254740993")}, decoded)
+}
+
+func TestValidateSchema_Concurrent(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Burger:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          minLength: 3
+        patties:
+          type: integer
+          maximum: 3
+        toppings:
+          type: array
+          items:
+            type: string
+            enum: [cheese, pickles]`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	schema := m.Model.Components.Schemas.GetOrZero("Burger").Schema()
+
+	// validators are shared, and created per goroutine, and all of them validate the same schema.
+	shared := NewSchemaValidator()
+	payload := []byte(`{"name":"ab","patties":5,"toppings":["cheese","onions"]}`)
+	// the order of failures is not fixed, so they are compared by their reason and location.
+	failures := func(validationErrors []*liberrors.ValidationError) []string {
+		var located []string
+		for _, validationError := range validationErrors {
+			for _, failure := range validationError.SchemaValidationErrors {
+				located = append(located, fmt.Sprintf("%s (%d:%d)", failure.Reason, failure.Line, failure.Column))
+			}
+		}
+		return located
+	}
+	_, expected := shared.ValidateSchemaBytes(schema, payload)
+	assert.Len(t, expected, 1)
+	assert.Len(t, failures(expected), 3)
+
+	var wg sync.WaitGroup
+	results := make([][]*liberrors.ValidationError, 50)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			validator := shared
+			if i%2 == 1 {
+				validator = NewSchemaValidator()
+			}
+			_, results[i] = validator.ValidateSchemaBytes(schema, payload)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, validationErrors := range results {
+		assert.Len(t, validationErrors, 1)
+		assert.ElementsMatch(t, failures(expected), failures(validationErrors))
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package schema_validation
+
+import (
+	"strconv"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// schemaWalker invokes a callback with every schema of a schema (or of a document), and its JSON pointer location.
+// Referenced schemas are only visited once, which also stops circular references from being followed forever.
+type schemaWalker struct {
+	visited map[string]bool
+
+	// schema is invoked with every schema that is visited.
+	schema func(sch *base.Schema, location string)
+
+	// mediaType is invoked with every request and response media type of a document that has a schema, after its
+	// schema is visited. It is optional.
+	mediaType func(mediaType *v3.MediaType, sch *base.Schema, location string)
+}
+
+func newSchemaWalker(schema func(sch *base.Schema, location string)) *schemaWalker {
+	return &schemaWalker{visited: make(map[string]bool), schema: schema}
+}
+
+// walkDocument visits the schemas of the components of a document, and the schemas of the parameters, request bodies
+// and responses of every operation.
+func (w *schemaWalker) walkDocument(document *v3.Document) {
+	if document.Components != nil {
+		for pair := orderedmap.First(document.Components.Schemas); pair != nil; pair = pair.Next() {
+			// a component is visited here, so the references to it from operations are not visited again.
+			w.visited["#/components/schemas/"+escapeJSONPointer(pair.Key())] = true
+			w.walkProxy(pair.Value(), "/components/schemas/"+escapeJSONPointer(pair.Key()))
+		}
+	}
+	if document.Paths != nil {
+		for path := orderedmap.First(document.Paths.PathItems); path != nil; path = path.Next() {
+			location := "/paths/" + escapeJSONPointer(path.Key())
+			w.walkParameters(path.Value().Parameters, location)
+			for op := orderedmap.First(path.Value().GetOperations()); op != nil; op = op.Next() {
+				w.walkOperation(op.Value(), location+"/"+op.Key())
+			}
+		}
+	}
+}
+
+func (w *schemaWalker) walkOperation(op *v3.Operation, location string) {
+	w.walkParameters(op.Parameters, location)
+	if op.RequestBody != nil {
+		w.walkContent(op.RequestBody.Content, location+"/requestBody/content")
+	}
+	if op.Responses != nil {
+		for code := orderedmap.First(op.Responses.Codes); code != nil; code = code.Next() {
+			w.walkContent(code.Value().Content, location+"/responses/"+code.Key()+"/content")
+		}
+		if op.Responses.Default != nil {
+			w.walkContent(op.Responses.Default.Content, location+"/responses/default/content")
+		}
+	}
+}
+
+func (w *schemaWalker) walkParameters(params []*v3.Parameter, location string) {
+	for i, p := range params {
+		if p != nil {
+			w.walkProxy(p.Schema, location+"/parameters/"+strconv.Itoa(i)+"/schema")
+		}
+	}
+}
+
+func (w *schemaWalker) walkContent(content *orderedmap.Map[string, *v3.MediaType], location string) {
+	for pair := orderedmap.First(content); pair != nil; pair = pair.Next() {
+		mediaLocation := location + "/" + escapeJSONPointer(pair.Key())
+		w.walkProxy(pair.Value().Schema, mediaLocation+"/schema")
+		if w.mediaType == nil {
+			continue
+		}
+		if sch := proxySchema(pair.Value().Schema); sch != nil {
+			w.mediaType(pair.Value(), sch, mediaLocation)
+		}
+	}
+}
+
+func (w *schemaWalker) walkProxy(proxy *base.SchemaProxy, location string) {
+	if proxy != nil && w.visit(proxy) {
+		w.walkSchema(proxySchema(proxy), location)
+	}
+}
+
+// walkSchema visits a schema, and every schema within it.
+func (w *schemaWalker) walkSchema(sch *base.Schema, location string) {
+	if sch == nil {
+		return
+	}
+	w.schema(sch, location)
+	walkSubSchemas(sch, location, func(proxy *base.SchemaProxy, location string) bool {
+		sub := proxySchema(proxy)
+		if sub == nil || !w.visit(proxy) {
+			return false
+		}
+		w.schema(sub, location)
+		return true
+	})
+}
+
+// visit returns false if a proxy is a reference to a schema that has already been visited.
+func (w *schemaWalker) visit(proxy *base.SchemaProxy) bool {
+	if !proxy.IsReference() {
+		return true
+	}
+	if w.visited[proxy.GetReference()] {
+		return false
+	}
+	w.visited[proxy.GetReference()] = true
+	return true
+}
@@ -6,15 +6,19 @@ package validator
 import (
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/parameters"
 	"github.com/pb33f/libopenapi-validator/paths"
 	"github.com/pb33f/libopenapi-validator/requests"
 	"github.com/pb33f/libopenapi-validator/responses"
 	"github.com/pb33f/libopenapi-validator/schema_validation"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
 )
 
 // Validator provides a coarse grained interface for validating an OpenAPI 3+ documents.
@@ -32,6 +36,12 @@ type Validator interface {
 	// The path, query, cookie and header parameters and request body are validated.
 	ValidateHttpRequestSync(request *http.Request) (bool, []*errors.ValidationError)
 
+	// ValidateHttpRequestForOperation will validate an *http.Request object against the operation of an OpenAPI 3+
+	// document with an operationId (see paths.FindOperationById), for tests that reference operations rather than
+	// paths. The request must use the method of the operation, and its path must match the path of the operation.
+	// The path, query, cookie and header parameters and request body are validated.
+	ValidateHttpRequestForOperation(request *http.Request, operationId string) (bool, []*errors.ValidationError)
+
 	// ValidateHttpResponse will an *http.Response object against an OpenAPI 3+ document.
 	// The response body is validated. The request is only used to extract the correct reponse from the spec.
 	ValidateHttpResponse(request *http.Request, response *http.Response) (bool, []*errors.ValidationError)
@@ -51,61 +61,153 @@ type Validator interface {
 
 	// GetResponseBodyValidator will return a parameters.ResponseBodyValidator instance used to validate response bodies
 	GetResponseBodyValidator() responses.ResponseBodyValidator
+
+	// Reset will clear every cached schema, precompiled path and operation built from the nodes of the document (such
+	// as CONNECT operations), and rebuild them from the current document model. Use it if the model has been modified
+	// in place. It can be called while requests are being validated: the caches are rebuilt first, and replace the
+	// old ones once the requests being validated are done, so every request is validated using a single set of them.
+	Reset()
+
+	// Reload will replace the document being validated against, for example when a development server reloads the
+	// document after it is edited. The caches are cleared and rebuilt from the new document, with the same options.
+	// If the model of the new document cannot be built, the errors are returned and the validator is not changed. Like
+	// Reset, it can be called while requests are being validated, each request is validated against one document.
+	Reload(document libopenapi.Document) []error
 }
 
-// NewValidator will create a new Validator from an OpenAPI 3+ document
-func NewValidator(document libopenapi.Document) (Validator, []error) {
+// NewValidator will create a new Validator from an OpenAPI 3+ document. Options can be supplied to change
+// how validation is performed, see the config package for what is available.
+func NewValidator(document libopenapi.Document, opts ...config.Option) (Validator, []error) {
 	m, errs := document.BuildV3Model()
 	if errs != nil {
 		return nil, errs
 	}
-	v := NewValidatorFromV3Model(&m.Model)
+	v := NewValidatorFromV3Model(&m.Model, opts...)
 	v.(*validator).document = document
 	return v, nil
 }
 
-// NewValidatorFromV3Model will create a new Validator from an OpenAPI Model
-func NewValidatorFromV3Model(m *v3.Document) Validator {
+// NewValidatorFromV3Model will create a new Validator from an OpenAPI Model. The paths of the model are precompiled
+// into a paths.PathMatcher once, and every request is matched using it, with the matching options supplied (such as
+// config.WithTrailingSlash, config.WithCaseInsensitive and config.WithServerStripping).
+func NewValidatorFromV3Model(m *v3.Document, opts ...config.Option) Validator {
+	v := &validator{options: config.NewValidationOptions(opts...)}
+	v.build(m, nil)
+	return v
+}
+
+// build creates the path matcher, and the parameter, request body and response body validators for a model. They
+// hold the caches of the validator (the compiled schemas, and the operations of a helpers.OperationCache), so
+// building them again clears the caches. They are built before the state lock is taken, and then replace the old
+// ones all at once, so a request that is being validated only ever sees one document.
+func (v *validator) build(m *v3.Document, document libopenapi.Document) {
+	// every part shares one operation cache, so each operation that is not part of the model is only built once.
+	options := config.NewValidationOptions(config.WithExistingOpts(v.options),
+		config.WithOperationCache(helpers.NewOperationCache(m, v.options.MethodExtension)))
+
 	// create a new parameter validator
-	paramValidator := parameters.NewParameterValidator(m)
+	paramValidator := parameters.NewParameterValidator(m, config.WithExistingOpts(options))
 
 	// create a new request body validator
-	reqBodyValidator := requests.NewRequestBodyValidator(m)
+	requestValidator := requests.NewRequestBodyValidator(m, config.WithExistingOpts(options))
 
 	// create a response body validator
-	respBodyValidator := responses.NewResponseBodyValidator(m)
+	responseValidator := responses.NewResponseBodyValidator(m, config.WithExistingOpts(options))
+
+	pathMatcher := paths.NewPathMatcher(m, config.WithExistingOpts(options))
+
+	v.stateLock.Lock()
+	defer v.stateLock.Unlock()
+	v.v3Model = m
+	v.document = document
+	v.paramValidator = paramValidator
+	v.requestValidator = requestValidator
+	v.responseValidator = responseValidator
+	v.pathMatcher = pathMatcher
+	v.foundPath = nil
+	v.foundPathValue = ""
+	v.errors = nil
+}
+
+func (v *validator) Reset() {
+	v.stateLock.RLock()
+	m, document := v.v3Model, v.document
+	v.stateLock.RUnlock()
+	v.build(m, document)
+}
+
+func (v *validator) Reload(document libopenapi.Document) []error {
+	m, errs := document.BuildV3Model()
+	if errs != nil {
+		return errs
+	}
+	v.build(&m.Model, document)
+	return nil
+}
 
-	return &validator{
-		v3Model:           m,
-		requestValidator:  reqBodyValidator,
-		responseValidator: respBodyValidator,
-		paramValidator:    paramValidator,
+// findPath will find the path item that matches the request, using the path matcher that was precompiled for the
+// document when the validator was created. Any warnings about the matched operation are passed to the warning handler,
+// and the time taken is recorded in the stats (if they are being collected).
+func (v *validator) findPath(request *http.Request, stats *config.ValidationStats) (*v3.PathItem,
+	[]*errors.ValidationError, string) {
+	start := time.Now()
+	result, candidates := v.pathMatcher.MatchCounted(request)
+	if stats != nil {
+		stats.PathMatchDuration = time.Since(start)
+		stats.PathCandidates = candidates
+		stats.MatchedPath = result.FoundPath
+	}
+	if v.options.WarningHandler != nil {
+		for _, warning := range result.Warnings {
+			v.options.WarningHandler(warning)
+		}
 	}
+	return result.PathItem, result.Errors, result.FoundPath
 }
 
 func (v *validator) GetParameterValidator() parameters.ParameterValidator {
+	v.stateLock.RLock()
+	defer v.stateLock.RUnlock()
 	return v.paramValidator
 }
 func (v *validator) GetRequestBodyValidator() requests.RequestBodyValidator {
+	v.stateLock.RLock()
+	defer v.stateLock.RUnlock()
 	return v.requestValidator
 }
 func (v *validator) GetResponseBodyValidator() responses.ResponseBodyValidator {
+	v.stateLock.RLock()
+	defer v.stateLock.RUnlock()
 	return v.responseValidator
 }
 
 func (v *validator) ValidateDocument() (bool, []*errors.ValidationError) {
+	v.stateLock.RLock()
+	defer v.stateLock.RUnlock()
 	return schema_validation.ValidateOpenAPIDocument(v.document)
 }
 
 func (v *validator) ValidateHttpResponse(
 	request *http.Request,
 	response *http.Response) (bool, []*errors.ValidationError) {
+	v.stateLock.RLock()
+	defer v.stateLock.RUnlock()
+	start, stats := time.Now(), v.startStats(request)
+	valid, validationErrors := v.validateHttpResponse(request, response, stats)
+	v.reportStats(stats, start, valid, validationErrors)
+	return valid, validationErrors
+}
+
+func (v *validator) validateHttpResponse(
+	request *http.Request,
+	response *http.Response,
+	stats *config.ValidationStats) (bool, []*errors.ValidationError) {
 
 	var pathItem *v3.PathItem
 	var pathValue string
 	var errs []*errors.ValidationError
 
-	pathItem, errs, pathValue = paths.FindPath(request, v.v3Model)
+	pathItem, errs, pathValue = v.findPath(request, stats)
 	if pathItem == nil || errs != nil {
 		v.errors = errs
 		return false, errs
@@ -130,12 +232,24 @@ func (v *validator) ValidateHttpResponse(
 func (v *validator) ValidateHttpRequestResponse(
 	request *http.Request,
 	response *http.Response) (bool, []*errors.ValidationError) {
+	v.stateLock.RLock()
+	defer v.stateLock.RUnlock()
+	start, stats := time.Now(), v.startStats(request)
+	valid, validationErrors := v.validateHttpRequestResponse(request, response, stats)
+	v.reportStats(stats, start, valid, validationErrors)
+	return valid, validationErrors
+}
+
+func (v *validator) validateHttpRequestResponse(
+	request *http.Request,
+	response *http.Response,
+	stats *config.ValidationStats) (bool, []*errors.ValidationError) {
 
 	var pathItem *v3.PathItem
 	var pathValue string
 	var errs []*errors.ValidationError
 
-	pathItem, errs, pathValue = paths.FindPath(request, v.v3Model)
+	pathItem, errs, pathValue = v.findPath(request, stats)
 	if pathItem == nil || errs != nil {
 		v.errors = errs
 		return false, errs
@@ -147,7 +261,7 @@ func (v *validator) ValidateHttpRequestResponse(
 	responseBodyValidator.SetPathItem(pathItem, pathValue)
 
 	// validate request and response
-	_, requestErrors := v.ValidateHttpRequest(request)
+	_, requestErrors := v.validateHttpRequest(request, stats)
 	_, responseErrors := responseBodyValidator.ValidateResponseBody(request, response)
 
 	if len(requestErrors) > 0 || len(responseErrors) > 0 {
@@ -159,13 +273,23 @@ func (v *validator) ValidateHttpRequestResponse(
 }
 
 func (v *validator) ValidateHttpRequest(request *http.Request) (bool, []*errors.ValidationError) {
+	v.stateLock.RLock()
+	defer v.stateLock.RUnlock()
+	start, stats := time.Now(), v.startStats(request)
+	valid, validationErrors := v.validateHttpRequest(request, stats)
+	v.reportStats(stats, start, valid, validationErrors)
+	return valid, validationErrors
+}
+
+func (v *validator) validateHttpRequest(request *http.Request,
+	stats *config.ValidationStats) (bool, []*errors.ValidationError) {
 
 	// find path
 	var pathItem *v3.PathItem
 	var pathValue string
 	var errs []*errors.ValidationError
 	if v.foundPath == nil {
-		pathItem, errs, pathValue = paths.FindPath(request, v.v3Model)
+		pathItem, errs, pathValue = v.findPath(request, stats)
 		if pathItem == nil || errs != nil {
 			v.errors = errs
 			return false, errs
@@ -281,13 +405,86 @@ func (v *validator) ValidateHttpRequest(request *http.Request) (bool, []*errors.
 	return true, nil
 }
 
+func (v *validator) ValidateHttpRequestForOperation(request *http.Request,
+	operationId string) (bool, []*errors.ValidationError) {
+	v.stateLock.RLock()
+	defer v.stateLock.RUnlock()
+	start, stats := time.Now(), v.startStats(request)
+	valid, validationErrors := v.validateHttpRequestForOperation(request, operationId, stats)
+	v.reportStats(stats, start, valid, validationErrors)
+	return valid, validationErrors
+}
+
+// validateHttpRequestForOperation finds the operation with an operationId, checks the request is for it, and then
+// validates the request against it, in the same way as validateHttpRequest does for the path it finds.
+func (v *validator) validateHttpRequestForOperation(request *http.Request, operationId string,
+	stats *config.ValidationStats) (bool, []*errors.ValidationError) {
+
+	pathItem, operation, path := paths.FindOperationById(v.v3Model, operationId)
+	var errs []*errors.ValidationError
+	switch {
+	case operation == nil:
+		errs = []*errors.ValidationError{errors.OperationIdNotFound(request, operationId)}
+	case helpers.ExtractOperation(request, pathItem) != operation:
+		errs = []*errors.ValidationError{errors.OperationIdMethodMismatch(operation, request,
+			operationMethod(pathItem, operation), path)}
+	}
+	if errs != nil {
+		errors.SetValidationDirection(errs, helpers.RequestDirection)
+		return false, errs
+	}
+
+	// the request path must match the path of the operation, so the values of its path parameters can be found.
+	start := time.Now()
+	result := paths.MatchPathInOrder(request, v.v3Model, []string{path}, config.WithExistingOpts(v.options))
+	if stats != nil {
+		stats.PathMatchDuration = time.Since(start)
+		stats.PathCandidates = 1
+		stats.MatchedPath = result.FoundPath
+	}
+	if result.PathItem == nil || result.Errors != nil {
+		errs = []*errors.ValidationError{errors.OperationIdPathMismatch(operation, request, path)}
+		errors.SetValidationDirection(errs, helpers.RequestDirection)
+		return false, errs
+	}
+	if v.options.WarningHandler != nil {
+		for _, warning := range result.Warnings {
+			v.options.WarningHandler(warning)
+		}
+	}
+	v.foundPath = result.PathItem
+	v.foundPathValue = result.FoundPath
+	return v.validateHttpRequest(request, stats)
+}
+
+// operationMethod returns the method of an operation of a path item, or an empty string if the path item does not
+// define the operation.
+func operationMethod(pathItem *v3.PathItem, operation *v3.Operation) string {
+	for pair := orderedmap.First(pathItem.GetOperations()); pair != nil; pair = pair.Next() {
+		if pair.Value() == operation {
+			return pair.Key()
+		}
+	}
+	return ""
+}
+
 func (v *validator) ValidateHttpRequestSync(request *http.Request) (bool, []*errors.ValidationError) {
+	v.stateLock.RLock()
+	defer v.stateLock.RUnlock()
+	start, stats := time.Now(), v.startStats(request)
+	valid, validationErrors := v.validateHttpRequestSync(request, stats)
+	v.reportStats(stats, start, valid, validationErrors)
+	return valid, validationErrors
+}
+
+func (v *validator) validateHttpRequestSync(request *http.Request,
+	stats *config.ValidationStats) (bool, []*errors.ValidationError) {
 	// find path
 	var pathItem *v3.PathItem
 	var pathValue string
 	var errs []*errors.ValidationError
 	if v.foundPath == nil {
-		pathItem, errs, pathValue = paths.FindPath(request, v.v3Model)
+		pathItem, errs, pathValue = v.findPath(request, stats)
 		if pathItem == nil || errs != nil {
 			v.errors = errs
 			return false, errs
@@ -337,8 +534,32 @@ func (v *validator) ValidateHttpRequestSync(request *http.Request) (bool, []*err
 	return true, nil
 }
 
+// startStats starts collecting the stats of a validation, if there is a metrics handler to report them to. If there
+// is not, nil is returned and no stats are collected.
+func (v *validator) startStats(request *http.Request) *config.ValidationStats {
+	if v.options.MetricsHandler == nil {
+		return nil
+	}
+	return &config.ValidationStats{Method: request.Method, Path: request.URL.Path}
+}
+
+// reportStats completes the stats of a validation that started at start, and reports them to the metrics handler.
+func (v *validator) reportStats(stats *config.ValidationStats, start time.Time, valid bool,
+	validationErrors []*errors.ValidationError) {
+	if stats == nil {
+		return
+	}
+	stats.TotalDuration = time.Since(start)
+	stats.ValidationDuration = stats.TotalDuration - stats.PathMatchDuration
+	stats.Valid = valid
+	stats.Errors = len(validationErrors)
+	v.options.MetricsHandler(*stats)
+}
+
 type validator struct {
 	v3Model           *v3.Document
+	options           *config.ValidationOptions
+	pathMatcher       *paths.PathMatcher
 	document          libopenapi.Document
 	foundPath         *v3.PathItem
 	foundPathValue    string
@@ -346,6 +567,10 @@ type validator struct {
 	requestValidator  requests.RequestBodyValidator
 	responseValidator responses.ResponseBodyValidator
 	errors            []*errors.ValidationError
+
+	// stateLock is held for reading while a request is validated, and for writing while Reset or Reload replace
+	// the model and everything built from it.
+	stateLock sync.RWMutex
 }
 
 var validationLock sync.Mutex
@@ -6,12 +6,17 @@ package validator
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/stretchr/testify/assert"
@@ -1480,3 +1485,691 @@ paths:
 	assert.True(t, valid)
 	assert.Len(t, errors, 0)
 }
+
+func TestNewValidator_ValidateHttpRequest_DuplicateSlashNormalization(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: integer`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	v, _ := NewValidator(doc, config.WithDuplicateSlashNormalization())
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com//burgers//42", nil)
+	valid, errors := v.ValidateHttpRequestSync(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers//fortytwo", nil)
+	valid, errors = v.ValidateHttpRequestSync(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'burgerId' is not a valid number", errors[0].Message)
+}
+
+func TestNewValidator_ValidateHttpRequest_Connect(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /tunnels/{port}:
+    parameters:
+      - $ref: '#/components/parameters/Port'
+    connect:
+      operationId: openTunnel
+      parameters:
+        - name: X-Tunnel-Token
+          in: header
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: tunnel opened
+components:
+  parameters:
+    Port:
+      name: port
+      in: path
+      required: true
+      schema:
+        type: integer
+        minimum: 1
+        maximum: 65535`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	v, _ := NewValidator(doc)
+
+	request, _ := http.NewRequest(http.MethodConnect, "https://things.com/tunnels/443", nil)
+	request.Header.Set("X-Tunnel-Token", "secret")
+
+	valid, errors := v.ValidateHttpRequest(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodConnect, "https://things.com/tunnels/70000", nil)
+
+	valid, errors = v.ValidateHttpRequest(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 2)
+	assert.Equal(t, "Path parameter 'port' failed to validate", errors[0].Message)
+	assert.Equal(t, "Header parameter 'X-Tunnel-Token' is missing", errors[1].Message)
+
+	// only CONNECT requests are matched by the connect operation.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/tunnels/443", nil)
+
+	valid, errors = v.ValidateHttpRequest(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "GET Path '/tunnels/443' not found", errors[0].Message)
+}
+
+func TestNewValidator_ValidateHttpRequest_ProblemJSON(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                patties:
+                  type: integer
+                  maximum: 3`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	v, _ := NewValidator(doc)
+
+	body, _ := json.Marshal(map[string]interface{}{"name": "big mac", "patties": 5})
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", bytes.NewBuffer(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, errors := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+
+	payload, err := liberrors.ToProblemJSON(errors)
+	require.NoError(t, err)
+
+	var problem map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload, &problem))
+	assert.Equal(t, "about:blank", problem["type"])
+	assert.Equal(t, "Bad Request", problem["title"])
+	assert.Equal(t, float64(http.StatusBadRequest), problem["status"])
+	assert.Equal(t, errors[0].Message, problem["detail"])
+	assert.Equal(t, "/burgers/createBurger", problem["instance"])
+
+	problemErrors := problem["errors"].([]interface{})
+	assert.Len(t, problemErrors, 1)
+	problemError := problemErrors[0].(map[string]interface{})
+	assert.Equal(t, "requestBody", problemError["validationType"])
+	schemaErrors := problemError["validationErrors"].([]interface{})
+	assert.Len(t, schemaErrors, 1)
+	assert.Equal(t, "/patties", schemaErrors[0].(map[string]interface{})["instanceLocation"])
+
+	// a path that cannot be found is not found.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/burgers/deleteBurger", nil)
+	_, errors = v.ValidateHttpRequest(request)
+
+	problemDetails := liberrors.NewProblemDetails(errors)
+	assert.Equal(t, http.StatusNotFound, problemDetails.Status)
+	assert.Equal(t, "Not Found", problemDetails.Title)
+
+	// no errors is still a valid payload.
+	payload, err = liberrors.ToProblemJSON(nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"about:blank","title":"Bad Request","status":400,"errors":[]}`, string(payload))
+}
+
+func TestNewValidator_PathMatchingOptions(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /api/burgers/{burgerId}/:
+    get:
+      operationId: getBurger
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: integer`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	v, _ := NewValidator(doc, config.WithServerStripping(false), config.WithTrailingSlash(),
+		config.WithCaseInsensitive())
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/API/Burgers/12", nil)
+	valid, errors := v.ValidateHttpRequest(request)
+
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/burgers/big/", nil)
+	valid, errors = v.ValidateHttpRequest(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'burgerId' is not a valid number", errors[0].Message)
+
+	// each option changes one behavior, so without them, the request path is not found.
+	v, _ = NewValidator(doc)
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/API/Burgers/12", nil)
+	valid, errors = v.ValidateHttpRequest(request)
+
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.True(t, errors[0].IsPathMissingError())
+}
+
+func TestNewValidator_ValidateHttpRequest_DeprecatedWarning(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      deprecated: true
+      responses:
+        '200':
+          description: ok
+  /burgers/{id}:
+    get:
+      responses:
+        '200':
+          description: ok`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	var warnings []*liberrors.ValidationWarning
+	v, _ := NewValidator(doc, config.WithWarningHandler(func(warning *liberrors.ValidationWarning) {
+		warnings = append(warnings, warning)
+	}))
+
+	// the warning does not fail the request.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+	valid, errors := v.ValidateHttpRequest(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, "deprecated", warnings[0].WarningType)
+	assert.Equal(t, "/burgers", warnings[0].SpecPath)
+
+	valid, _ = v.ValidateHttpRequestSync(request)
+	assert.True(t, valid)
+	assert.Len(t, warnings, 2)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/1", nil)
+	valid, _ = v.ValidateHttpRequest(request)
+	assert.True(t, valid)
+	assert.Len(t, warnings, 2)
+}
+
+func TestNewValidator_ValidateHttpRequest_MetricsHandler(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                patties:
+                  type: integer
+                  maximum: 3`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	var stats []config.ValidationStats
+	v, _ := NewValidator(doc, config.WithMetricsHandler(func(s config.ValidationStats) {
+		stats = append(stats, s)
+	}))
+
+	body, _ := json.Marshal(map[string]interface{}{"patties": 5})
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", bytes.NewBuffer(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	valid, _ := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, http.MethodPost, stats[0].Method)
+	assert.Equal(t, "/burgers/createBurger", stats[0].Path)
+	assert.Equal(t, "/burgers/createBurger", stats[0].MatchedPath)
+	assert.Equal(t, 1, stats[0].PathCandidates) // the GET path does not define POST, so it is not compared.
+	assert.False(t, stats[0].Valid)
+	assert.Equal(t, 1, stats[0].Errors)
+	assert.GreaterOrEqual(t, stats[0].TotalDuration, stats[0].PathMatchDuration+stats[0].ValidationDuration)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/12", nil)
+	valid, _ = v.ValidateHttpRequestSync(request)
+	assert.True(t, valid)
+	assert.Len(t, stats, 2)
+	assert.True(t, stats[1].Valid)
+	assert.Equal(t, "/burgers/{id}", stats[1].MatchedPath)
+
+	// a missing path is still reported, without a matched path.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/fries", nil)
+	valid, _ = v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	assert.Len(t, stats, 3)
+	assert.Equal(t, "", stats[2].MatchedPath)
+	assert.Equal(t, 1, stats[2].Errors)
+}
+
+func TestNewValidator_Reload(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                patties:
+                  type: integer
+                  maximum: 3`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v, _ := NewValidator(doc)
+
+	validate := func(path string) (bool, []*liberrors.ValidationError) {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com"+path,
+			bytes.NewBufferString(`{"patties": 5}`))
+		request.Header.Set("Content-Type", "application/json")
+		return v.ValidateHttpRequest(request)
+	}
+
+	// the schema is cached by the first validation.
+	valid, errors := validate("/burgers/createBurger")
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+
+	reloaded, _ := libopenapi.NewDocument([]byte(strings.ReplaceAll(
+		strings.ReplaceAll(spec, "maximum: 3", "maximum: 5"), "createBurger", "makeBurger")))
+	assert.Nil(t, v.Reload(reloaded))
+
+	valid, errors = validate("/burgers/makeBurger")
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+
+	valid, errors = validate("/burgers/createBurger")
+	assert.False(t, valid)
+	assert.True(t, errors[0].IsPathMissingError())
+
+	// a document that cannot be built leaves the validator as it was.
+	broken, _ := libopenapi.NewDocument([]byte(`swagger: 2.0`))
+	assert.NotNil(t, v.Reload(broken))
+	valid, _ = validate("/burgers/makeBurger")
+	assert.True(t, valid)
+
+	// reset rebuilds from the model, after it has been modified in place.
+	v.Reset()
+	valid, _ = validate("/burgers/makeBurger")
+	assert.True(t, valid)
+}
+
+func TestNewValidator_Reload_WhileValidating(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                patties:
+                  type: integer
+                  maximum: 3`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v, _ := NewValidator(doc)
+	reloaded, _ := libopenapi.NewDocument([]byte(strings.ReplaceAll(spec, "maximum: 3", "maximum: 5")))
+
+	// run with -race, requests are validated against one document or the other, never a mix of both.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+				bytes.NewBufferString(`{"patties": 2}`))
+			request.Header.Set("Content-Type", "application/json")
+			valid, errors := v.ValidateHttpRequest(request)
+			assert.True(t, valid)
+			assert.Len(t, errors, 0)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		if i%2 == 0 {
+			assert.Nil(t, v.Reload(reloaded))
+		} else {
+			v.Reset()
+		}
+	}
+	wg.Wait()
+}
+
+func TestNewValidator_Reset_ConnectOperation(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /tunnels:
+    connect:
+      parameters:
+        - name: X-Tunnel-Token
+          in: header
+          required: true
+          schema:
+            type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v, _ := NewValidator(doc)
+
+	request, _ := http.NewRequest(http.MethodConnect, "https://things.com/tunnels", nil)
+
+	// the CONNECT operation is built from the path item node, and cached, by the first validation.
+	valid, errors := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+
+	// make the header optional, in the node the operation is built from.
+	m, _ := doc.BuildV3Model()
+	root := m.Model.Paths.PathItems.GetOrZero("/tunnels").GoLow().RootNode
+	param := root.Content[1].Content[1].Content[0]
+	for i := 0; i+1 < len(param.Content); i += 2 {
+		if param.Content[i].Value == "required" {
+			param.Content[i+1].Value = "false"
+		}
+	}
+
+	valid, _ = v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+
+	v.Reset()
+	valid, errors = v.ValidateHttpRequest(request)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestNewValidator_ValidationErrorDirection(t *testing.T) {
+
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  patties:
+                    type: integer`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	v, _ := NewValidator(doc)
+
+	// the client sent a bad path parameter.
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/big", nil)
+	valid, errors := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "request", errors[0].Direction)
+	assert.True(t, errors[0].IsClientError())
+	assert.False(t, errors[0].IsServerError())
+	assert.Equal(t, http.StatusBadRequest, liberrors.ProblemStatus(errors[0]))
+
+	// the server responded with a bad body.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/1", nil)
+	response := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"patties":"two"}`)),
+	}
+	valid, errors = v.ValidateHttpResponse(request, response)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "response", errors[0].Direction)
+	assert.True(t, errors[0].IsServerError())
+	assert.Equal(t, http.StatusBadGateway, liberrors.ProblemStatus(errors[0]))
+
+	// the request path is not in the contract, which is still the fault of the request.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/pizza", nil)
+	valid, errors = v.ValidateHttpResponse(request, response)
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.True(t, errors[0].IsClientError())
+}
+
+func TestValidateDocument_Findings(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: integer
+          example: big
+        - name: sauceId
+          in: path
+          required: true
+          schema:
+            type: string
+  /burgers/{id}:
+    get:
+      responses:
+        "200":
+          description: a burger
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Burger'
+              example:
+                patties: two
+  /burgers/latest:
+    get:
+      responses:
+        "200":
+          description: the latest burger
+components:
+  schemas:
+    Burger:
+      type: object
+      properties:
+        patties:
+          type: integer
+          default: lots
+        sauce:
+          type: string
+          enum: [ketchup, 7]`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	findings := ValidateDocument(&m.Model)
+	var subTypes []string
+	for _, finding := range findings {
+		subTypes = append(subTypes, finding.ValidationSubType)
+		assert.Greater(t, finding.SpecLine, 0, finding.Message)
+	}
+	assert.Equal(t, []string{
+		helpers.ParameterValidationPath, // sauceId is not in the template.
+		helpers.ParameterValidationPath, // id is not declared.
+		helpers.SchemaExampleInvalid,    // the burgerId example is not an integer.
+		helpers.SchemaDefaultInvalid,    // the patties default is not an integer.
+		helpers.SchemaExampleInvalid,    // the response example has a string for patties.
+		helpers.SchemaEnumValueInvalid,  // 7 is not a string.
+		helpers.PathAmbiguous,           // /burgers/{id} can never be matched.
+		helpers.PathOverlapping,         // /burgers/latest is matched by /burgers/{burgerId} first.
+		helpers.PathOverlapping,         // and it overlaps /burgers/{id} as well.
+	}, subTypes)
+
+	assert.Equal(t, "Path parameter 'sauceId' is not part of the path template", findings[0].Message)
+	assert.Equal(t, 12, findings[0].SpecLine)
+	assert.Equal(t, "Path parameter 'id' is not declared", findings[1].Message)
+	assert.Equal(t, 11, findings[2].SpecLine)
+	assert.Equal(t, 40, findings[3].SpecLine)
+	assert.Equal(t, 27, findings[4].SpecLine)
+	assert.Equal(t, "Path '/burgers/{id}' is ambiguous with path '/burgers/{burgerId}'", findings[6].Message)
+	assert.Equal(t, 17, findings[6].SpecLine)
+	assert.Equal(t, "Path '/burgers/latest' overlaps with path '/burgers/{burgerId}'", findings[7].Message)
+	for _, finding := range findings[:7] {
+		assert.Equal(t, SeverityError, finding.Severity)
+	}
+	assert.Equal(t, SeverityWarning, findings[7].Severity)
+	assert.Equal(t, SeverityWarning, findings[8].Severity)
+
+	// the finding marshals as a validation error, with a severity.
+	encoded, err := json.Marshal(findings[7])
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"severity":"warning"`)
+	assert.Contains(t, string(encoded), `"validationSubType":"overlappingPath"`)
+}
+
+func TestValidateDocument_Consistent(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    parameters:
+      - name: burgerId
+        in: path
+        required: true
+        schema:
+          type: integer
+        examples:
+          first:
+            value: 1
+    get:
+      responses:
+        "200":
+          description: a burger
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  patties:
+                    type: integer
+                    default: 2
+              examples:
+                double:
+                  value:
+                    patties: 2`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	assert.Empty(t, ValidateDocument(&m.Model))
+	assert.Nil(t, ValidateDocument(nil))
+}
+
+func TestNewValidator_ValidateHttpRequestForOperation(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      operationId: getBurger
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: integer
+  /burgers/latest:
+    get:
+      operationId: getLatestBurger`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	v, _ := NewValidator(doc)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/12", nil)
+	valid, errors := v.ValidateHttpRequestForOperation(request, "getBurger")
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/big", nil)
+	valid, errors = v.ValidateHttpRequestForOperation(request, "getBurger")
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "Path parameter 'burgerId' is not a valid number", errors[0].Message)
+
+	// the latest burger is matched by the path of getBurger first, but it is validated against the operation.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/latest", nil)
+	valid, errors = v.ValidateHttpRequestForOperation(request, "getLatestBurger")
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	// the path of the request must match the path of the operation.
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers/12", nil)
+	valid, errors = v.ValidateHttpRequestForOperation(request, "getLatestBurger")
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "GET Path '/burgers/12' does not match the path '/burgers/latest' of the operation "+
+		"'getLatestBurger'", errors[0].Message)
+	assert.True(t, errors[0].IsPathMissingError())
+
+	// as must the method.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/burgers/12", nil)
+	valid, errors = v.ValidateHttpRequestForOperation(request, "getBurger")
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "POST request does not match the GET operation 'getBurger'", errors[0].Message)
+	assert.Equal(t, helpers.RequestMissingOperation, errors[0].ValidationSubType)
+	assert.Equal(t, 5, errors[0].SpecLine)
+	assert.Equal(t, "request", errors[0].Direction)
+
+	valid, errors = v.ValidateHttpRequestForOperation(request, "eatBurger")
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "Operation 'eatBurger' not found", errors[0].Message)
+	assert.Equal(t, helpers.RequestUnknownOperationId, errors[0].ValidationSubType)
+	assert.Equal(t, http.StatusBadRequest, liberrors.ProblemStatus(errors[0]))
+}